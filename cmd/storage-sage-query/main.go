@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/user"
 	"text/tabwriter"
+	"time"
 
+	"storage-sage/internal/accounts"
 	"storage-sage/internal/database"
 	"storage-sage/internal/exitcodes"
 )
@@ -22,6 +25,24 @@ func main() {
 	pathPattern := flag.String("path", "", "Filter by path pattern (SQL LIKE syntax)")
 	largest := flag.Int("largest", 0, "Show N largest deletions")
 	days := flag.Int("days", 30, "Number of days for statistics (default: 30)")
+	byOwner := flag.Bool("by-owner", false, "Show bytes freed per owning user over --days")
+	byGroup := flag.Bool("by-group", false, "Show bytes freed per owning group over --days")
+	pending := flag.Bool("pending", false, "Show deletions waiting out the grace period")
+	reprieves := flag.Bool("reprieves", false, "Show active reprieves")
+	reprieve := flag.String("reprieve", "", "Exempt a file or directory from deletion")
+	unreprieve := flag.String("unreprieve", "", "Lift the reprieve on a file or directory")
+	note := flag.String("note", "", "Reason recorded with --reprieve, --hold or --release-hold")
+	reprieveHours := flag.Int("reprieve-hours", 0, "Expire the --reprieve after N hours (default: until lifted)")
+	quarantine := flag.Bool("quarantine", false, "Show paths whose deletes are suspended after a permanent failure")
+	release := flag.String("release", "", "Release a path from quarantine so the next cycle retries it")
+	scanErrors := flag.Bool("scan-errors", false, "Show what the last cycle's scan could not read")
+	holds := flag.Bool("holds", false, "Show active legal holds (with --all, every hold ever placed)")
+	allHolds := flag.Bool("all", false, "Include released and expired holds in --holds")
+	hold := flag.String("hold", "", "Place a legal hold on an absolute path or glob (needs --owner and --note)")
+	holdOwner := flag.String("owner", "", "Owner recorded with --hold")
+	holdUntil := flag.String("hold-until", "", "Expire the --hold at this date (YYYY-MM-DD or RFC 3339; default: until released)")
+	releaseHold := flag.Int64("release-hold", 0, "Release the legal hold with this ID (--note is audited)")
+	holdAudit := flag.Bool("hold-audit", false, "Show the legal hold audit trail")
 	jsonOutput := flag.Bool("json", false, "Output in JSON format")
 	flag.Parse()
 
@@ -50,6 +71,32 @@ func main() {
 		showByPath(db, *pathPattern, *jsonOutput)
 	case *largest > 0:
 		showLargest(db, *largest, *jsonOutput)
+	case *byOwner:
+		showByOwner(db, "user", *days, *jsonOutput)
+	case *byGroup:
+		showByOwner(db, "group", *days, *jsonOutput)
+	case *pending:
+		showPending(db, *jsonOutput)
+	case *reprieves:
+		showReprieves(db, *jsonOutput)
+	case *reprieve != "":
+		addReprieve(db, *reprieve, *note, *reprieveHours)
+	case *unreprieve != "":
+		removeReprieve(db, *unreprieve)
+	case *quarantine:
+		showQuarantine(db, *jsonOutput)
+	case *release != "":
+		releaseQuarantine(db, *release)
+	case *scanErrors:
+		showScanErrors(db, *jsonOutput)
+	case *holds:
+		showHolds(db, *allHolds, *jsonOutput)
+	case *hold != "":
+		placeHold(db, *hold, *holdOwner, *note, *holdUntil)
+	case *releaseHold > 0:
+		releaseLegalHold(db, *releaseHold, *note)
+	case *holdAudit:
+		showHoldAudit(db, *jsonOutput)
 	default:
 		flag.Usage()
 		fmt.Println("\nExamples:")
@@ -59,6 +106,16 @@ func main() {
 		fmt.Println("  storage-sage-query --action DELETE       # Show only deletions")
 		fmt.Println("  storage-sage-query --path '/var/log/%'   # Show deletions from /var/log")
 		fmt.Println("  storage-sage-query --largest 10          # Show 10 largest deletions")
+		fmt.Println("  storage-sage-query --by-owner --days 30  # Show bytes freed per user")
+		fmt.Println("  storage-sage-query --by-group --days 30  # Show bytes freed per group")
+		fmt.Println("  storage-sage-query --pending             # Show files waiting out the grace period")
+		fmt.Println("  storage-sage-query --reprieve /scratch/run1 --note 'paper deadline' --reprieve-hours 72")
+		fmt.Println("  storage-sage-query --unreprieve /scratch/run1")
+		fmt.Println("  storage-sage-query --quarantine          # Show paths that failed with EACCES, EPERM or EROFS")
+		fmt.Println("  storage-sage-query --release /scratch/run1/locked.dat")
+		fmt.Println("  storage-sage-query --scan-errors         # Show subtrees the last scan could not read")
+		fmt.Println("  storage-sage-query --hold '/data/projects/acme' --owner legal@example.com --note 'case 2026-114'")
+		fmt.Println("  storage-sage-query --release-hold 3 --note 'case closed'")
 		os.Exit(exitcodes.InvalidConfig)
 	}
 }
@@ -177,6 +234,323 @@ func showLargest(db *database.DeletionDB, limit int, jsonOutput bool) {
 	printRecords(records)
 }
 
+func showByOwner(db *database.DeletionDB, kind string, days int, jsonOutput bool) {
+	end := time.Now()
+	start := end.AddDate(0, 0, -days)
+
+	resolver := accounts.Default()
+	var usage []database.OwnerUsage
+	var err error
+	if kind == "group" {
+		usage, err = db.GetBytesFreedByGroup(start, end)
+		for i := range usage {
+			usage[i].Name = resolver.GroupName(uint32(usage[i].ID))
+		}
+	} else {
+		usage, err = db.GetBytesFreedByOwner(start, end)
+		for i := range usage {
+			usage[i].Name = resolver.UserName(uint32(usage[i].ID))
+		}
+	}
+	if err != nil {
+		log.Fatalf("ERROR: Failed to aggregate by %s: %v", kind, err)
+	}
+
+	if jsonOutput {
+		data, _ := json.MarshalIndent(usage, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	fmt.Printf("Bytes freed by %s (Last %d days)\n\n", kind, days)
+	if len(usage) == 0 {
+		fmt.Println("No records found")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "Name\tID\tFiles\tFreed")
+	_, _ = fmt.Fprintln(w, "----\t--\t-----\t-----")
+	for _, u := range usage {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", u.Name, u.ID, u.Files, formatBytes(u.Bytes))
+	}
+	_ = w.Flush()
+}
+
+func showPending(db *database.DeletionDB, jsonOutput bool) {
+	pending, err := db.GetPendingDeletions(0)
+	if err != nil {
+		log.Fatalf("ERROR: Failed to get pending deletions: %v", err)
+	}
+
+	if jsonOutput {
+		data, _ := json.MarshalIndent(pending, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	if len(pending) == 0 {
+		fmt.Println("No pending deletions")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "Due\tSize\tRule\tPath")
+	_, _ = fmt.Fprintln(w, "---\t----\t----\t----")
+	for _, p := range pending {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+			p.DueAt.Format("2006-01-02 15:04:05"), formatBytes(p.Size), p.PathRule, p.Path)
+	}
+	_ = w.Flush()
+}
+
+func showReprieves(db *database.DeletionDB, jsonOutput bool) {
+	reprieves, err := db.GetReprieves(time.Now())
+	if err != nil {
+		log.Fatalf("ERROR: Failed to get reprieves: %v", err)
+	}
+
+	if jsonOutput {
+		data, _ := json.MarshalIndent(reprieves, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	if len(reprieves) == 0 {
+		fmt.Println("No active reprieves")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "Path\tBy\tExpires\tReason")
+	_, _ = fmt.Fprintln(w, "----\t--\t-------\t------")
+	for _, r := range reprieves {
+		expires := "never"
+		if r.ExpiresAt != nil {
+			expires = r.ExpiresAt.Format("2006-01-02 15:04:05")
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Path, r.ReprievedBy, expires, r.Reason)
+	}
+	_ = w.Flush()
+}
+
+func addReprieve(db *database.DeletionDB, path, note string, hours int) {
+	r := database.Reprieve{Path: path, Reason: note, ReprievedBy: currentUser()}
+	if hours > 0 {
+		expires := time.Now().Add(time.Duration(hours) * time.Hour)
+		r.ExpiresAt = &expires
+	}
+	if err := db.AddReprieve(r); err != nil {
+		log.Fatalf("ERROR: Failed to reprieve %s: %v", path, err)
+	}
+	fmt.Printf("Reprieved %s\n", path)
+}
+
+func removeReprieve(db *database.DeletionDB, path string) {
+	removed, err := db.RemoveReprieve(path)
+	if err != nil {
+		log.Fatalf("ERROR: Failed to lift reprieve on %s: %v", path, err)
+	}
+	if !removed {
+		fmt.Printf("No reprieve on %s\n", path)
+		os.Exit(exitcodes.RuntimeError)
+	}
+	fmt.Printf("Lifted reprieve on %s\n", path)
+}
+
+func showQuarantine(db *database.DeletionDB, jsonOutput bool) {
+	entries, err := db.GetQuarantine()
+	if err != nil {
+		log.Fatalf("ERROR: Failed to get quarantine: %v", err)
+	}
+
+	if jsonOutput {
+		data, _ := json.MarshalIndent(entries, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No quarantined paths")
+		return
+	}
+
+	now := time.Now()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "Until	Errno	Failures	Path")
+	_, _ = fmt.Fprintln(w, "-----	-----	--------	----")
+	for _, q := range entries {
+		until := q.Until.Format("2006-01-02 15:04:05")
+		if !q.Active(now) {
+			until = "expired"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", until, q.Errno, q.Failures, q.Path)
+	}
+	_ = w.Flush()
+}
+
+func releaseQuarantine(db *database.DeletionDB, path string) {
+	released, err := db.ReleaseQuarantine(path)
+	if err != nil {
+		log.Fatalf("ERROR: Failed to release %s: %v", path, err)
+	}
+	if !released {
+		fmt.Printf("%s is not quarantined\n", path)
+		os.Exit(exitcodes.RuntimeError)
+	}
+	fmt.Printf("Released %s from quarantine\n", path)
+}
+
+func showScanErrors(db *database.DeletionDB, jsonOutput bool) {
+	runs, err := db.GetRecentRuns(1)
+	if err != nil {
+		log.Fatalf("ERROR: Failed to get last cycle: %v", err)
+	}
+	if len(runs) == 0 {
+		fmt.Println("No cycles recorded")
+		return
+	}
+	run := runs[0]
+	entries, err := db.GetScanErrors(run.ID)
+	if err != nil {
+		log.Fatalf("ERROR: Failed to get scan errors: %v", err)
+	}
+
+	if jsonOutput {
+		data, _ := json.MarshalIndent(map[string]interface{}{"run": run, "scan_errors": entries}, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	fmt.Printf("Cycle #%d at %s\n", run.ID, run.StartedAt.Format("2006-01-02 15:04:05"))
+	if run.ScanErrors == 0 {
+		fmt.Println("The scan read every entry")
+		return
+	}
+	fmt.Printf("Unreadable entries: %d (about %s below unreadable directories)\n", run.ScanErrors, formatBytes(run.SkippedBytes))
+	if run.Message != "" {
+		fmt.Println(run.Message)
+	}
+	if len(entries) < run.ScanErrors {
+		fmt.Printf("Showing the first %d entries\n", len(entries))
+	}
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "Rule\tKind\tEstimate\tPath")
+	_, _ = fmt.Fprintln(w, "----\t----\t--------\t----")
+	for _, e := range entries {
+		estimate := "-"
+		if e.IsDir {
+			estimate = formatBytes(e.EstimatedBytes)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.PathRule, e.Kind, estimate, e.Path)
+	}
+	_ = w.Flush()
+}
+
+func showHolds(db *database.DeletionDB, all, jsonOutput bool) {
+	holds, err := db.GetHolds(time.Now(), all)
+	if err != nil {
+		log.Fatalf("ERROR: Failed to get legal holds: %v", err)
+	}
+
+	if jsonOutput {
+		data, _ := json.MarshalIndent(holds, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	if len(holds) == 0 {
+		fmt.Println("No legal holds")
+		return
+	}
+
+	now := time.Now()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tState\tOwner\tPlaced By\tExpires\tPattern\tReason")
+	_, _ = fmt.Fprintln(w, "--\t-----\t-----\t---------\t-------\t-------\t------")
+	for _, h := range holds {
+		state := "active"
+		switch {
+		case h.ReleasedAt != nil:
+			state = "released"
+		case !h.Active(now):
+			state = "expired"
+		}
+		expires := "never"
+		if h.ExpiresAt != nil {
+			expires = h.ExpiresAt.Format("2006-01-02 15:04:05")
+		}
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", h.ID, state, h.Owner, h.CreatedBy, expires, h.Pattern, h.Reason)
+	}
+	_ = w.Flush()
+}
+
+func placeHold(db *database.DeletionDB, pattern, owner, note, until string) {
+	h := database.LegalHold{Pattern: pattern, Owner: owner, Reason: note, CreatedBy: currentUser()}
+	if until != "" {
+		expires, err := time.ParseInLocation("2006-01-02", until, time.Local)
+		if err != nil {
+			if expires, err = time.Parse(time.RFC3339, until); err != nil {
+				log.Fatalf("ERROR: --hold-until must be YYYY-MM-DD or RFC 3339: %q", until)
+			}
+		}
+		h.ExpiresAt = &expires
+	}
+	id, err := db.PlaceHold(h)
+	if err != nil {
+		log.Fatalf("ERROR: Failed to place legal hold on %s: %v", pattern, err)
+	}
+	fmt.Printf("Placed legal hold #%d on %s\n", id, pattern)
+}
+
+func releaseLegalHold(db *database.DeletionDB, id int64, note string) {
+	released, err := db.ReleaseHold(id, currentUser(), note, time.Now())
+	if err != nil {
+		log.Fatalf("ERROR: Failed to release legal hold #%d: %v", id, err)
+	}
+	if !released {
+		fmt.Printf("No active legal hold #%d\n", id)
+		os.Exit(exitcodes.RuntimeError)
+	}
+	fmt.Printf("Released legal hold #%d\n", id)
+}
+
+func showHoldAudit(db *database.DeletionDB, jsonOutput bool) {
+	events, err := db.GetHoldEvents(0)
+	if err != nil {
+		log.Fatalf("ERROR: Failed to get legal hold audit trail: %v", err)
+	}
+
+	if jsonOutput {
+		data, _ := json.MarshalIndent(events, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	if len(events) == 0 {
+		fmt.Println("No legal hold events")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "Time\tHold\tAction\tBy\tDetail")
+	_, _ = fmt.Fprintln(w, "----\t----\t------\t--\t------")
+	for _, e := range events {
+		_, _ = fmt.Fprintf(w, "%s\t#%d\t%s\t%s\t%s\n", e.At.Format("2006-01-02 15:04:05"), e.HoldID, e.Action, e.Actor, e.Detail)
+	}
+	_ = w.Flush()
+}
+
+// currentUser names the caller for the reprieve and legal hold audit trails
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return fmt.Sprintf("uid:%d", os.Getuid())
+}
+
 func printRecords(records []database.DeletionRecord) {
 	if len(records) == 0 {
 		fmt.Println("No records found")
@@ -194,8 +568,12 @@ func printRecords(records []database.DeletionRecord) {
 		if r.FileName != "" {
 			fullPath = fullPath + "/" + r.FileName
 		}
+		reason := r.PrimaryReason
+		if r.Errno != "" {
+			reason = r.Errno
+		}
 		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n",
-			r.ID, timestamp, r.Action, r.PrimaryReason, size, fullPath)
+			r.ID, timestamp, r.Action, reason, size, fullPath)
 	}
 	_ = w.Flush()
 }
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"storage-sage/internal/control"
+	"storage-sage/internal/database"
+	"storage-sage/internal/scheduler"
+)
+
+// daemonControl adapts the scheduler to the control API. Reload shares the
+// channel used by SIGHUP; triggers are queued as scheduler requests so they
+// can carry a rule scope and a dry-run override.
+type daemonControl struct {
+	requests chan scheduler.TriggerRequest
+	reload   chan os.Signal
+	db       *database.DeletionDB
+	dryRun   bool
+}
+
+func (d *daemonControl) Trigger(req control.TriggerRequest) error {
+	sreq := scheduler.TriggerRequest{Rules: cleanRules(req.Rules), DryRun: req.DryRun}
+	if err := scheduler.ValidateTrigger(sreq); err != nil {
+		return controlError(err)
+	}
+	select {
+	case d.requests <- sreq:
+		return nil
+	default:
+		return control.ErrBusy
+	}
+}
+
+func (d *daemonControl) Reload() error {
+	select {
+	case d.reload <- syscall.SIGHUP:
+		return nil
+	default:
+		return control.ErrBusy
+	}
+}
+
+func (d *daemonControl) Pause(req control.PauseRequest) error {
+	if len(req.Rules) == 0 {
+		return controlError(scheduler.Pause(d.db, "", req.Reason, req.By))
+	}
+	for _, rule := range cleanRules(req.Rules) {
+		if err := scheduler.Pause(d.db, rule, req.Reason, req.By); err != nil {
+			return controlError(err)
+		}
+	}
+	return nil
+}
+
+func (d *daemonControl) Resume(req control.PauseRequest) error {
+	if len(req.Rules) == 0 {
+		return controlError(scheduler.Resume(d.db, ""))
+	}
+	for _, rule := range cleanRules(req.Rules) {
+		if err := scheduler.Resume(d.db, rule); err != nil {
+			return controlError(err)
+		}
+	}
+	return nil
+}
+
+func (d *daemonControl) Override(req control.OverrideRequest) error {
+	scheduler.ArmOverride(req.Reason, req.By)
+	return nil
+}
+
+func (d *daemonControl) Status() control.Status {
+	st := scheduler.CurrentState()
+	status := control.Status{
+		PID:         os.Getpid(),
+		DryRun:      d.dryRun,
+		Paused:      st.Paused,
+		PauseReason: st.PauseReason,
+		Running:     st.Running,
+		Interval:    st.Interval.String(),
+		LastRun:     st.LastRun,
+		LastError:   st.LastError,
+		NextRun:     st.NextRun,
+		Rules:       make([]control.RuleStatus, 0, len(st.Rules)),
+
+		OverrideArmed: st.OverrideArmed,
+		OverrideBy:    st.OverrideBy,
+	}
+	for _, r := range st.Rules {
+		status.Rules = append(status.Rules, control.RuleStatus{
+			Path:     r.Path,
+			Paused:   r.Paused,
+			Reason:   r.Reason,
+			PausedBy: r.PausedBy,
+			PausedAt: r.PausedAt,
+		})
+	}
+	return status
+}
+
+// cleanRules normalizes rule paths the same way the config loader does
+func cleanRules(rules []string) []string {
+	cleaned := make([]string, 0, len(rules))
+	for _, r := range rules {
+		cleaned = append(cleaned, filepath.Clean(r))
+	}
+	return cleaned
+}
+
+// controlError maps scheduler errors to the control API's error values
+func controlError(err error) error {
+	switch {
+	case errors.Is(err, scheduler.ErrPaused):
+		return control.ErrPaused
+	case errors.Is(err, scheduler.ErrUnknownRule):
+		return control.ErrUnknownRule
+	}
+	return err
+}
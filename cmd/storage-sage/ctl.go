@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"storage-sage/internal/config"
+	"storage-sage/internal/control"
+	"storage-sage/internal/exitcodes"
+)
+
+const defaultControlSocket = "/run/storage-sage/control.sock"
+
+// runCtl implements `storage-sage ctl <command>` against a running daemon
+func runCtl(args []string) int {
+	fs := flag.NewFlagSet("ctl", flag.ContinueOnError)
+	configPath := fs.String("config", "/etc/storage-sage/config.yaml", "Config file used to locate the control socket")
+	addr := fs.String("addr", "", "Control socket path or http(s) URL (overrides the config)")
+	tokenFile := fs.String("token-file", "", "Bearer token file for TCP control listeners")
+	jsonOutput := fs.Bool("json", false, "Print status as JSON")
+	var rules ruleList
+	fs.Var(&rules, "rule", "Limit trigger/pause/resume to this rule path (repeatable)")
+	dryRun := fs.Bool("dry-run", false, "Run the triggered cycle as a dry run")
+	reason := fs.String("reason", "", "Reason recorded with a pause or override")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: storage-sage ctl [flags] trigger|reload|pause|resume|override|status")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitcodes.InvalidConfig
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return exitcodes.InvalidConfig
+	}
+
+	target, token, err := ctlTarget(*configPath, *addr, *tokenFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		return exitcodes.InvalidConfig
+	}
+	client, err := control.NewClient(target, token)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		return exitcodes.InvalidConfig
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	switch cmd := fs.Arg(0); cmd {
+	case "trigger":
+		err = client.Trigger(ctx, control.TriggerRequest{Rules: rules, DryRun: *dryRun})
+	case "reload":
+		err = client.Reload(ctx)
+	case "pause":
+		err = client.Pause(ctx, control.PauseRequest{Rules: rules, Reason: *reason})
+	case "resume":
+		err = client.Resume(ctx, control.PauseRequest{Rules: rules})
+	case "override":
+		err = client.Override(ctx, control.OverrideRequest{Reason: *reason})
+	case "status":
+		var st control.Status
+		if st, err = client.Status(ctx); err == nil {
+			printStatus(st, *jsonOutput)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "ERROR: unknown command %q\n", cmd)
+		fs.Usage()
+		return exitcodes.InvalidConfig
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %s: %v\n", fs.Arg(0), err)
+		return exitcodes.RuntimeError
+	}
+	if fs.Arg(0) != "status" {
+		fmt.Printf("%s: ok\n", fs.Arg(0))
+	}
+	return exitcodes.Success
+}
+
+// ruleList collects repeated -rule flags
+type ruleList []string
+
+func (r *ruleList) String() string { return strings.Join(*r, ",") }
+
+func (r *ruleList) Set(v string) error {
+	*r = append(*r, v)
+	return nil
+}
+
+// ctlTarget picks the control address and token: explicit flags win, then the
+// daemon config, then the default socket
+func ctlTarget(configPath, addr, tokenFile string) (string, string, error) {
+	var cfg *config.Config
+	if addr == "" || tokenFile == "" {
+		if loaded, err := config.Load(configPath); err == nil {
+			cfg = loaded
+		}
+	}
+
+	if addr == "" {
+		addr = defaultControlSocket
+		if cfg != nil {
+			addr = cfg.Control.Socket
+		}
+	}
+	remote := strings.HasPrefix(addr, "http://") || strings.HasPrefix(addr, "https://")
+	if tokenFile == "" && remote && cfg != nil {
+		tokenFile = cfg.Control.TokenFile
+	}
+	if tokenFile == "" {
+		return addr, "", nil
+	}
+	token, err := control.ReadToken(tokenFile)
+	if err != nil {
+		return "", "", err
+	}
+	return addr, token, nil
+}
+
+func printStatus(st control.Status, jsonOutput bool) {
+	if jsonOutput {
+		data, _ := json.MarshalIndent(st, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+	state := "idle"
+	switch {
+	case st.Running:
+		state = "running"
+	case st.Paused:
+		state = "paused"
+	}
+	fmt.Printf("PID:        %d\n", st.PID)
+	fmt.Printf("State:      %s\n", state)
+	fmt.Printf("Paused:     %t\n", st.Paused)
+	if st.PauseReason != "" {
+		fmt.Printf("Reason:     %s\n", st.PauseReason)
+	}
+	fmt.Printf("Dry run:    %t\n", st.DryRun)
+	fmt.Printf("Interval:   %s\n", st.Interval)
+	fmt.Printf("Last run:   %s\n", formatTime(st.LastRun))
+	fmt.Printf("Next run:   %s\n", formatTime(st.NextRun))
+	if st.LastError != "" {
+		fmt.Printf("Last error: %s\n", st.LastError)
+	}
+	if st.OverrideArmed {
+		fmt.Printf("Override:   armed by %s\n", st.OverrideBy)
+	}
+	if len(st.Rules) == 0 {
+		return
+	}
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RULE\tSTATE\tSINCE\tBY\tREASON")
+	for _, r := range st.Rules {
+		if !r.Paused {
+			fmt.Fprintf(w, "%s\tactive\t\t\t\n", r.Path)
+			continue
+		}
+		fmt.Fprintf(w, "%s\tpaused\t%s\t%s\t%s\n", r.Path, formatTime(r.PausedAt), r.PausedBy, r.Reason)
+	}
+	w.Flush()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Local().Format(time.RFC3339)
+}
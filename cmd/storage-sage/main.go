@@ -2,25 +2,36 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"storage-sage/internal/cleanup"
 	"storage-sage/internal/config"
+	"storage-sage/internal/control"
 	"storage-sage/internal/database"
 	"storage-sage/internal/exitcodes"
 	"storage-sage/internal/logging"
 	"storage-sage/internal/metrics"
+	"storage-sage/internal/sandbox"
 	"storage-sage/internal/scheduler"
 )
 
 func main() {
+	// `storage-sage ctl ...` is a client for a running daemon
+	if len(os.Args) > 1 && os.Args[1] == "ctl" {
+		os.Exit(runCtl(os.Args[2:]))
+	}
+
 	// Parse command-line flags
 	configPath := flag.String("config", "/etc/storage-sage/config.yaml", "Path to configuration file")
 	dryRun := flag.Bool("dry-run", false, "Perform dry run without deleting files")
 	once := flag.Bool("once", false, "Run cleanup once and exit (no loop)")
+	overrideLimits := flag.Bool("override-limits", false, "With --once, let this run exceed the configured blast_radius limits")
 	flag.Parse()
 
 	// Initialize logger
@@ -63,6 +74,34 @@ func main() {
 		}()
 	}
 
+	// Confine deletes to the configured roots with Landlock (opt-in)
+	var sb *sandbox.Sandbox
+	applySandbox := func(cfg *config.Config) {
+		if !cfg.Sandbox.Enabled {
+			if sb != nil {
+				logger.Println("WARNING: sandbox.enabled was turned off; the existing ruleset stays in force until restart")
+			}
+			return
+		}
+		if sb == nil {
+			sb = sandbox.New()
+			scheduler.SetSandbox(sb)
+		}
+		status := sb.Apply(sandbox.RulesFromConfig(cfg, *configPath, logging.Dir()))
+		metrics.SetHealthInfo("sandbox", status)
+		if status.Enforced {
+			logger.Printf("Landlock sandbox enforced (ABI v%d) for %d roots", status.ABI, len(status.Roots))
+		} else {
+			logger.Printf("WARNING: Landlock sandbox not enforced, continuing unconfined: %s", status.Error)
+		}
+	}
+	applySandbox(cfg)
+	defer func() {
+		if sb != nil {
+			sb.Close()
+		}
+	}()
+
 	// Create context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -76,18 +115,83 @@ func main() {
 		cancel()
 	}()
 
+	// Restore pauses set through the control API before the first cycle
+	if err := scheduler.RestorePauses(cfg, db); err != nil {
+		logger.Printf("ERROR: Failed to restore pause state: %v", err)
+		os.Exit(exitcodes.RuntimeError)
+	}
+	if scheduler.Paused() {
+		logger.Println("WARNING: cleanup is paused; resume with `storage-sage ctl resume`")
+	}
+
+	// SIGUSR1 runs a cycle now, SIGHUP reloads the config; the control API's
+	// reload requests feed the same channel and its triggers are queued on
+	// requestChan with an optional rule scope
+	triggerChan := make(chan os.Signal, 1)
+	reloadChan := make(chan os.Signal, 1)
+	requestChan := make(chan scheduler.TriggerRequest, 1)
+	signal.Notify(triggerChan, syscall.SIGUSR1)
+	signal.Notify(reloadChan, syscall.SIGHUP)
+
+	// Start the authenticated control API (used by `storage-sage ctl` and the web backend)
+	if !*once {
+		controlOpts := control.Options{
+			Socket:    cfg.Control.Socket,
+			AllowUIDs: cfg.Control.AllowUIDs,
+			AllowGIDs: cfg.Control.AllowGIDs,
+			Listen:    cfg.Control.Listen,
+		}
+		if cfg.Control.TokenFile != "" {
+			token, err := control.ReadToken(cfg.Control.TokenFile)
+			if err != nil {
+				logger.Printf("ERROR: %v", err)
+				os.Exit(exitcodes.InvalidConfig)
+			}
+			controlOpts.Token = token
+		}
+		daemon := &daemonControl{requests: requestChan, reload: reloadChan, db: db, dryRun: *dryRun}
+		controlSrv := control.NewServer(daemon, controlOpts, logger)
+		if err := controlSrv.Start(); err != nil {
+			logger.Printf("WARNING: control API: %v", err)
+		}
+		defer func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := controlSrv.Shutdown(shutdownCtx); err != nil {
+				logger.Printf("ERROR: control API shutdown: %v", err)
+			}
+		}()
+	}
+
 	// Run scheduler
 	logger.Println("Starting cleanup scheduler...")
 	if *once {
 		// Run once and exit
+		if *overrideLimits {
+			logger.Println("WARNING: blast-radius limits overridden for this run")
+			scheduler.ArmOverride("--override-limits", fmt.Sprintf("uid:%d", os.Getuid()))
+		}
 		if err := scheduler.RunOnceWithDB(ctx, cfg, *dryRun, logger, db); err != nil {
 			logger.Printf("ERROR: Cleanup failed: %v", err)
+			if errors.Is(err, cleanup.ErrCircuitBreaker) {
+				os.Exit(exitcodes.SafetyViolation)
+			}
 			os.Exit(exitcodes.RuntimeError)
 		}
 		logger.Println("Cleanup completed successfully")
 	} else {
 		// Run continuously with database support
-		if err := scheduler.RunWithDB(ctx, cfg, *dryRun, logger, db); err != nil && err != context.Canceled {
+		signals := scheduler.Signals{
+			Trigger:  triggerChan,
+			Requests: requestChan,
+			Reload:   reloadChan,
+			Load: func() (*config.Config, error) {
+				logger.Printf("Reloading config from %s", *configPath)
+				return config.Load(*configPath)
+			},
+			OnReload: applySandbox,
+		}
+		if err := scheduler.RunWithSignals(ctx, cfg, *dryRun, logger, db, signals); err != nil && err != context.Canceled {
 			logger.Printf("ERROR: Scheduler failed: %v", err)
 			os.Exit(exitcodes.RuntimeError)
 		}
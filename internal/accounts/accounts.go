@@ -0,0 +1,123 @@
+// Package accounts maps numeric uids and gids to names using the local
+// /etc/passwd and /etc/group files.
+package accounts
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultPasswdPath = "/etc/passwd"
+	DefaultGroupPath  = "/etc/group"
+)
+
+// Resolver caches uid/gid to name mappings and reloads them when the
+// underlying files change. Unknown ids resolve to their decimal form so
+// deleted accounts still show up in reports.
+type Resolver struct {
+	passwdPath string
+	groupPath  string
+
+	mu     sync.Mutex
+	users  idTable
+	groups idTable
+}
+
+type idTable struct {
+	names   map[uint32]string
+	modTime time.Time
+}
+
+var (
+	defaultResolver     *Resolver
+	defaultResolverOnce sync.Once
+)
+
+// NewResolver creates a resolver reading the given passwd and group files
+func NewResolver(passwdPath, groupPath string) *Resolver {
+	return &Resolver{passwdPath: passwdPath, groupPath: groupPath}
+}
+
+// Default returns a shared resolver for the system account files
+func Default() *Resolver {
+	defaultResolverOnce.Do(func() {
+		defaultResolver = NewResolver(DefaultPasswdPath, DefaultGroupPath)
+	})
+	return defaultResolver
+}
+
+// UserName returns the login name for uid
+func (r *Resolver) UserName(uid uint32) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.users.lookup(r.passwdPath, uid)
+}
+
+// GroupName returns the group name for gid
+func (r *Resolver) GroupName(gid uint32) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.groups.lookup(r.groupPath, gid)
+}
+
+func (t *idTable) lookup(path string, id uint32) string {
+	t.refresh(path)
+	if name, ok := t.names[id]; ok {
+		return name
+	}
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+// refresh reloads the table when the file's mtime has changed
+func (t *idTable) refresh(path string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return // keep whatever we loaded last
+	}
+	if t.names != nil && info.ModTime().Equal(t.modTime) {
+		return
+	}
+	names, err := parseIDFile(path)
+	if err != nil {
+		return
+	}
+	t.names = names
+	t.modTime = info.ModTime()
+}
+
+// parseIDFile reads a passwd(5) or group(5) style file. Both formats carry
+// the name in the first field and the numeric id in the third.
+func parseIDFile(path string) (map[uint32]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	names := make(map[uint32]string)
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == '#' || line[0] == '+' || line[0] == '-' {
+			continue // comments and NIS compat entries
+		}
+		fields := strings.SplitN(line, ":", 4)
+		if len(fields) < 3 || fields[0] == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(fields[2], 10, 32)
+		if err != nil {
+			continue
+		}
+		// First entry wins, matching getpwuid/getgrgid
+		if _, exists := names[uint32(id)]; !exists {
+			names[uint32(id)] = fields[0]
+		}
+	}
+	return names, sc.Err()
+}
@@ -0,0 +1,50 @@
+package accounts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolver(t *testing.T) {
+	dir := t.TempDir()
+	passwd := filepath.Join(dir, "passwd")
+	group := filepath.Join(dir, "group")
+
+	if err := os.WriteFile(passwd, []byte(
+		"# comment\n"+
+			"root:x:0:0:root:/root:/bin/bash\n"+
+			"alice:x:1001:2000:Alice:/home/alice:/bin/bash\n"+
+			"alias:x:1001:2000::/home/alias:/bin/sh\n"+
+			"+nisuser::::::\n"+
+			"broken:x:notanumber:0::/:/bin/false\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(group, []byte("root:x:0:\nresearch:x:2000:alice\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewResolver(passwd, group)
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"root user", r.UserName(0), "root"},
+		{"first entry wins", r.UserName(1001), "alice"},
+		{"unknown uid", r.UserName(4242), "4242"},
+		{"group", r.GroupName(2000), "research"},
+		{"unknown gid", r.GroupName(7), "7"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+
+	missing := NewResolver(filepath.Join(dir, "nope"), filepath.Join(dir, "nope"))
+	if got := missing.UserName(5); got != "5" {
+		t.Errorf("missing passwd file: got %q, want %q", got, "5")
+	}
+}
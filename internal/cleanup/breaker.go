@@ -0,0 +1,245 @@
+package cleanup
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"storage-sage/internal/config"
+	"storage-sage/internal/metrics"
+	"storage-sage/internal/scan"
+)
+
+// ErrCircuitBreaker is returned when a cycle would exceed its blast-radius
+// limits. Nothing has been deleted when it is returned.
+var ErrCircuitBreaker = errors.New("circuit breaker tripped")
+
+// globalScope labels trips of the top-level blast_radius limits
+const globalScope = "*"
+
+// BreakerTrip is one blast-radius limit a cycle would exceed
+type BreakerTrip struct {
+	Rule   string // Rule path, or "*" for the global limits
+	Limit  string // max_files, max_bytes or max_fraction
+	Detail string // e.g. "1200 > 1000"
+}
+
+// BreakerError lists every limit a cycle would exceed; it matches ErrCircuitBreaker
+type BreakerError struct {
+	Trips []BreakerTrip
+}
+
+func (e *BreakerError) Error() string {
+	parts := make([]string, 0, len(e.Trips))
+	for _, t := range e.Trips {
+		parts = append(parts, fmt.Sprintf("%s %s %s", t.Rule, t.Limit, t.Detail))
+	}
+	return fmt.Sprintf("%v: %s", ErrCircuitBreaker, strings.Join(parts, "; "))
+}
+
+func (e *BreakerError) Unwrap() error {
+	return ErrCircuitBreaker
+}
+
+// SetFileCounts gives the breaker the number of files scanned under each
+// rule root, which max_fraction is measured against
+func (c *Cleaner) SetFileCounts(counts map[string]int64) {
+	c.fileCounts = counts
+}
+
+// SetBreakerOverride lets the next cleanup run past the blast-radius limits
+func (c *Cleaner) SetBreakerOverride(override bool) {
+	c.override = override
+}
+
+// OverrideUsed reports whether a cleanup exceeded its limits under an override
+func (c *Cleaner) OverrideUsed() bool {
+	return c.overrideUsed
+}
+
+// usage is what a cycle would delete under one rule
+type usage struct {
+	entries int
+	files   int64 // non-directory entries, comparable with the scanned file count
+	bytes   int64
+}
+
+// checkBlastRadius compares candidates against the global and per-rule limits.
+// A directory removeTree would delete counts as everything below it, so one
+// old directory cannot take a whole tree past the limits. When a limit is
+// exceeded and no override is set, one SKIP is recorded per affected rule and
+// a *BreakerError is returned before anything is deleted.
+func (c *Cleaner) checkBlastRadius(cfg *config.Config, candidates []scan.Candidate) error {
+	if cfg == nil {
+		return nil
+	}
+
+	trees := make(map[string]bool)
+	if cfg.CleanupOptions.Recursive && cfg.CleanupOptions.DeleteDirs {
+		for _, cand := range candidates {
+			if cand.IsDir && !cand.IsEmptyDir {
+				trees[cand.Path] = true
+			}
+		}
+	}
+
+	byRule := make(map[string]*usage)
+	var total usage
+	for _, cand := range candidates {
+		// Compressing a file keeps its data; only deletes count against the blast radius
+		if cand.DeletionReason.Compress != nil {
+			continue
+		}
+		// Counted with the directory removeTree takes it down with
+		if withinTree(cand.Path, trees) {
+			continue
+		}
+		cu := usage{entries: 1, bytes: cand.Size}
+		if !cand.IsDir {
+			cu.files = 1
+		}
+		if trees[cand.Path] {
+			cu = subtreeUsage(cand.Path)
+		}
+
+		rule := cand.DeletionReason.PathRule
+		u := byRule[rule]
+		if u == nil {
+			u = &usage{}
+			byRule[rule] = u
+		}
+		for _, acc := range []*usage{u, &total} {
+			acc.entries += cu.entries
+			acc.files += cu.files
+			acc.bytes += cu.bytes
+		}
+	}
+
+	var scanned int64
+	for _, n := range c.fileCounts {
+		scanned += n
+	}
+
+	trips := exceeded(globalScope, cfg.BlastRadius, total, scanned)
+	for _, rule := range cfg.Paths {
+		if u := byRule[rule.Path]; u != nil {
+			trips = append(trips, exceeded(rule.Path, rule.BlastRadius, *u, c.fileCounts[rule.Path])...)
+		}
+	}
+	if len(trips) == 0 {
+		metrics.SetCircuitBreaker(nil)
+		return nil
+	}
+
+	breakerErr := &BreakerError{Trips: trips}
+	if c.override {
+		c.overrideUsed = true
+		metrics.SetCircuitBreaker(nil)
+		c.logger.Info("Circuit breaker overridden for this cycle", "limits", breakerErr.Error())
+		return nil
+	}
+
+	// Record the abort against each affected rule root; a global trip affects every rule with candidates
+	affected := make(map[string][]string)
+	for _, t := range trips {
+		detail := fmt.Sprintf("%s %s", t.Limit, t.Detail)
+		if t.Rule != globalScope {
+			affected[t.Rule] = append(affected[t.Rule], detail)
+			continue
+		}
+		for rule := range byRule {
+			affected[rule] = append(affected[rule], "global "+detail)
+		}
+	}
+	roots := make([]string, 0, len(affected))
+	for rule := range affected {
+		roots = append(roots, rule)
+	}
+	sort.Strings(roots)
+
+	tripped := make([]string, 0, len(trips))
+	for _, t := range trips {
+		tripped = append(tripped, t.Rule)
+	}
+	metrics.SetCircuitBreaker(tripped)
+
+	now := time.Now()
+	for _, root := range roots {
+		u := byRule[root]
+		detail := strings.Join(affected[root], ", ")
+		c.logStructured("SKIP", root, "circuit_breaker", u.bytes, detail)
+		if c.db != nil {
+			marker := scan.Candidate{
+				Path:           root,
+				Size:           u.bytes,
+				IsDir:          true,
+				DeletionReason: scan.DeletionReason{PathRule: root, EvaluatedAt: now},
+			}
+			if err := c.db.RecordDeletion("SKIP", marker, "circuit_breaker: "+detail); err != nil {
+				c.logger.Error("Failed to record circuit breaker to database", "error", err)
+			}
+		}
+	}
+	c.logger.Error("Circuit breaker tripped, aborting cleanup before any delete", "limits", breakerErr.Error())
+	return breakerErr
+}
+
+// withinTree reports whether one of the ancestors of path is in trees
+func withinTree(path string, trees map[string]bool) bool {
+	if len(trees) == 0 {
+		return false
+	}
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		if trees[dir] {
+			return true
+		}
+		if dir == filepath.Dir(dir) {
+			return false
+		}
+	}
+}
+
+// subtreeUsage counts what removeTree would delete for the directory dir:
+// every entry, the non-directories among them and their sizes. Symlinks are
+// counted, not followed, and unreadable directories count as far as they can
+// be read.
+func subtreeUsage(dir string) usage {
+	var u usage
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		u.entries++
+		if d.IsDir() {
+			return nil
+		}
+		u.files++
+		if info, err := d.Info(); err == nil {
+			u.bytes += info.Size()
+		}
+		return nil
+	})
+	return u
+}
+
+// exceeded returns the limits in b that u would exceed. scanned is the number
+// of files the rule holds; max_fraction is not checked when it is unknown.
+func exceeded(rule string, b config.BlastRadius, u usage, scanned int64) []BreakerTrip {
+	var trips []BreakerTrip
+	if b.MaxFiles > 0 && u.entries > b.MaxFiles {
+		trips = append(trips, BreakerTrip{rule, "max_files", fmt.Sprintf("%d > %d", u.entries, b.MaxFiles)})
+	}
+	if b.MaxBytes > 0 && u.bytes > b.MaxBytes {
+		trips = append(trips, BreakerTrip{rule, "max_bytes", fmt.Sprintf("%d > %d", u.bytes, b.MaxBytes)})
+	}
+	if b.MaxFraction > 0 && scanned > 0 {
+		if frac := float64(u.files) / float64(scanned); frac > b.MaxFraction {
+			trips = append(trips, BreakerTrip{rule, "max_fraction", fmt.Sprintf("%.3f > %.3f (%d of %d files)", frac, b.MaxFraction, u.files, scanned)})
+		}
+	}
+	return trips
+}
@@ -0,0 +1,172 @@
+package cleanup
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"storage-sage/internal/config"
+	"storage-sage/internal/database"
+	"storage-sage/internal/fsops"
+	"storage-sage/internal/safety"
+	"storage-sage/internal/scan"
+)
+
+// TestCircuitBreaker proves a cycle over its blast radius deletes nothing
+func TestCircuitBreaker(t *testing.T) {
+	root := t.TempDir()
+	other := t.TempDir()
+
+	var candidates []scan.Candidate
+	for _, name := range []string{"a", "b", "c", "d"} {
+		candidates = append(candidates, scan.Candidate{
+			Path:           filepath.Join(root, name),
+			Size:           100,
+			DeletionReason: scan.DeletionReason{PathRule: root},
+		})
+	}
+	candidates = append(candidates, scan.Candidate{
+		Path:           filepath.Join(other, "e"),
+		Size:           100,
+		DeletionReason: scan.DeletionReason{PathRule: other},
+	})
+
+	tests := []struct {
+		name     string
+		global   config.BlastRadius
+		rule     config.BlastRadius
+		counts   map[string]int64
+		override bool
+		tripped  []string // rule roots expected in SKIP rows; nil means the cycle runs
+	}{
+		{"no limits", config.BlastRadius{}, config.BlastRadius{}, nil, false, nil},
+		{"within limits", config.BlastRadius{MaxFiles: 5, MaxBytes: 500}, config.BlastRadius{MaxFiles: 4}, nil, false, nil},
+		{"rule max_files", config.BlastRadius{}, config.BlastRadius{MaxFiles: 3}, nil, false, []string{root}},
+		{"rule max_bytes", config.BlastRadius{}, config.BlastRadius{MaxBytes: 399}, nil, false, []string{root}},
+		{"rule max_fraction", config.BlastRadius{}, config.BlastRadius{MaxFraction: 0.5}, map[string]int64{root: 5, other: 1}, false, []string{root}},
+		{"fraction within", config.BlastRadius{}, config.BlastRadius{MaxFraction: 0.5}, map[string]int64{root: 10, other: 1}, false, nil},
+		{"global max_files", config.BlastRadius{MaxFiles: 4}, config.BlastRadius{}, nil, false, []string{other, root}},
+		{"global max_fraction", config.BlastRadius{MaxFraction: 0.2}, config.BlastRadius{}, map[string]int64{root: 10, other: 10}, false, []string{other, root}},
+		{"override", config.BlastRadius{MaxFiles: 1}, config.BlastRadius{MaxFiles: 1}, nil, true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := database.NewDeletionDB(filepath.Join(t.TempDir(), "deletions.db"))
+			if err != nil {
+				t.Fatalf("NewDeletionDB: %v", err)
+			}
+			defer db.Close()
+
+			cfg := &config.Config{
+				ScanPaths:   []string{other},
+				Paths:       []config.PathRule{{Path: root, BlastRadius: tt.rule}},
+				BlastRadius: tt.global,
+			}
+			fake := &fsops.FakeDeleter{}
+			cleaner := NewCleaner(log.Default(), nil, false, db)
+			cleaner.SetDeleter(fake)
+			cleaner.SetValidator(safety.NewValidator([]string{root, other}, nil))
+			cleaner.SetFileCounts(tt.counts)
+			cleaner.SetBreakerOverride(tt.override)
+
+			count, _, err := cleaner.CleanupWithConfig(cfg, append([]scan.Candidate(nil), candidates...))
+			skips, dbErr := db.GetDeletionsByAction("SKIP")
+			if dbErr != nil {
+				t.Fatalf("GetDeletionsByAction: %v", dbErr)
+			}
+
+			if tt.tripped == nil {
+				if err != nil {
+					t.Fatalf("CleanupWithConfig: %v", err)
+				}
+				if count != len(candidates) || len(fake.Calls) != len(candidates) {
+					t.Errorf("deleted %d (%d calls), want %d", count, len(fake.Calls), len(candidates))
+				}
+				if cleaner.OverrideUsed() != tt.override {
+					t.Errorf("OverrideUsed = %t, want %t", cleaner.OverrideUsed(), tt.override)
+				}
+				return
+			}
+
+			var breakerErr *BreakerError
+			if !errors.Is(err, ErrCircuitBreaker) || !errors.As(err, &breakerErr) {
+				t.Fatalf("CleanupWithConfig error = %v, want ErrCircuitBreaker", err)
+			}
+			if count != 0 || len(fake.Calls) != 0 {
+				t.Errorf("breaker tripped but %d deletes were issued: %v", len(fake.Calls), fake.Calls)
+			}
+			var got []string
+			for _, s := range skips {
+				if !strings.HasPrefix(s.ErrorMessage, "circuit_breaker: ") {
+					t.Errorf("SKIP %s message = %q, want circuit_breaker prefix", s.Path, s.ErrorMessage)
+				}
+				got = append(got, s.Path)
+			}
+			want := append([]string(nil), tt.tripped...)
+			sort.Strings(got)
+			sort.Strings(want)
+			if strings.Join(got, ",") != strings.Join(want, ",") {
+				t.Errorf("SKIP rows for %v, want %v", got, tt.tripped)
+			}
+		})
+	}
+}
+
+// TestCircuitBreakerCountsTrees proves one old directory counts as every entry
+// removeTree would take with it, not as a single entry
+func TestCircuitBreakerCountsTrees(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "run1")
+	if err := os.MkdirAll(filepath.Join(dir, "out"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		if err := os.WriteFile(filepath.Join(dir, "out", fmt.Sprintf("part-%d", i)), make([]byte, 100), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dirCand := scan.Candidate{Path: dir, IsDir: true, Size: 4096, DeletionReason: scan.DeletionReason{PathRule: root}}
+	// A file inside the tree is selected too; it must not be counted twice
+	fileCand := scan.Candidate{Path: filepath.Join(dir, "out", "part-0"), Size: 100, DeletionReason: scan.DeletionReason{PathRule: root}}
+
+	tests := []struct {
+		name    string
+		limits  config.BlastRadius
+		tripped bool
+	}{
+		{"max_files", config.BlastRadius{MaxFiles: 5}, true},
+		{"max_bytes", config.BlastRadius{MaxBytes: 999}, true},
+		{"max_fraction", config.BlastRadius{MaxFraction: 0.5}, true},
+		{"within limits", config.BlastRadius{MaxFiles: 12, MaxBytes: 1000}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Paths:          []config.PathRule{{Path: root, BlastRadius: tt.limits}},
+				CleanupOptions: config.CleanupOptions{Recursive: true, DeleteDirs: true},
+			}
+			fake := &fsops.FakeDeleter{}
+			cleaner := NewCleaner(log.Default(), nil, false, nil)
+			cleaner.SetDeleter(fake)
+			cleaner.SetValidator(safety.NewValidator([]string{root}, nil))
+			cleaner.SetFileCounts(map[string]int64{root: 12})
+
+			_, _, err := cleaner.CleanupWithConfig(cfg, []scan.Candidate{dirCand, fileCand})
+			if tt.tripped {
+				if !errors.Is(err, ErrCircuitBreaker) || len(fake.Calls) != 0 {
+					t.Fatalf("CleanupWithConfig = %v with %d deletes, want the breaker to trip first", err, len(fake.Calls))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CleanupWithConfig: %v", err)
+			}
+		})
+	}
+}
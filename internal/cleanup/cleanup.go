@@ -1,6 +1,8 @@
 package cleanup
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -8,10 +10,12 @@ import (
 	"strings"
 	"time"
 
+	"storage-sage/internal/accounts"
 	"storage-sage/internal/config"
 	"storage-sage/internal/database"
 	"storage-sage/internal/disk"
 	"storage-sage/internal/fsops"
+	"storage-sage/internal/limiter"
 	"storage-sage/internal/metrics"
 	"storage-sage/internal/safety"
 	"storage-sage/internal/scan"
@@ -70,6 +74,7 @@ func (m *cleanupMetrics) ErrorsTotal() prometheus.Counter {
 
 // Cleaner performs cleanup operations with structured logging
 type Cleaner struct {
+	ctx       context.Context // Cycle context; cancelled at shutdown or reload
 	logger    CleanupLogger
 	metrics   Metrics
 	logFile   *os.File // Optional file for structured logging
@@ -77,8 +82,27 @@ type Cleaner struct {
 	db        *database.DeletionDB // Database for recording deletion history
 	validator *safety.Validator    // Safety validator for all delete operations
 	deleter   fsops.Deleter        // Filesystem deleter (real or fake)
+	limiter   *limiter.Limiter     // Optional CPU and I/O budget applied before each delete
+	paused    PauseCheck           // Optional pause state consulted before each change
+	holds     HoldRefresh          // Optional reload of legal holds placed during the cycle
+
+	fileCounts   map[string]int64 // Files scanned per rule root, for blast_radius.max_fraction
+	override     bool             // Run past the blast-radius limits this cycle
+	overrideUsed bool             // The limits were exceeded and the override let the cycle through
+
+	retries       int                                 // Retries of transient failures per delete
+	backoff       time.Duration                       // Delay before the first retry
+	quarantineFor time.Duration                       // How long permanent failures are left alone
+	quarantined   map[string]database.QuarantineEntry // Quarantine list loaded at the start of the cycle
 }
 
+// PauseCheck reports whether changes at path are paused right now, and by what
+type PauseCheck func(path string) (detail string, paused bool)
+
+// HoldRefresh reloads the validator's legal holds if any were placed or
+// released since the last call, and reports whether they changed
+type HoldRefresh func() (changed bool, err error)
+
 // NewCleaner creates a new Cleaner instance
 func NewCleaner(logger *log.Logger, logFile *os.File, dryRun bool, db *database.DeletionDB) *Cleaner {
 	cleanupLogger := &cleanupStdLogger{Logger: logger}
@@ -86,6 +110,7 @@ func NewCleaner(logger *log.Logger, logFile *os.File, dryRun bool, db *database.
 		cleanupLogger.Logger = log.Default()
 	}
 	return &Cleaner{
+		ctx:       context.Background(),
 		logger:    cleanupLogger,
 		metrics:   &cleanupMetrics{},
 		logFile:   logFile,
@@ -106,6 +131,36 @@ func (c *Cleaner) SetDeleter(d fsops.Deleter) {
 	c.deleter = d
 }
 
+// SetContext sets the cycle context that interrupts budget waits and retry backoff
+func (c *Cleaner) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+// context returns the cycle context, or a background one for a Cleaner built without NewCleaner
+func (c *Cleaner) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// SetPauseCheck sets the pause state consulted before each delete, compression
+// or truncation, so a pause takes effect within a running cycle
+func (c *Cleaner) SetPauseCheck(check PauseCheck) {
+	c.paused = check
+}
+
+// SetHoldRefresh sets how legal holds placed while the cycle runs are picked
+// up; it is called before each delete, compression or truncation
+func (c *Cleaner) SetHoldRefresh(refresh HoldRefresh) {
+	c.holds = refresh
+}
+
+// SetLimiter sets the CPU and I/O budget applied before each delete
+func (c *Cleaner) SetLimiter(l *limiter.Limiter) {
+	c.limiter = l
+}
+
 // incrementErrorsTotal safely increments the errors metric if metrics are initialized
 func (c *Cleaner) incrementErrorsTotal() {
 	if c.metrics != nil {
@@ -193,34 +248,31 @@ func (c *Cleaner) CleanupWithConfig(cfg *config.Config, candidates []scan.Candid
 		c.logger.Info("Validator not set - using legacy path checking only")
 	}
 
+	// Paths that failed permanently are left alone until their quarantine expires
+	c.setRetryPolicy(cfg.CleanupOptions)
+	candidates, quarantinedCount := c.dropQuarantined(candidates)
+
+	// Abort before the first delete if the cycle would exceed its blast radius
+	if err := c.checkBlastRadius(cfg, candidates); err != nil {
+		return 0, 0, err
+	}
+
 	var totalSpaceFreed int64
 	successCount := 0
 	errorCount := 0
 
 	for _, cand := range candidates {
+		// Shutdown or reload ends the cycle here rather than after the remaining candidates
+		if err := c.context().Err(); err != nil {
+			return successCount, totalSpaceFreed, err
+		}
+
 		// SAFETY CONTRACT: Validate delete target through centralized validator
-		if c.validator != nil {
-			if err := c.validator.ValidateDeleteTarget(cand.Path); err != nil {
-				c.logStructured("SKIP", cand.Path, "safety_violation", 0, err.Error())
-				// Record safety violation to database
-				if c.db != nil {
-					_ = c.db.RecordDeletion("SKIP", cand, "safety_violation: "+err.Error())
-				}
-				c.incrementErrorsTotal()
-				errorCount++
-				continue
-			}
-		} else {
-			// Fallback to legacy path checking if validator not set (backward compat during transition)
-			if !withinAllowed(cand.Path, cfg) {
-				c.logStructured("SKIP", cand.Path, "unsafe_path", 0, "")
-				if c.db != nil {
-					_ = c.db.RecordDeletion("SKIP", cand, "unsafe_path")
-				}
-				c.incrementErrorsTotal()
+		if allowed, isError := c.checkTarget(cfg, cand); !allowed {
+			if isError {
 				errorCount++
-				continue
 			}
+			continue
 		}
 
 		// Check for stale NFS before attempting deletion
@@ -237,6 +289,60 @@ func (c *Cleaner) CleanupWithConfig(cfg *config.Config, candidates []scan.Candid
 			}
 		}
 
+		// Spread deletions out so the journal is not hit with a burst of unlinks
+		if !c.dryRun {
+			if err := c.limiter.WaitDelete(c.context(), cand.Size); err != nil {
+				return successCount, totalSpaceFreed, err
+			}
+		}
+
+		// The scan may be minutes old; make sure this is still the entry it selected and that it still qualifies
+		if ok, isError := c.verifyCandidate(cfg, &cand); !ok {
+			if isError {
+				errorCount++
+			}
+			continue
+		}
+
+		// A pause placed while the cycle runs stops it from touching the rule any further
+		if c.pausedNow(cand) {
+			continue
+		}
+
+		// So does a legal hold; a cycle that cannot read the holds stops here
+		if allowed, isError, err := c.holdsAllow(cfg, cand); err != nil {
+			return successCount, totalSpaceFreed, err
+		} else if !allowed {
+			if isError {
+				errorCount++
+			}
+			continue
+		}
+
+		// Compression keeps the data, so it is recorded apart from deletes
+		if cand.DeletionReason.Compress != nil && !cand.IsDir {
+			saved, ok, isError := c.compress(cfg, cand)
+			if ok {
+				successCount++
+				totalSpaceFreed += saved
+			} else if isError {
+				errorCount++
+			}
+			continue
+		}
+
+		// Truncation frees space in place, even while a process holds the file open
+		if cand.DeletionReason.Truncate != nil && !cand.IsDir {
+			freed, ok, isError := c.truncate(cfg, cand)
+			if ok {
+				successCount++
+				totalSpaceFreed += freed
+			} else if isError {
+				errorCount++
+			}
+			continue
+		}
+
 		var err error
 		objectType := "file"
 		deletionReason := ""
@@ -259,7 +365,7 @@ func (c *Cleaner) CleanupWithConfig(cfg *config.Config, candidates []scan.Candid
 					c.logger.Info("[DRY RUN] Would remove empty directory", "path", cand.Path)
 					// DRY-RUN CONTRACT: Never call deleter in dry-run mode
 				} else {
-					err = c.deleter.Remove(cand.Path)
+					err = c.remove(cand.Path)
 				}
 			} else {
 				objectType = "directory"
@@ -271,15 +377,21 @@ func (c *Cleaner) CleanupWithConfig(cfg *config.Config, candidates []scan.Candid
 					}
 					continue
 				}
+				if cfg.CleanupOptions.Recursive {
+					// Walk the subtree so every entry is validated, recorded and accounted for
+					removed, freed, failed := c.removeTree(cfg, cand)
+					successCount += removed
+					totalSpaceFreed += freed
+					if failed {
+						errorCount++
+					}
+					continue
+				}
 				if c.dryRun {
-					c.logger.Info("[DRY RUN] Would remove directory recursively", "path", cand.Path)
+					c.logger.Info("[DRY RUN] Would remove directory", "path", cand.Path)
 					// DRY-RUN CONTRACT: Never call deleter in dry-run mode
 				} else {
-					if cfg.CleanupOptions.Recursive {
-						err = c.deleter.RemoveAll(cand.Path)
-					} else {
-						err = c.deleter.Remove(cand.Path)
-					}
+					err = c.remove(cand.Path)
 				}
 			}
 		} else {
@@ -287,7 +399,7 @@ func (c *Cleaner) CleanupWithConfig(cfg *config.Config, candidates []scan.Candid
 				c.logger.Info("[DRY RUN] Would delete file", "path", cand.Path, "size", cand.Size)
 				// DRY-RUN CONTRACT: Never call deleter in dry-run mode
 			} else {
-				err = c.deleter.Remove(cand.Path)
+				err = c.remove(cand.Path)
 			}
 		}
 
@@ -304,6 +416,25 @@ func (c *Cleaner) CleanupWithConfig(cfg *config.Config, candidates []scan.Candid
 				continue
 			}
 
+			// An ancestor was swapped for a symlink after validation; the deleter refused to follow it
+			if errors.Is(err, safety.ErrSymlinkAncestor) {
+				c.logStructured("SKIP", cand.Path, "safety_violation", 0, err.Error())
+				if c.db != nil {
+					_ = c.db.RecordDeletion("SKIP", cand, "safety_violation: "+err.Error())
+				}
+				c.incrementErrorsTotal()
+				errorCount++
+				continue
+			}
+
+			// The rule root could not be opened this cycle; nothing below it is touched
+			if errors.Is(err, safety.ErrRootUnavailable) {
+				c.skip(cand, "root_unavailable", err.Error())
+				c.incrementErrorsTotal()
+				errorCount++
+				continue
+			}
+
 			// Don't count "file not found" errors as real errors - these are expected in race conditions
 			// when multiple cleanup criteria match the same file and it gets deleted twice
 			if os.IsNotExist(err) {
@@ -312,15 +443,7 @@ func (c *Cleaner) CleanupWithConfig(cfg *config.Config, candidates []scan.Candid
 				continue
 			}
 
-			c.logger.Error("Failed to delete", "path", cand.Path, "error", err)
-			c.logStructured("ERROR", cand.Path, objectType, cand.Size, deletionReason)
-			// Record error to database
-			if c.db != nil {
-				if dbErr := c.db.RecordDeletion("ERROR", cand, err.Error()); dbErr != nil {
-					c.logger.Error("Failed to record error to database", "error", dbErr)
-				}
-			}
-			c.incrementErrorsTotal()
+			c.recordFailure(cand, err)
 			errorCount++
 			continue
 		}
@@ -343,18 +466,13 @@ func (c *Cleaner) CleanupWithConfig(cfg *config.Config, candidates []scan.Candid
 
 		totalSpaceFreed += cand.Size
 		successCount++
-
-		// Update Prometheus metrics
-		c.incrementFilesProcessed()
-		c.addSpaceFreed(cand.Size)
-
-		// Record path-specific deletion metrics (Section 7.2)
-		metrics.RecordPathDeletion(cand.Path, cand.Size)
+		c.accountDeletion(cand)
 	}
 
 	c.logger.Info("Cleanup complete",
 		"success", successCount,
 		"errors", errorCount,
+		"quarantined", quarantinedCount,
 		"space_freed_bytes", totalSpaceFreed,
 		"space_freed_mb", totalSpaceFreed/1024/1024,
 	)
@@ -362,6 +480,101 @@ func (c *Cleaner) CleanupWithConfig(cfg *config.Config, candidates []scan.Candid
 	return successCount, totalSpaceFreed, nil
 }
 
+// pausedNow records a SKIP and returns true when changes at cand are paused.
+// Dry runs change nothing, so they are never paused.
+func (c *Cleaner) pausedNow(cand scan.Candidate) bool {
+	if c.dryRun || c.paused == nil {
+		return false
+	}
+	detail, paused := c.paused(cand.Path)
+	if !paused {
+		return false
+	}
+	c.skip(cand, "paused", detail)
+	return true
+}
+
+// holdsAllow reloads the legal holds if they changed during the cycle and, if
+// so, validates cand again so a new hold covering it is honoured
+func (c *Cleaner) holdsAllow(cfg *config.Config, cand scan.Candidate) (allowed bool, isError bool, err error) {
+	if c.holds == nil {
+		return true, false, nil
+	}
+	changed, err := c.holds()
+	if err != nil || !changed {
+		return err == nil, false, err
+	}
+	allowed, isError = c.checkTarget(cfg, cand)
+	return allowed, isError, nil
+}
+
+// checkTarget runs the safety validator on cand and records a SKIP when it is denied.
+// isError is false when the denial comes from the protection policy, which is intended.
+func (c *Cleaner) checkTarget(cfg *config.Config, cand scan.Candidate) (allowed bool, isError bool) {
+	if c.validator == nil {
+		// Fallback to legacy path checking if validator not set (backward compat during transition)
+		if !withinAllowed(cand.Path, cfg) {
+			c.logStructured("SKIP", cand.Path, "unsafe_path", 0, "")
+			if c.db != nil {
+				_ = c.db.RecordDeletion("SKIP", cand, "unsafe_path")
+			}
+			c.incrementErrorsTotal()
+			return false, true
+		}
+		return true, false
+	}
+
+	err := c.validator.ValidateDeleteTarget(cand.Path)
+	if err == nil {
+		return true, false
+	}
+
+	// Policy denials are intended protections, not safety violations
+	var policyErr *safety.PolicyError
+	if errors.As(err, &policyErr) {
+		c.logStructured("SKIP", cand.Path, policyErr.Reason(), 0, policyErr.Detail)
+		if c.db != nil {
+			_ = c.db.RecordDeletion("SKIP", cand, policyErr.Reason()+": "+policyErr.Detail)
+		}
+		return false, false
+	}
+
+	// Legal holds are intended too; the SKIP names the hold so it shows in the audit
+	var holdErr *safety.HoldError
+	if errors.As(err, &holdErr) {
+		c.skip(cand, holdErr.Reason(), holdErr.Error())
+		return false, false
+	}
+
+	// Retention floors hold in every mode; a young entry reaching this point is kept, not an error
+	var floorErr *safety.RetentionError
+	if errors.As(err, &floorErr) {
+		c.skip(cand, floorErr.Reason(), floorErr.Error())
+		return false, false
+	}
+
+	c.logStructured("SKIP", cand.Path, "safety_violation", 0, err.Error())
+	// Record safety violation to database
+	if c.db != nil {
+		_ = c.db.RecordDeletion("SKIP", cand, "safety_violation: "+err.Error())
+	}
+	c.incrementErrorsTotal()
+	return false, true
+}
+
+// accountDeletion updates Prometheus metrics for one removed entry
+func (c *Cleaner) accountDeletion(cand scan.Candidate) {
+	c.incrementFilesProcessed()
+	c.addSpaceFreed(cand.Size)
+
+	// Record path-specific deletion metrics (Section 7.2)
+	metrics.RecordPathDeletion(cand.Path, cand.Size)
+	if cand.HasOwner {
+		metrics.RecordOwnerDeletion(accounts.Default().UserName(cand.UID), cand.Size)
+		metrics.RecordGroupDeletion(accounts.Default().GroupName(cand.GID), cand.Size)
+	}
+}
+
 // logStructured logs with structured format: timestamp, action, path, size, object type, deletion reason
 func (c *Cleaner) logStructured(action, path, objectType string, size int64, deletionReason string) {
 	logEntry := fmt.Sprintf("[%s] %s path=%s object=%s size=%d",
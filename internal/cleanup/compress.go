@@ -0,0 +1,74 @@
+package cleanup
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"storage-sage/internal/config"
+	"storage-sage/internal/fsops"
+	"storage-sage/internal/metrics"
+	"storage-sage/internal/scan"
+)
+
+// compress replaces a file selected by a compress tier with a copy in the
+// rule's format: the deleter links the copy next to it as path.gz or path.zst
+// and removes the original. It returns the bytes saved and whether the file
+// was compressed (or would be, in dry-run), after logging and recording the
+// outcome.
+func (c *Cleaner) compress(cfg *config.Config, cand scan.Candidate) (saved int64, ok bool, isError bool) {
+	reason := cand.DeletionReason.ToLogString()
+	if c.dryRun {
+		c.logger.Info("[DRY RUN] Would compress file", "path", cand.Path, "size", cand.Size)
+		// DRY-RUN CONTRACT: Never write or remove anything in dry-run mode
+		c.logStructured("DRY_RUN", cand.Path, "file", cand.Size, reason)
+		if c.db != nil {
+			if dbErr := c.db.RecordDeletion("DRY_RUN", cand, ""); dbErr != nil {
+				c.logger.Error("Failed to record to database", "error", dbErr)
+			}
+		}
+		return 0, true, false
+	}
+
+	opts := (&config.PathRule{}).CompressOptions()
+	if rule := scan.RuleFor(cfg, cand.DeletionReason.PathRule); rule != nil {
+		opts = rule.CompressOptions()
+	}
+	// Compress through the deleter, so the secure deleter's root fds and the sandbox confine it like a delete
+	z, canCompress := c.deleter.(fsops.Compressor)
+	if !canCompress {
+		c.recordFailure(cand, fmt.Errorf("%w: %T cannot compress", errors.ErrUnsupported, c.deleter))
+		return 0, false, true
+	}
+	dst, size, err := z.Compress(cand.Path, opts.Format, opts.Level)
+	if errors.Is(err, fsops.ErrOriginalNotRemoved) {
+		// Keep the original alone rather than leave both copies
+		_ = c.deleter.Remove(dst)
+	}
+	switch {
+	case err == nil:
+		c.release(cand.Path)
+	case os.IsNotExist(err):
+		c.logger.Info("File already deleted (race condition)", "path", cand.Path)
+		return 0, false, false
+	case errors.Is(err, fsops.ErrChangedWhileCompressing):
+		c.skip(cand, "changed_since_scan", err.Error())
+		return 0, false, false
+	case errors.Is(err, fsops.ErrCompressedExists):
+		c.skip(cand, "compressed_exists", err.Error())
+		return 0, false, false
+	default:
+		c.recordFailure(cand, err)
+		return 0, false, true
+	}
+
+	saved = cand.Size - size
+	c.logStructured("COMPRESS", cand.Path, "file", cand.Size, reason)
+	if c.db != nil {
+		if dbErr := c.db.RecordCompression(cand, size); dbErr != nil {
+			c.logger.Error("Failed to record to database", "error", dbErr)
+		}
+	}
+	metrics.RecordCompression(saved)
+	return saved, true, false
+}
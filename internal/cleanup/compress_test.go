@@ -0,0 +1,168 @@
+package cleanup
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"storage-sage/internal/config"
+	"storage-sage/internal/database"
+	"storage-sage/internal/fsops"
+	"storage-sage/internal/safety"
+	"storage-sage/internal/scan"
+)
+
+// TestCleanupCompress verifies compress candidates are replaced by a gzip copy
+// and recorded as COMPRESS with the compressed size, and that dry-run and the
+// blast radius leave them alone
+func TestCleanupCompress(t *testing.T) {
+	for _, dryRun := range []bool{false, true} {
+		name := "compress"
+		if dryRun {
+			name = "dry run"
+		}
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			rule := config.PathRule{
+				Path:        root,
+				Tiers:       []config.Tier{{UsagePercent: 0, MinAge: config.Duration(config.Day), Action: config.ActionCompress}},
+				BlastRadius: config.BlastRadius{MaxFiles: 1},
+			}
+			old := time.Now().Add(-3 * config.Day).Truncate(time.Second)
+			content := strings.Repeat("worker 7 finished job\n", 500)
+
+			var candidates []scan.Candidate
+			for _, name := range []string{"a.log", "b.log"} {
+				path := filepath.Join(root, name)
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.Chtimes(path, old, old); err != nil {
+					t.Fatal(err)
+				}
+				info, err := os.Lstat(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				reason := scan.DeletionReason{
+					PathRule: root,
+					Compress: &scan.CompressReason{Level: 1, MinAge: config.Day, Age: 3 * config.Day, Format: config.FormatGzip, ActualPercent: 40},
+				}
+				candidates = append(candidates, scan.CandidateFromInfo(path, info, reason))
+			}
+
+			db, err := database.NewDeletionDB(filepath.Join(t.TempDir(), "deletions.db"))
+			if err != nil {
+				t.Fatalf("NewDeletionDB: %v", err)
+			}
+			defer db.Close()
+
+			cleaner := NewCleaner(log.Default(), nil, dryRun, db)
+			cleaner.SetDeleter(fsops.OSDeleter{})
+			cleaner.SetValidator(safety.NewValidator([]string{root}, nil))
+			count, saved, err := cleaner.CleanupWithConfig(&config.Config{Paths: []config.PathRule{rule}}, candidates)
+			if err != nil {
+				t.Fatalf("CleanupWithConfig: %v", err)
+			}
+			if count != 2 {
+				t.Errorf("count = %d, want 2", count)
+			}
+
+			action := "COMPRESS"
+			if dryRun {
+				action = "DRY_RUN"
+			}
+			rows, err := db.GetDeletionsByAction(action)
+			if err != nil {
+				t.Fatalf("GetDeletionsByAction: %v", err)
+			}
+			if len(rows) != 2 {
+				t.Fatalf("%s rows = %d, want 2", action, len(rows))
+			}
+
+			for _, c := range candidates {
+				_, origErr := os.Lstat(c.Path)
+				gz, gzErr := os.Lstat(c.Path + ".gz")
+				if dryRun {
+					if origErr != nil || !os.IsNotExist(gzErr) || saved != 0 {
+						t.Errorf("dry run touched %s (saved %d)", c.Path, saved)
+					}
+					continue
+				}
+				if !os.IsNotExist(origErr) || gzErr != nil {
+					t.Fatalf("%s: original err %v, compressed err %v; want only the .gz", c.Path, origErr, gzErr)
+				}
+				if !gz.ModTime().Equal(old) {
+					t.Errorf("%s.gz mtime %v, want %v", c.Path, gz.ModTime(), old)
+				}
+			}
+			if dryRun {
+				return
+			}
+			for _, r := range rows {
+				if r.SizeAfter == nil || *r.SizeAfter >= r.Size || r.PrimaryReason != "compress" {
+					t.Errorf("row %+v, want size_after below size and primary reason compress", r)
+				}
+			}
+			if saved <= 0 {
+				t.Errorf("saved = %d, want bytes saved", saved)
+			}
+		})
+	}
+}
+
+// stuckCompressor links a copy but reports the original could not be removed
+type stuckCompressor struct {
+	fsops.OSDeleter
+}
+
+func (stuckCompressor) Compress(path, format string, level int) (string, int64, error) {
+	dst := path + fsops.GzipExt
+	if err := os.WriteFile(dst, []byte("copy"), 0644); err != nil {
+		return "", 0, err
+	}
+	return dst, 4, fmt.Errorf("%w: %s", fsops.ErrOriginalNotRemoved, path)
+}
+
+// TestCompressOriginalNotRemoved verifies the copy is removed, and the file
+// recorded as an error, when the original cannot be unlinked after the link
+func TestCompressOriginalNotRemoved(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "app.log")
+	if err := os.WriteFile(path, []byte("worker 7 finished job\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Lstat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reason := scan.DeletionReason{
+		PathRule: root,
+		Compress: &scan.CompressReason{Level: 1, MinAge: config.Day, Age: 3 * config.Day, Format: config.FormatGzip, ActualPercent: 40},
+	}
+	rule := config.PathRule{
+		Path:  root,
+		Tiers: []config.Tier{{UsagePercent: 0, MinAge: config.Duration(config.Day), Action: config.ActionCompress}},
+	}
+
+	cleaner := NewCleaner(log.Default(), nil, false, nil)
+	cleaner.SetDeleter(stuckCompressor{})
+	cleaner.SetValidator(safety.NewValidator([]string{root}, nil))
+	count, _, err := cleaner.CleanupWithConfig(&config.Config{Paths: []config.PathRule{rule}}, []scan.Candidate{scan.CandidateFromInfo(path, info, reason)})
+	if err != nil {
+		t.Fatalf("CleanupWithConfig: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("count = %d, want 0", count)
+	}
+	if _, err := os.Lstat(path); err != nil {
+		t.Errorf("original: %v, want it kept", err)
+	}
+	if _, err := os.Lstat(path + fsops.GzipExt); !os.IsNotExist(err) {
+		t.Errorf("copy: %v, want it removed", err)
+	}
+}
@@ -1,13 +1,17 @@
 package cleanup
 
 import (
+	"context"
+	"errors"
 	"log"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"storage-sage/internal/config"
 	"storage-sage/internal/fsops"
+	"storage-sage/internal/limiter"
 	"storage-sage/internal/metrics"
 	"storage-sage/internal/safety"
 	"storage-sage/internal/scan"
@@ -130,6 +134,74 @@ func TestRealModeCallsDeleter(t *testing.T) {
 	}
 }
 
+// TestCancelInterruptsBudgetWait proves a slow deletion budget does not hold
+// the cycle past shutdown or reload
+func TestCancelInterruptsBudgetWait(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := &config.Config{ScanPaths: []string{tmpDir}}
+	var candidates []scan.Candidate
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		candidates = append(candidates, scan.Candidate{Path: filepath.Join(tmpDir, name), Size: 4})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	fakeDeleter := &fsops.FakeDeleter{Calls: []string{}}
+	cleaner := NewCleaner(log.Default(), nil, false, nil)
+	cleaner.SetContext(ctx)
+	cleaner.SetDeleter(fakeDeleter)
+	cleaner.SetValidator(safety.NewValidator([]string{tmpDir}, nil))
+	cleaner.SetLimiter(limiter.New(limiter.Options{DeletionsPerSecond: 0.01}))
+
+	start := time.Now()
+	_, _, err := cleaner.CleanupWithConfig(cfg, candidates)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("CleanupWithConfig = %v, want the cycle context's error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("cleanup took %s after cancellation", elapsed)
+	}
+	if len(fakeDeleter.Calls) == len(candidates) {
+		t.Error("every candidate was deleted despite the budget")
+	}
+}
+
+// TestPauseStopsRunningCycle proves a pause placed while a cycle runs stops
+// the remaining deletes under the paused rule
+func TestPauseStopsRunningCycle(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := &config.Config{ScanPaths: []string{tmpDir}}
+	var candidates []scan.Candidate
+	for _, name := range []string{"a", "b", "c"} {
+		candidates = append(candidates, scan.Candidate{Path: filepath.Join(tmpDir, name), Size: 4})
+	}
+
+	fakeDeleter := &fsops.FakeDeleter{Calls: []string{}}
+	cleaner := NewCleaner(log.Default(), nil, false, nil)
+	cleaner.SetDeleter(fakeDeleter)
+	cleaner.SetValidator(safety.NewValidator([]string{tmpDir}, nil))
+	// The rule is paused once the first file is gone
+	cleaner.SetPauseCheck(func(path string) (string, bool) {
+		return "rule paused: incident", len(fakeDeleter.Calls) > 0
+	})
+
+	count, _, err := cleaner.CleanupWithConfig(cfg, candidates)
+	if err != nil {
+		t.Fatalf("CleanupWithConfig failed: %v", err)
+	}
+	if count != 1 || len(fakeDeleter.Calls) != 1 {
+		t.Errorf("deleted %d (%v), want only the file before the pause", count, fakeDeleter.Calls)
+	}
+
+	// Dry runs change nothing, so a pause does not stop them
+	dryRun := NewCleaner(log.Default(), nil, true, nil)
+	dryRun.SetValidator(safety.NewValidator([]string{tmpDir}, nil))
+	dryRun.SetPauseCheck(func(string) (string, bool) { return "paused", true })
+	if count, _, _ := dryRun.CleanupWithConfig(cfg, candidates); count != len(candidates) {
+		t.Errorf("dry run reported %d, want %d", count, len(candidates))
+	}
+}
+
 // TestSafetyValidatorBlocksDeletion proves validator integration works
 func TestSafetyValidatorBlocksDeletion(t *testing.T) {
 	tmpDir := t.TempDir()
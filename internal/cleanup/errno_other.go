@@ -0,0 +1,13 @@
+//go:build !unix
+
+package cleanup
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// errnoName returns the numeric errno; symbolic names are only available on unix
+func errnoName(e syscall.Errno) string {
+	return fmt.Sprintf("errno_%d", int(e))
+}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package cleanup
+
+import (
+	"fmt"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// errnoName returns the symbolic name of e, e.g. EACCES
+func errnoName(e syscall.Errno) string {
+	if name := unix.ErrnoName(e); name != "" {
+		return name
+	}
+	return fmt.Sprintf("errno_%d", int(e))
+}
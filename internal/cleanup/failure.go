@@ -0,0 +1,153 @@
+package cleanup
+
+import (
+	"errors"
+	"syscall"
+	"time"
+
+	"storage-sage/internal/config"
+	"storage-sage/internal/database"
+	"storage-sage/internal/metrics"
+	"storage-sage/internal/scan"
+)
+
+// Failure classes used in logs and the storagesage_delete_errors_total metric
+const (
+	failureTransient = "transient" // worth retrying within the cycle
+	failurePermanent = "permanent" // will fail again until someone intervenes; quarantined
+	failureOther     = "other"     // logged and retried next cycle, as before
+)
+
+// classifyError returns the errno name behind err and its failure class
+func classifyError(err error) (errno, class string) {
+	var e syscall.Errno
+	if !errors.As(err, &e) {
+		return "unknown", failureOther
+	}
+	switch e {
+	case syscall.EBUSY, syscall.EAGAIN, syscall.ESTALE, syscall.EINTR:
+		return errnoName(e), failureTransient
+	case syscall.EACCES, syscall.EPERM, syscall.EROFS:
+		return errnoName(e), failurePermanent
+	}
+	return errnoName(e), failureOther
+}
+
+// setRetryPolicy takes the retry and quarantine settings for this cycle and
+// loads the quarantine list
+func (c *Cleaner) setRetryPolicy(opts config.CleanupOptions) {
+	c.retries = opts.Retries()
+	c.backoff = opts.RetryBackoff()
+	c.quarantineFor = opts.QuarantineDuration()
+	c.quarantined = nil
+	if c.db == nil {
+		return
+	}
+
+	entries, err := c.db.GetQuarantine()
+	if err != nil {
+		c.logger.Error("Failed to load quarantine list", "error", err)
+		return
+	}
+	now := time.Now()
+	c.quarantined = make(map[string]database.QuarantineEntry, len(entries))
+	active := 0
+	for _, q := range entries {
+		c.quarantined[q.Path] = q
+		if q.Active(now) {
+			active++
+		}
+	}
+	metrics.SetQuarantinedPaths(active)
+}
+
+// dropQuarantined removes quarantined candidates and returns how many were dropped
+func (c *Cleaner) dropQuarantined(candidates []scan.Candidate) ([]scan.Candidate, int) {
+	if len(c.quarantined) == 0 {
+		return candidates, 0
+	}
+	kept := make([]scan.Candidate, 0, len(candidates))
+	for _, cand := range candidates {
+		if !c.isQuarantined(cand.Path) {
+			kept = append(kept, cand)
+		}
+	}
+	return kept, len(candidates) - len(kept)
+}
+
+// isQuarantined reports whether deletes of path are currently suppressed
+func (c *Cleaner) isQuarantined(path string) bool {
+	q, ok := c.quarantined[path]
+	return ok && q.Active(time.Now())
+}
+
+// remove deletes path, retrying transient failures with exponential backoff.
+// A path that was quarantined earlier is released once it is removed.
+func (c *Cleaner) remove(path string) error {
+	backoff := c.backoff
+	for attempt := 1; ; attempt++ {
+		err := c.deleter.Remove(path)
+		if err == nil {
+			c.release(path)
+			return nil
+		}
+		errno, class := classifyError(err)
+		if class != failureTransient || attempt > c.retries {
+			return err
+		}
+		c.logger.Info("Transient delete failure, retrying", "path", path, "errno", errno, "attempt", attempt, "backoff", backoff)
+		if !c.wait(backoff) {
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+// wait sleeps for d and reports whether it did so without the cycle being cancelled
+func (c *Cleaner) wait(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-c.context().Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// release drops path from the quarantine list after a successful delete
+func (c *Cleaner) release(path string) {
+	if _, ok := c.quarantined[path]; !ok || c.db == nil {
+		return
+	}
+	if _, err := c.db.ReleaseQuarantine(path); err != nil {
+		c.logger.Error("Failed to release path from quarantine", "path", path, "error", err)
+	}
+	delete(c.quarantined, path)
+}
+
+// recordFailure logs and records a failed delete with its errno, and
+// quarantines the path when the failure is permanent
+func (c *Cleaner) recordFailure(cand scan.Candidate, err error) {
+	errno, class := classifyError(err)
+	c.logger.Error("Failed to delete", "path", cand.Path, "errno", errno, "class", class, "error", err)
+	c.logStructured("ERROR", cand.Path, objectTypeOf(cand), cand.Size, errno)
+	metrics.RecordDeleteError(errno, class)
+	c.incrementErrorsTotal()
+	if c.db == nil {
+		return
+	}
+	if dbErr := c.db.RecordFailure(cand, errno, err.Error()); dbErr != nil {
+		c.logger.Error("Failed to record error to database", "error", dbErr)
+	}
+	if class != failurePermanent || c.quarantineFor <= 0 {
+		return
+	}
+	now := time.Now()
+	until := now.Add(c.quarantineFor)
+	if qErr := c.db.Quarantine(cand.Path, errno, err.Error(), now, until); qErr != nil {
+		c.logger.Error("Failed to quarantine path", "path", cand.Path, "error", qErr)
+		return
+	}
+	c.logger.Info("Path quarantined after permanent failure", "path", cand.Path, "errno", errno, "until", until.Format(time.RFC3339))
+}
@@ -0,0 +1,282 @@
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"storage-sage/internal/config"
+	"storage-sage/internal/database"
+	"storage-sage/internal/safety"
+	"storage-sage/internal/scan"
+)
+
+// scriptedDeleter fails each path with the queued errors before succeeding
+type scriptedDeleter struct {
+	errs  map[string][]error
+	calls map[string]int
+}
+
+func (d *scriptedDeleter) Remove(path string) error {
+	d.calls[path]++
+	if q := d.errs[path]; len(q) > 0 {
+		d.errs[path] = q[1:]
+		return q[0]
+	}
+	return nil
+}
+
+func (d *scriptedDeleter) RemoveAll(path string) error {
+	return d.Remove(path)
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err       error
+		wantErrno string
+		wantClass string
+	}{
+		{&fs.PathError{Op: "remove", Path: "/x", Err: syscall.EBUSY}, "EBUSY", failureTransient},
+		{syscall.ESTALE, "ESTALE", failureTransient},
+		{&fs.PathError{Op: "remove", Path: "/x", Err: syscall.EACCES}, "EACCES", failurePermanent},
+		{syscall.EROFS, "EROFS", failurePermanent},
+		{syscall.ENOTEMPTY, "ENOTEMPTY", failureOther},
+		{fs.ErrClosed, "unknown", failureOther},
+	}
+	for _, tt := range tests {
+		errno, class := classifyError(tt.err)
+		if errno != tt.wantErrno || class != tt.wantClass {
+			t.Errorf("classifyError(%v) = %s, %s; want %s, %s", tt.err, errno, class, tt.wantErrno, tt.wantClass)
+		}
+	}
+}
+
+// TestRetryAndQuarantine verifies transient failures are retried within the
+// cycle and permanent ones are quarantined instead of retried every cycle
+func TestRetryAndQuarantine(t *testing.T) {
+	root := t.TempDir()
+	busy := filepath.Join(root, "busy")
+	stuck := filepath.Join(root, "stuck")
+	denied := filepath.Join(root, "denied")
+
+	db, err := database.NewDeletionDB(filepath.Join(t.TempDir(), "deletions.db"))
+	if err != nil {
+		t.Fatalf("NewDeletionDB: %v", err)
+	}
+	defer db.Close()
+
+	deleter := &scriptedDeleter{
+		errs: map[string][]error{
+			busy:   {syscall.EBUSY, syscall.EAGAIN},
+			stuck:  {syscall.EBUSY, syscall.EBUSY, syscall.EBUSY, syscall.EBUSY},
+			denied: {&fs.PathError{Op: "remove", Path: denied, Err: syscall.EACCES}},
+		},
+		calls: make(map[string]int),
+	}
+	cfg := &config.Config{
+		ScanPaths:      []string{root},
+		CleanupOptions: config.CleanupOptions{RetryAttempts: intPtr(2), RetryBackoffMs: 1, QuarantineHours: intPtr(24)},
+	}
+	cleaner := NewCleaner(log.Default(), nil, false, db)
+	cleaner.SetDeleter(deleter)
+	cleaner.SetValidator(safety.NewValidator([]string{root}, nil))
+
+	candidates := []scan.Candidate{
+		{Path: busy, DeletionReason: scan.DeletionReason{PathRule: root}},
+		{Path: stuck, DeletionReason: scan.DeletionReason{PathRule: root}},
+		{Path: denied, DeletionReason: scan.DeletionReason{PathRule: root}},
+	}
+	count, _, err := cleaner.CleanupWithConfig(cfg, candidates)
+	if err != nil {
+		t.Fatalf("CleanupWithConfig: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("deleted %d, want 1", count)
+	}
+	wantCalls := map[string]int{busy: 3, stuck: 3, denied: 1}
+	for path, want := range wantCalls {
+		if got := deleter.calls[path]; got != want {
+			t.Errorf("%s: %d delete attempts, want %d", filepath.Base(path), got, want)
+		}
+	}
+
+	failed, err := db.GetDeletionsByAction("ERROR")
+	if err != nil {
+		t.Fatalf("GetDeletionsByAction: %v", err)
+	}
+	gotErrno := make(map[string]string)
+	for _, r := range failed {
+		gotErrno[r.Path] = r.Errno
+	}
+	if gotErrno[stuck] != "EBUSY" || gotErrno[denied] != "EACCES" || len(failed) != 2 {
+		t.Errorf("ERROR rows errno = %v, want stuck=EBUSY denied=EACCES", gotErrno)
+	}
+
+	quarantine, err := db.GetQuarantine()
+	if err != nil {
+		t.Fatalf("GetQuarantine: %v", err)
+	}
+	if len(quarantine) != 1 || quarantine[0].Path != denied || quarantine[0].Errno != "EACCES" {
+		t.Fatalf("quarantine = %+v, want only %s", quarantine, denied)
+	}
+
+	// The next cycle leaves the quarantined path alone and retries the transient one
+	deleter.calls = make(map[string]int)
+	if _, _, err := cleaner.CleanupWithConfig(cfg, candidates[1:]); err != nil {
+		t.Fatalf("CleanupWithConfig: %v", err)
+	}
+	if deleter.calls[denied] != 0 || deleter.calls[stuck] != 2 {
+		t.Errorf("second cycle attempts = %v, want stuck twice", deleter.calls)
+	}
+
+	// Once the quarantine expires the path is retried and released when it goes
+	now := time.Now()
+	if err := db.Quarantine(denied, "EACCES", "permission denied", now.Add(-time.Hour), now.Add(-time.Minute)); err != nil {
+		t.Fatalf("Quarantine: %v", err)
+	}
+	if _, _, err := cleaner.CleanupWithConfig(cfg, candidates[2:]); err != nil {
+		t.Fatalf("CleanupWithConfig: %v", err)
+	}
+	if deleter.calls[denied] != 1 {
+		t.Errorf("expired quarantine: %d attempts, want 1", deleter.calls[denied])
+	}
+	if quarantine, _ := db.GetQuarantine(); len(quarantine) != 0 {
+		t.Errorf("quarantine after successful delete = %+v, want empty", quarantine)
+	}
+}
+
+// TestRetryPolicy verifies retry_attempts 0 turns retries off, an unset value
+// retries three times, and cancelling the cycle cuts the backoff short
+func TestRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		attempts  *int
+		backoffMs int
+		cancel    bool
+		wantCalls int
+	}{
+		{name: "no retries", attempts: intPtr(0), backoffMs: 1, wantCalls: 1},
+		{name: "default", backoffMs: 1, wantCalls: 4},
+		{name: "cancelled during backoff", attempts: intPtr(3), backoffMs: 60000, cancel: true, wantCalls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			busy := filepath.Join(root, "busy")
+			deleter := &scriptedDeleter{
+				errs:  map[string][]error{busy: {syscall.EBUSY, syscall.EBUSY, syscall.EBUSY, syscall.EBUSY, syscall.EBUSY}},
+				calls: make(map[string]int),
+			}
+			cfg := &config.Config{
+				ScanPaths:      []string{root},
+				CleanupOptions: config.CleanupOptions{RetryAttempts: tt.attempts, RetryBackoffMs: tt.backoffMs, QuarantineHours: intPtr(24)},
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancel {
+				time.AfterFunc(50*time.Millisecond, cancel)
+			}
+			cleaner := NewCleaner(log.Default(), nil, false, nil)
+			cleaner.SetContext(ctx)
+			cleaner.SetDeleter(deleter)
+			cleaner.SetValidator(safety.NewValidator([]string{root}, nil))
+
+			start := time.Now()
+			candidates := []scan.Candidate{
+				{Path: busy, DeletionReason: scan.DeletionReason{PathRule: root}},
+				{Path: filepath.Join(root, "next"), DeletionReason: scan.DeletionReason{PathRule: root}},
+			}
+			_, _, err := cleaner.CleanupWithConfig(cfg, candidates)
+			if tt.cancel {
+				if !errors.Is(err, context.Canceled) || time.Since(start) > 5*time.Second {
+					t.Fatalf("CleanupWithConfig = %v after %s, want it cancelled promptly", err, time.Since(start))
+				}
+			} else if err != nil {
+				t.Fatalf("CleanupWithConfig: %v", err)
+			}
+			if got := deleter.calls[busy]; got != tt.wantCalls {
+				t.Errorf("%d delete attempts, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+// TestQuarantineOff verifies quarantine_hours 0 leaves permanent failures to
+// be retried every cycle
+func TestQuarantineOff(t *testing.T) {
+	root := t.TempDir()
+	denied := filepath.Join(root, "denied")
+
+	db, err := database.NewDeletionDB(filepath.Join(t.TempDir(), "deletions.db"))
+	if err != nil {
+		t.Fatalf("NewDeletionDB: %v", err)
+	}
+	defer db.Close()
+
+	deleter := &scriptedDeleter{
+		errs:  map[string][]error{denied: {&fs.PathError{Op: "remove", Path: denied, Err: syscall.EACCES}}},
+		calls: make(map[string]int),
+	}
+	cfg := &config.Config{
+		ScanPaths:      []string{root},
+		CleanupOptions: config.CleanupOptions{QuarantineHours: intPtr(0)},
+	}
+	cleaner := NewCleaner(log.Default(), nil, false, db)
+	cleaner.SetDeleter(deleter)
+	cleaner.SetValidator(safety.NewValidator([]string{root}, nil))
+
+	if _, _, err := cleaner.CleanupWithConfig(cfg, []scan.Candidate{{Path: denied, DeletionReason: scan.DeletionReason{PathRule: root}}}); err != nil {
+		t.Fatalf("CleanupWithConfig: %v", err)
+	}
+	if quarantine, _ := db.GetQuarantine(); len(quarantine) != 0 {
+		t.Errorf("quarantine = %+v, want empty with quarantine_hours 0", quarantine)
+	}
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+// TestUnavailableRootSkipped verifies a delete refused because its rule root
+// could not be opened is recorded as a SKIP and not quarantined
+func TestUnavailableRootSkipped(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "old.log")
+
+	db, err := database.NewDeletionDB(filepath.Join(t.TempDir(), "deletions.db"))
+	if err != nil {
+		t.Fatalf("NewDeletionDB: %v", err)
+	}
+	defer db.Close()
+
+	refused := &fs.PathError{Op: "remove", Path: path, Err: safety.ErrRootUnavailable}
+	deleter := &scriptedDeleter{errs: map[string][]error{path: {refused}}, calls: make(map[string]int)}
+	cfg := &config.Config{
+		ScanPaths:      []string{root},
+		CleanupOptions: config.CleanupOptions{QuarantineHours: intPtr(24)},
+	}
+	cleaner := NewCleaner(log.Default(), nil, false, db)
+	cleaner.SetDeleter(deleter)
+	cleaner.SetValidator(safety.NewValidator([]string{root}, nil))
+
+	count, _, err := cleaner.CleanupWithConfig(cfg, []scan.Candidate{{Path: path, DeletionReason: scan.DeletionReason{PathRule: root}}})
+	if err != nil || count != 0 {
+		t.Fatalf("CleanupWithConfig = %d, %v; want nothing deleted", count, err)
+	}
+	skipped, err := db.GetDeletionsByAction("SKIP")
+	if err != nil {
+		t.Fatalf("GetDeletionsByAction: %v", err)
+	}
+	if len(skipped) != 1 || !strings.HasPrefix(skipped[0].ErrorMessage, "root_unavailable") {
+		t.Errorf("SKIP rows = %+v, want one root_unavailable", skipped)
+	}
+	if quarantine, _ := db.GetQuarantine(); len(quarantine) != 0 {
+		t.Errorf("quarantine = %+v, want empty", quarantine)
+	}
+}
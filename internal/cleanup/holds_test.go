@@ -0,0 +1,60 @@
+package cleanup
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"storage-sage/internal/config"
+	"storage-sage/internal/database"
+	"storage-sage/internal/fsops"
+	"storage-sage/internal/safety"
+	"storage-sage/internal/scan"
+)
+
+// TestLegalHoldSkipsCandidate verifies a held file is recorded as a
+// legal_hold SKIP rather than a safety violation, and others still go
+func TestLegalHoldSkipsCandidate(t *testing.T) {
+	root := t.TempDir()
+	held, free := filepath.Join(root, "case", "mail.eml"), filepath.Join(root, "tmp.bin")
+	for _, p := range []string{held, free} {
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	db, err := database.NewDeletionDB(filepath.Join(t.TempDir(), "deletions.db"))
+	if err != nil {
+		t.Fatalf("NewDeletionDB: %v", err)
+	}
+	defer db.Close()
+
+	validator := safety.NewValidator([]string{root}, nil)
+	validator.SetHolds([]safety.LegalHold{{ID: 1, Pattern: filepath.Join(root, "case"), Owner: "legal", Reason: "case 114"}})
+	fake := &fsops.FakeDeleter{}
+	cleaner := NewCleaner(log.Default(), nil, false, db)
+	cleaner.SetDeleter(fake)
+	cleaner.SetValidator(validator)
+
+	cfg := &config.Config{ScanPaths: []string{root}}
+	candidates := []scan.Candidate{{Path: held, Size: 1}, {Path: free, Size: 1}}
+	count, _, err := cleaner.CleanupWithConfig(cfg, candidates)
+	if err != nil {
+		t.Fatalf("CleanupWithConfig: %v", err)
+	}
+	if count != 1 || len(fake.Calls) != 1 || fake.Calls[0] != "rm:"+free {
+		t.Errorf("deleted %d (%v), want only %s", count, fake.Calls, free)
+	}
+	skips, err := db.GetDeletionsByAction("SKIP")
+	if err != nil {
+		t.Fatalf("GetDeletionsByAction: %v", err)
+	}
+	if len(skips) != 1 || !strings.HasPrefix(skips[0].ErrorMessage, "legal_hold: ") {
+		t.Errorf("SKIP rows = %+v, want one legal_hold", skips)
+	}
+}
@@ -0,0 +1,173 @@
+package cleanup
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"storage-sage/internal/config"
+	"storage-sage/internal/scan"
+)
+
+// removeTree deletes a directory candidate bottom-up instead of calling RemoveAll.
+// Every entry is validated and recorded on its own and freed bytes are the real
+// file sizes. The walk stops at the first entry that fails validation or cannot
+// be removed, leaving that entry and all of its ancestors in place.
+// failed reports whether the walk stopped because of an error rather than a
+// protection policy rule.
+func (c *Cleaner) removeTree(cfg *config.Config, root scan.Candidate) (removed int, freed int64, failed bool) {
+	info, err := os.Lstat(root.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			c.logger.Info("Directory already deleted (race condition)", "path", root.Path)
+			return 0, 0, false
+		}
+		c.recordFailure(root, err)
+		return 0, 0, true
+	}
+
+	t := &treeRemoval{cleaner: c, cfg: cfg, reason: root.DeletionReason}
+	t.remove(root.Path, info, true)
+	return t.removed, t.freed, t.failed
+}
+
+// treeRemoval carries the running totals of one recursive delete
+type treeRemoval struct {
+	cleaner *Cleaner
+	cfg     *config.Config
+	reason  scan.DeletionReason // inherited from the directory candidate
+
+	removed int
+	freed   int64
+	failed  bool
+}
+
+// remove deletes path (after its children, for directories) and returns false
+// when the walk must stop
+func (t *treeRemoval) remove(path string, info os.FileInfo, isRoot bool) bool {
+	c := t.cleaner
+
+	entry := scan.CandidateFromInfo(path, info, t.reason)
+	if info.IsDir() {
+		// Directory inode sizes are not reclaimable space
+		entry.Size = 0
+	}
+
+	// The root was validated by CleanupWithConfig
+	if !isRoot {
+		// A quarantined entry cannot be removed, so neither can its ancestors
+		if c.isQuarantined(path) {
+			return false
+		}
+		if allowed, isError := c.checkTarget(t.cfg, entry); !allowed {
+			t.failed = isError
+			return false
+		}
+	}
+
+	if info.IsDir() {
+		names, err := readDirNames(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return true
+			}
+			c.recordFailure(entry, err)
+			t.failed = true
+			return false
+		}
+		empty := true
+		for _, name := range names {
+			child := filepath.Join(path, name)
+			childInfo, err := os.Lstat(child)
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				c.recordFailure(scan.Candidate{Path: child, DeletionReason: t.reason}, err)
+				t.failed = true
+				return false
+			}
+			empty = false
+			if !t.remove(child, childInfo, false) {
+				return false
+			}
+		}
+		entry.IsEmptyDir = empty
+	}
+
+	action := "DRY_RUN"
+	if c.dryRun {
+		c.logger.Info("[DRY RUN] Would remove", "path", path, "size", entry.Size)
+		// DRY-RUN CONTRACT: Never call deleter in dry-run mode
+	} else {
+		if err := c.limiter.WaitDelete(c.context(), entry.Size); err != nil {
+			c.recordFailure(entry, err)
+			t.failed = true
+			return false
+		}
+		if c.pausedNow(entry) {
+			return false
+		}
+		allowed, isError, err := c.holdsAllow(t.cfg, entry)
+		if err != nil {
+			c.recordFailure(entry, err)
+			t.failed = true
+			return false
+		}
+		if !allowed {
+			t.failed = isError
+			return false
+		}
+		if err := c.remove(path); err != nil {
+			if os.IsNotExist(err) {
+				return true
+			}
+			c.recordFailure(entry, err)
+			t.failed = true
+			return false
+		}
+		action = "DELETE"
+	}
+
+	deletionReason := ""
+	if entry.DeletionReason.HasReason() {
+		deletionReason = entry.DeletionReason.ToLogString()
+	}
+	c.logStructured(action, path, objectTypeOf(entry), entry.Size, deletionReason)
+	if c.db != nil {
+		if dbErr := c.db.RecordDeletion(action, entry, ""); dbErr != nil {
+			c.logger.Error("Failed to record to database", "error", dbErr)
+		}
+	}
+
+	t.removed++
+	t.freed += entry.Size
+	c.accountDeletion(entry)
+	return true
+}
+
+// objectTypeOf returns the object type used in structured logs
+func objectTypeOf(c scan.Candidate) string {
+	if c.IsEmptyDir {
+		return "empty_directory"
+	}
+	if c.IsDir {
+		return "directory"
+	}
+	return "file"
+}
+
+// readDirNames returns the sorted entry names of dir
+func readDirNames(dir string) ([]string, error) {
+	f, err := os.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+	names, err := f.Readdirnames(-1)
+	_ = f.Close()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,112 @@
+package cleanup
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"storage-sage/internal/config"
+	"storage-sage/internal/fsops"
+	"storage-sage/internal/safety"
+	"storage-sage/internal/scan"
+)
+
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		full := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestRecursiveDeleteValidatesEachEntry verifies directories are removed
+// bottom-up through the deleter, one validated entry at a time
+func TestRecursiveDeleteValidatesEachEntry(t *testing.T) {
+	tmpDir := t.TempDir()
+	dir := filepath.Join(tmpDir, "old")
+	writeTree(t, dir, map[string]string{
+		"a.log":     "12345",
+		"sub/b.log": "1234567890",
+	})
+
+	cfg := &config.Config{
+		ScanPaths:      []string{tmpDir},
+		CleanupOptions: config.CleanupOptions{Recursive: true, DeleteDirs: true},
+	}
+	fakeDeleter := &fsops.FakeDeleter{}
+	cleaner := NewCleaner(log.Default(), nil, false, nil)
+	cleaner.SetDeleter(fakeDeleter)
+	cleaner.SetValidator(safety.NewValidator([]string{tmpDir}, nil))
+
+	count, freed, err := cleaner.CleanupWithConfig(cfg, []scan.Candidate{{Path: dir, Size: 4096, IsDir: true}})
+	if err != nil {
+		t.Fatalf("CleanupWithConfig failed: %v", err)
+	}
+
+	want := []string{
+		"rm:" + filepath.Join(dir, "a.log"),
+		"rm:" + filepath.Join(dir, "sub", "b.log"),
+		"rm:" + filepath.Join(dir, "sub"),
+		"rm:" + dir,
+	}
+	if len(fakeDeleter.Calls) != len(want) {
+		t.Fatalf("deleter calls = %v, want %v", fakeDeleter.Calls, want)
+	}
+	for i := range want {
+		if fakeDeleter.Calls[i] != want[i] {
+			t.Errorf("call %d = %s, want %s", i, fakeDeleter.Calls[i], want[i])
+		}
+	}
+	if count != 4 {
+		t.Errorf("count = %d, want 4 removed entries", count)
+	}
+	if freed != 15 {
+		t.Errorf("freed = %d, want 15 bytes of file content (not the directory inode size)", freed)
+	}
+}
+
+// TestRecursiveDeleteStopsAtProtectedEntry verifies a protected entry and its
+// ancestors survive a recursive delete
+func TestRecursiveDeleteStopsAtProtectedEntry(t *testing.T) {
+	tmpDir := t.TempDir()
+	dir := filepath.Join(tmpDir, "old")
+	writeTree(t, dir, map[string]string{
+		"a.log":                   "x",
+		"keep/.storage-sage-keep": "",
+		"keep/data.bin":           "x",
+		"z.log":                   "x",
+	})
+
+	cfg := &config.Config{
+		ScanPaths:      []string{tmpDir},
+		CleanupOptions: config.CleanupOptions{Recursive: true, DeleteDirs: true},
+	}
+	validator := safety.NewValidator([]string{tmpDir}, nil)
+	validator.SetPolicy(&safety.Policy{MarkerFile: ".storage-sage-keep"})
+
+	cleaner := NewCleaner(log.Default(), nil, false, nil)
+	cleaner.SetValidator(validator)
+
+	count, _, err := cleaner.CleanupWithConfig(cfg, []scan.Candidate{{Path: dir, IsDir: true}})
+	if err != nil {
+		t.Fatalf("CleanupWithConfig failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("count = %d, want 1 (only a.log precedes the protected entry)", count)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "a.log")); !os.IsNotExist(err) {
+		t.Errorf("a.log should have been deleted")
+	}
+	for _, kept := range []string{"keep/data.bin", "keep/.storage-sage-keep", "z.log", ""} {
+		if _, err := os.Stat(filepath.Join(dir, kept)); err != nil {
+			t.Errorf("%s should have been left in place: %v", filepath.Join(dir, kept), err)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package cleanup
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"storage-sage/internal/config"
+	"storage-sage/internal/fsops"
+	"storage-sage/internal/metrics"
+	"storage-sage/internal/scan"
+)
+
+// truncate cuts a file selected for truncation down to its rule's
+// keep_tail_bytes in place. It returns the bytes freed and whether the file
+// was truncated (or would be, in dry-run), after logging and recording the
+// outcome.
+func (c *Cleaner) truncate(cfg *config.Config, cand scan.Candidate) (freed int64, ok bool, isError bool) {
+	reason := cand.DeletionReason.ToLogString()
+	if c.dryRun {
+		c.logger.Info("[DRY RUN] Would truncate file", "path", cand.Path, "size", cand.Size, "keep_tail", cand.DeletionReason.Truncate.KeepTail)
+		// DRY-RUN CONTRACT: Never write or remove anything in dry-run mode
+		c.logStructured("DRY_RUN", cand.Path, "file", cand.Size, reason)
+		if c.db != nil {
+			if dbErr := c.db.RecordDeletion("DRY_RUN", cand, ""); dbErr != nil {
+				c.logger.Error("Failed to record to database", "error", dbErr)
+			}
+		}
+		return 0, true, false
+	}
+
+	// The rule as configured now, not as it was at scan time
+	keepTail := cand.DeletionReason.Truncate.KeepTail
+	if rule := scan.RuleFor(cfg, cand.DeletionReason.PathRule); rule != nil && rule.Truncate != nil {
+		keepTail = rule.Truncate.KeepTailBytes
+	}
+	// Truncate through the deleter, so the secure deleter's root fds and the sandbox confine it like a delete
+	t, canTruncate := c.deleter.(fsops.Truncator)
+	if !canTruncate {
+		c.recordFailure(cand, fmt.Errorf("%w: %T cannot truncate", errors.ErrUnsupported, c.deleter))
+		return 0, false, true
+	}
+	id := fsops.FileID{Dev: cand.Dev, Ino: cand.Inode, Valid: cand.HasFileID}
+	before, after, err := t.Truncate(cand.Path, id, keepTail)
+	switch {
+	case err == nil:
+	case os.IsNotExist(err):
+		c.logger.Info("File already deleted (race condition)", "path", cand.Path)
+		return 0, false, false
+	case errors.Is(err, fsops.ErrChangedWhileTruncating):
+		c.skip(cand, "changed_since_scan", err.Error())
+		return 0, false, false
+	default:
+		c.recordFailure(cand, err)
+		return 0, false, true
+	}
+
+	cand.Size = before
+	freed = before - after
+	c.logStructured("TRUNCATE", cand.Path, "file", before, reason)
+	if c.db != nil {
+		if dbErr := c.db.RecordTruncation(cand, after); dbErr != nil {
+			c.logger.Error("Failed to record to database", "error", dbErr)
+		}
+	}
+	metrics.RecordTruncation(freed)
+	return freed, true, false
+}
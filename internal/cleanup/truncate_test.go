@@ -0,0 +1,93 @@
+package cleanup
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"storage-sage/internal/config"
+	"storage-sage/internal/database"
+	"storage-sage/internal/fsops"
+	"storage-sage/internal/safety"
+	"storage-sage/internal/scan"
+)
+
+// TestCleanupTruncate verifies truncate candidates are cut down to their tail
+// in place and recorded as TRUNCATE with the size before and after, and that
+// dry-run leaves them alone
+func TestCleanupTruncate(t *testing.T) {
+	for _, dryRun := range []bool{false, true} {
+		name := "truncate"
+		if dryRun {
+			name = "dry run"
+		}
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			rule := config.PathRule{
+				Path:     root,
+				Truncate: &config.Truncate{MaxSizeBytes: 1000, KeepTailBytes: 100},
+			}
+			path := filepath.Join(root, "app.log")
+			content := strings.Repeat("worker 7 finished job\n", 500)
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			info, err := os.Lstat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			reason := scan.DeletionReason{
+				PathRule: root,
+				Truncate: &scan.TruncateReason{Size: info.Size(), MaxSize: 1000, KeepTail: 100, Open: true},
+			}
+			cand := scan.CandidateFromInfo(path, info, reason)
+
+			db, err := database.NewDeletionDB(filepath.Join(t.TempDir(), "deletions.db"))
+			if err != nil {
+				t.Fatalf("NewDeletionDB: %v", err)
+			}
+			defer db.Close()
+
+			cleaner := NewCleaner(log.Default(), nil, dryRun, db)
+			cleaner.SetDeleter(fsops.OSDeleter{})
+			cleaner.SetValidator(safety.NewValidator([]string{root}, nil))
+			count, freed, err := cleaner.CleanupWithConfig(&config.Config{Paths: []config.PathRule{rule}}, []scan.Candidate{cand})
+			if err != nil {
+				t.Fatalf("CleanupWithConfig: %v", err)
+			}
+			if count != 1 {
+				t.Errorf("count = %d, want 1", count)
+			}
+
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("truncated file is gone: %v", err)
+			}
+			action := "TRUNCATE"
+			if dryRun {
+				action = "DRY_RUN"
+				if string(got) != content || freed != 0 {
+					t.Errorf("dry run touched the file (%d bytes left, freed %d)", len(got), freed)
+				}
+			} else if string(got) != content[len(content)-100:] || freed != int64(len(content)-100) {
+				t.Errorf("file holds %d bytes (freed %d), want its last 100", len(got), freed)
+			}
+
+			rows, err := db.GetDeletionsByAction(action)
+			if err != nil {
+				t.Fatalf("GetDeletionsByAction: %v", err)
+			}
+			if len(rows) != 1 {
+				t.Fatalf("%s rows = %d, want 1", action, len(rows))
+			}
+			if r := rows[0]; r.PrimaryReason != "truncate" || r.Size != int64(len(content)) {
+				t.Errorf("row %+v, want primary reason truncate and the size before", r)
+			}
+			if r := rows[0]; !dryRun && (r.SizeAfter == nil || *r.SizeAfter != 100) {
+				t.Errorf("row %+v, want size_after 100", r)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package cleanup
+
+import (
+	"os"
+	"time"
+
+	"storage-sage/internal/config"
+	"storage-sage/internal/disk"
+	"storage-sage/internal/scan"
+)
+
+// verifyCandidate lstats cand right before removal and re-evaluates its
+// deletion reason against the current metadata and disk usage. It returns
+// false, after logging and recording why, when cand must not be removed.
+// Candidates not built from lstat carry no file identity and are not checked.
+func (c *Cleaner) verifyCandidate(cfg *config.Config, cand *scan.Candidate) (ok bool, isError bool) {
+	if !cand.HasFileID {
+		return true, false
+	}
+
+	info, err := os.Lstat(cand.Path)
+	if os.IsNotExist(err) {
+		c.logger.Info("File already deleted (race condition)", "path", cand.Path)
+		return false, false
+	}
+	if err != nil {
+		c.logger.Error("Failed to re-check candidate", "path", cand.Path, "error", err)
+		c.logStructured("ERROR", cand.Path, "recheck", cand.Size, err.Error())
+		if c.db != nil {
+			_ = c.db.RecordDeletion("ERROR", *cand, err.Error())
+		}
+		c.incrementErrorsTotal()
+		return false, true
+	}
+
+	if change := scan.Changed(*cand, info); change != "" {
+		c.skip(*cand, "changed_since_scan", change)
+		return false, false
+	}
+
+	rule := scan.RuleFor(cfg, cand.DeletionReason.PathRule)
+	if rule == nil {
+		return true, false
+	}
+	usedPercent, _, _, err := disk.GetDiskUsage(rule.Path)
+	if err != nil {
+		// Keep the scan-time usage rather than guessing
+		usedPercent = scanUsage(cand.DeletionReason)
+	}
+	reason := scan.Reevaluate(*cand, rule, usedPercent, time.Now())
+	if !reason.HasReason() {
+		c.skip(*cand, "no_longer_eligible", cand.DeletionReason.ToLogString())
+		return false, false
+	}
+	cand.DeletionReason = reason
+	if !cand.IsDir {
+		cand.Size = info.Size()
+	}
+	return true, false
+}
+
+// skip logs and records a SKIP of cand as "reason: detail"
+func (c *Cleaner) skip(cand scan.Candidate, reason, detail string) {
+	c.logStructured("SKIP", cand.Path, reason, cand.Size, detail)
+	if c.db != nil {
+		_ = c.db.RecordDeletion("SKIP", cand, reason+": "+detail)
+	}
+}
+
+// scanUsage returns the disk usage the scanner saw when it selected a candidate
+func scanUsage(r scan.DeletionReason) float64 {
+	switch {
+	case r.Tier != nil:
+		return r.Tier.ActualPercent
+	case r.Compress != nil:
+		return r.Compress.ActualPercent
+	case r.Truncate != nil:
+		return r.Truncate.ActualPercent
+	case r.StackedCleanup != nil:
+		return r.StackedCleanup.ActualPercent
+	case r.DiskThreshold != nil:
+		return r.DiskThreshold.ActualPercent
+	}
+	return 0
+}
@@ -0,0 +1,173 @@
+package cleanup
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"storage-sage/internal/config"
+	"storage-sage/internal/database"
+	"storage-sage/internal/fsops"
+	"storage-sage/internal/safety"
+	"storage-sage/internal/scan"
+)
+
+// TestRecheckBeforeDelete verifies candidates that changed or stopped
+// qualifying between the scan and the delete are skipped
+func TestRecheckBeforeDelete(t *testing.T) {
+	old := time.Now().Add(-40 * 24 * time.Hour)
+
+	tests := []struct {
+		name     string
+		reason   func(root string) scan.DeletionReason
+		mutate   func(t *testing.T, path string)
+		young    bool   // file is younger than the age rule
+		wantSkip string // SKIP reason prefix; "" expects a delete
+		gone     bool   // expect neither a delete nor a SKIP
+	}{
+		{
+			name:   "unchanged",
+			reason: ageReason,
+		},
+		{
+			name:   "rewritten",
+			reason: ageReason,
+			mutate: func(t *testing.T, path string) {
+				if err := os.WriteFile(path, []byte("new contents"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantSkip: "changed_since_scan",
+		},
+		{
+			name:   "replaced with same mtime",
+			reason: ageReason,
+			mutate: func(t *testing.T, path string) {
+				if err := os.Rename(path, path+".old"); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.Chtimes(path, old, old); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantSkip: "changed_since_scan",
+		},
+		{
+			name:   "replaced by directory",
+			reason: ageReason,
+			mutate: func(t *testing.T, path string) {
+				if err := os.Remove(path); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.Mkdir(path, 0755); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantSkip: "changed_since_scan",
+		},
+		{
+			name: "disk pressure relieved",
+			reason: func(root string) scan.DeletionReason {
+				return scan.DeletionReason{
+					PathRule:      root,
+					DiskThreshold: &scan.DiskReason{ConfiguredPercent: 100, ActualPercent: 100},
+				}
+			},
+			young:    true,
+			wantSkip: "no_longer_eligible",
+		},
+		{
+			name:   "removed",
+			reason: ageReason,
+			mutate: func(t *testing.T, path string) {
+				if err := os.Remove(path); err != nil {
+					t.Fatal(err)
+				}
+			},
+			gone: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			path := filepath.Join(root, "data.bin")
+			if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			mtime := old
+			if tt.young {
+				mtime = time.Now().Add(-time.Hour)
+			}
+			if err := os.Chtimes(path, mtime, mtime); err != nil {
+				t.Fatal(err)
+			}
+			info, err := os.Lstat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cand := scan.CandidateFromInfo(path, info, tt.reason(root))
+			if !cand.HasFileID {
+				t.Skip("platform does not expose inode numbers")
+			}
+			if tt.mutate != nil {
+				tt.mutate(t, path)
+			}
+
+			db, err := database.NewDeletionDB(filepath.Join(t.TempDir(), "deletions.db"))
+			if err != nil {
+				t.Fatalf("NewDeletionDB: %v", err)
+			}
+			defer db.Close()
+
+			// Disk usage can never reach 100%, so only the age rule can still select the file
+			cfg := &config.Config{
+				Paths: []config.PathRule{{Path: root, AgeOffDays: 30, MaxFreePercent: 100, StackThreshold: 100}},
+			}
+			fake := &fsops.FakeDeleter{}
+			cleaner := NewCleaner(log.Default(), nil, false, db)
+			cleaner.SetDeleter(fake)
+			cleaner.SetValidator(safety.NewValidator([]string{root}, nil))
+
+			count, _, err := cleaner.CleanupWithConfig(cfg, []scan.Candidate{cand})
+			if err != nil {
+				t.Fatalf("CleanupWithConfig: %v", err)
+			}
+			skips, err := db.GetDeletionsByAction("SKIP")
+			if err != nil {
+				t.Fatalf("GetDeletionsByAction: %v", err)
+			}
+
+			switch {
+			case tt.gone:
+				if count != 0 || len(fake.Calls) != 0 || len(skips) != 0 {
+					t.Errorf("removed file: count=%d calls=%v skips=%d, want nothing", count, fake.Calls, len(skips))
+				}
+			case tt.wantSkip == "":
+				if count != 1 || len(fake.Calls) != 1 {
+					t.Errorf("deleted %d (%v), want 1", count, fake.Calls)
+				}
+			default:
+				if count != 0 || len(fake.Calls) != 0 {
+					t.Errorf("changed candidate was deleted: %v", fake.Calls)
+				}
+				if len(skips) != 1 || !strings.HasPrefix(skips[0].ErrorMessage, tt.wantSkip+": ") {
+					t.Errorf("SKIP rows = %+v, want one %s", skips, tt.wantSkip)
+				}
+			}
+		})
+	}
+}
+
+func ageReason(root string) scan.DeletionReason {
+	return scan.DeletionReason{
+		PathRule:     root,
+		AgeThreshold: &scan.AgeReason{ConfiguredDays: 30, ActualAgeDays: 40},
+	}
+}
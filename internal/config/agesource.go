@@ -0,0 +1,75 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Timestamps a rule can measure the age of an entry from
+const (
+	AgeSourceMtime    = "mtime"
+	AgeSourceCtime    = "ctime"
+	AgeSourceAtime    = "atime"
+	AgeSourceFilename = "filename"
+)
+
+// validateAgeSource rejects unknown age sources and filename settings that cannot date a name
+func (r *PathRule) validateAgeSource() error {
+	switch r.AgeSource {
+	case "", AgeSourceMtime, AgeSourceCtime, AgeSourceAtime:
+		if r.AgeLayout != "" || r.AgePattern != "" {
+			return errors.New("age_layout and age_pattern need age_source filename")
+		}
+		return nil
+	case AgeSourceFilename:
+	default:
+		return fmt.Errorf("age_source must be mtime, ctime, atime or filename, got %q", r.AgeSource)
+	}
+	if r.AgeLayout == "" {
+		return errors.New("age_source filename needs age_layout")
+	}
+	if r.AgePattern == "" {
+		return nil
+	}
+	re, err := regexp.Compile(r.AgePattern)
+	if err != nil {
+		return fmt.Errorf("age_pattern: %w", err)
+	}
+	r.ageRe = re
+	return nil
+}
+
+// AgeFromName returns the time a file name carries under age_layout: the
+// pattern's "date" group (or whole match) when age_pattern is set, otherwise
+// the first run of the name as long as the layout that parses
+func (r *PathRule) AgeFromName(name string) (time.Time, bool) {
+	if r.AgePattern == "" {
+		for i := 0; i+len(r.AgeLayout) <= len(name); i++ {
+			if t, err := time.ParseInLocation(r.AgeLayout, name[i:i+len(r.AgeLayout)], time.Local); err == nil {
+				return t, true
+			}
+		}
+		return time.Time{}, false
+	}
+
+	if r.ageRe == nil {
+		if r.validateAgeSource() != nil {
+			return time.Time{}, false
+		}
+	}
+	m := r.ageRe.FindStringSubmatch(name)
+	if m == nil {
+		return time.Time{}, false
+	}
+	s := m[0]
+	if i := r.ageRe.SubexpIndex("date"); i >= 0 {
+		s = m[i]
+	}
+	t, err := time.ParseInLocation(r.AgeLayout, s, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
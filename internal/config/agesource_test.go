@@ -0,0 +1,64 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateAgeSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    PathRule
+		wantErr string
+	}{
+		{name: "default", rule: PathRule{}},
+		{name: "atime", rule: PathRule{AgeSource: "atime"}},
+		{name: "filename by layout", rule: PathRule{AgeSource: "filename", AgeLayout: "2006-01-02"}},
+		{name: "filename by pattern", rule: PathRule{AgeSource: "filename", AgeLayout: "20060102", AgePattern: `-(?P<date>\d{8})\.`}},
+		{name: "unknown source", rule: PathRule{AgeSource: "birth"}, wantErr: "age_source must be"},
+		{name: "filename without layout", rule: PathRule{AgeSource: "filename"}, wantErr: "needs age_layout"},
+		{name: "layout without filename", rule: PathRule{AgeLayout: "2006-01-02"}, wantErr: "need age_source filename"},
+		{name: "bad pattern", rule: PathRule{AgeSource: "filename", AgeLayout: "2006", AgePattern: `(`}, wantErr: "age_pattern"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.validateAgeSource()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateAgeSource: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("validateAgeSource() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAgeFromName(t *testing.T) {
+	sept1 := time.Date(2026, 9, 1, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		name     string
+		rule     PathRule
+		file     string
+		want     time.Time
+		wantDate bool
+	}{
+		{name: "layout anywhere in name", rule: PathRule{AgeLayout: "2006-01-02"}, file: "app-2026-09-01.log", want: sept1, wantDate: true},
+		{name: "no date in name", rule: PathRule{AgeLayout: "2006-01-02"}, file: "app.log"},
+		{name: "pattern date group", rule: PathRule{AgeLayout: "20060102", AgePattern: `\.(?P<date>\d{8})\.gz$`}, file: "export-7.20260901.gz", want: sept1, wantDate: true},
+		{name: "pattern whole match", rule: PathRule{AgeLayout: "2006-01-02", AgePattern: `\d{4}-\d{2}-\d{2}`}, file: "db_2026-09-01_full.sql", want: sept1, wantDate: true},
+		{name: "pattern matches an invalid date", rule: PathRule{AgeLayout: "20060102", AgePattern: `\d{8}`}, file: "build-20261345.tar"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.rule.AgeSource = AgeSourceFilename
+			got, ok := tt.rule.AgeFromName(tt.file)
+			if ok != tt.wantDate || !got.Equal(tt.want) {
+				t.Errorf("AgeFromName(%q) = %v, %v; want %v, %v", tt.file, got, ok, tt.want, tt.wantDate)
+			}
+		})
+	}
+}
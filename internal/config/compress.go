@@ -0,0 +1,145 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Tier actions: what a tier does with the files it selects
+const (
+	ActionDelete   = "delete"
+	ActionCompress = "compress"
+)
+
+// Compression formats of compress tiers
+const (
+	FormatGzip = "gzip"
+	FormatZstd = "zstd"
+)
+
+// defaultSkipExtensions are the suffixes of files that are already compressed
+var defaultSkipExtensions = []string{".gz", ".tgz", ".zst", ".bz2", ".xz", ".lz4", ".zip", ".7z"}
+
+// Compress configures how a rule's compress tiers compress the files they select
+type Compress struct {
+	Format         string   `yaml:"format" json:"format"`                   // "gzip" (default) or "zstd"
+	Level          int      `yaml:"level" json:"level"`                     // 1 (fastest) to 9 (smallest), mapped to zstd's levels for zstd (default: 6)
+	SkipExtensions []string `yaml:"skip_extensions" json:"skip_extensions"` // Suffixes of files left alone (default: .gz .tgz .zst .bz2 .xz .lz4 .zip .7z)
+}
+
+// action returns the tier's action with the default filled in
+func (t Tier) action() string {
+	if t.Action == "" {
+		return ActionDelete
+	}
+	return t.Action
+}
+
+// Deletes reports whether the tier deletes the files it selects
+func (t Tier) Deletes() bool {
+	return t.action() == ActionDelete
+}
+
+// Compresses reports whether the tier compresses the files it selects instead of deleting them
+func (t Tier) Compresses() bool {
+	return t.action() == ActionCompress
+}
+
+// Compresses reports whether any tier of the rule compresses
+func (r *PathRule) Compresses() bool {
+	for _, t := range r.Tiers {
+		if t.Compresses() {
+			return true
+		}
+	}
+	return false
+}
+
+// EngagedTier returns the highest tier at or below level (1-based) whose action
+// is action, or 0 when none is; tiers with different actions engage independently
+func (r *PathRule) EngagedTier(level int, action string) int {
+	if level > len(r.Tiers) {
+		level = len(r.Tiers)
+	}
+	for i := level; i > 0; i-- {
+		if r.Tiers[i-1].action() == action {
+			return i
+		}
+	}
+	return 0
+}
+
+// CompressOptions returns the rule's compression settings with defaults filled in
+func (r *PathRule) CompressOptions() Compress {
+	c := Compress{}
+	if r.Compress != nil {
+		c = *r.Compress
+	}
+	if c.Format == "" {
+		c.Format = FormatGzip
+	}
+	if c.Level == 0 {
+		c.Level = 6
+	}
+	if c.SkipExtensions == nil {
+		c.SkipExtensions = defaultSkipExtensions
+	}
+	return c
+}
+
+// validateCompress rejects compression settings without a compress tier and
+// unknown formats, then fills in the defaults
+func (r *PathRule) validateCompress() error {
+	if r.Compress == nil {
+		if !r.Compresses() {
+			return nil
+		}
+		r.Compress = &Compress{}
+	}
+	if !r.Compresses() {
+		return errors.New("compress settings need a tier with action compress")
+	}
+	switch r.Compress.Format {
+	case "", FormatGzip, FormatZstd:
+	default:
+		return fmt.Errorf("compress format must be %q or %q, got %q", FormatGzip, FormatZstd, r.Compress.Format)
+	}
+	if r.Compress.Level < 0 || r.Compress.Level > 9 {
+		return fmt.Errorf("compress level must be between 1 and 9, got %d", r.Compress.Level)
+	}
+	for i, ext := range r.Compress.SkipExtensions {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if ext == "" || ext == "." {
+			return errors.New("compress skip_extensions cannot list an empty extension")
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		r.Compress.SkipExtensions[i] = ext
+	}
+	*r.Compress = r.CompressOptions()
+	return nil
+}
+
+// Extension returns the suffix compressing a file appends to its name
+func (c Compress) Extension() string {
+	if c.Format == FormatZstd {
+		return ".zst"
+	}
+	return ".gz"
+}
+
+// Skips reports whether a file named name is left alone because it already carries a compressed extension
+func (c Compress) Skips(name string) bool {
+	name = strings.ToLower(name)
+	if strings.HasSuffix(name, c.Extension()) {
+		return true
+	}
+	for _, ext := range c.SkipExtensions {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateCompress(t *testing.T) {
+	tests := []struct {
+		name       string
+		yaml       string
+		wantLevel  int
+		wantFormat string
+		wantErr    string
+	}{
+		{
+			name:      "compress at any usage, delete much later",
+			yaml:      "tiers:\n      - {usage_percent: 0, min_age: 1d, action: compress}\n      - {usage_percent: 90, min_age: 30d}",
+			wantLevel: 6,
+		},
+		{
+			name:      "settings",
+			yaml:      "compress: {level: 9, skip_extensions: [LOG2, .bak]}\n    tiers:\n      - {usage_percent: 0, min_age: 1d, action: compress}",
+			wantLevel: 9,
+		},
+		{
+			name:       "zstd",
+			yaml:       "compress: {format: zstd}\n    tiers:\n      - {usage_percent: 0, action: compress}",
+			wantLevel:  6,
+			wantFormat: FormatZstd,
+		},
+		{name: "unknown format", yaml: "compress: {format: brotli}\n    tiers:\n      - {usage_percent: 0, action: compress}", wantErr: "compress format must be"},
+		{name: "level", yaml: "compress: {level: 12}\n    tiers:\n      - {usage_percent: 0, action: compress}", wantErr: "between 1 and 9"},
+		{name: "settings without tier", yaml: "compress: {level: 9}\n    tiers:\n      - {usage_percent: 90}", wantErr: "need a tier with action compress"},
+		{name: "unknown action", yaml: "tiers:\n      - {usage_percent: 90, action: shred}", wantErr: "action must be"},
+		{name: "delete tier at 0%", yaml: "tiers:\n      - {usage_percent: 0}", wantErr: "usage_percent must be between"},
+		{
+			name:    "higher compress tier spares files",
+			yaml:    "tiers:\n      - {usage_percent: 0, min_age: 1d, action: compress}\n      - {usage_percent: 90, min_age: 30d}\n      - {usage_percent: 95, min_age: 2d, action: compress}",
+			wantErr: "a lower one compresses",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := decode(strings.NewReader("paths:\n  - path: /var/log/app\n    " + tt.yaml + "\n"))
+			if err == nil {
+				err = cfg.validateAndDefault()
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantFormat := tt.wantFormat
+			if wantFormat == "" {
+				wantFormat = FormatGzip
+			}
+			rule := &cfg.Paths[0]
+			if rule.Compress == nil || rule.Compress.Level != tt.wantLevel || rule.Compress.Format != wantFormat {
+				t.Errorf("compress = %+v, want %s at level %d", rule.Compress, wantFormat, tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestCompressSkips(t *testing.T) {
+	rule := &PathRule{Compress: &Compress{SkipExtensions: []string{".bak"}}}
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"app.log", false},
+		{"app.log.gz", true},
+		{"APP.LOG.GZ", true},
+		{"db.bak", true},
+		{"archive.zst", false}, // An explicit list replaces the defaults
+	}
+	for _, tt := range tests {
+		if got := rule.CompressOptions().Skips(tt.name); got != tt.want {
+			t.Errorf("Skips(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if !(&PathRule{}).CompressOptions().Skips("archive.zst") {
+		t.Error("default extensions should skip .zst")
+	}
+	zstdRule := &PathRule{Compress: &Compress{Format: FormatZstd, SkipExtensions: []string{".bak"}}}
+	if !zstdRule.CompressOptions().Skips("app.log.zst") {
+		t.Error("a zstd rule should skip its own .zst output")
+	}
+}
+
+func TestEngagedTier(t *testing.T) {
+	rule := &PathRule{Tiers: []Tier{
+		{UsagePercent: 0, Action: ActionCompress},
+		{UsagePercent: 85},
+		{UsagePercent: 95, Action: ActionDelete},
+	}}
+	tests := []struct {
+		level        int
+		wantDelete   int
+		wantCompress int
+	}{
+		{0, 0, 0},
+		{1, 0, 1},
+		{2, 2, 1},
+		{3, 3, 1},
+	}
+	if got := rule.TriggerPercent(); got != 85 {
+		t.Errorf("TriggerPercent = %g, want 85 from the first delete tier", got)
+	}
+	for _, tt := range tests {
+		if d, c := rule.EngagedTier(tt.level, ActionDelete), rule.EngagedTier(tt.level, ActionCompress); d != tt.wantDelete || c != tt.wantCompress {
+			t.Errorf("level %d: delete tier %d, compress tier %d; want %d, %d", tt.level, d, c, tt.wantDelete, tt.wantCompress)
+		}
+	}
+}
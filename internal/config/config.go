@@ -6,20 +6,57 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"time"
 
 	yaml "gopkg.in/yaml.v3"
 )
 
 type PathRule struct {
-	Path              string `yaml:"path" json:"path"`
-	AgeOffDays        int    `yaml:"age_off_days" json:"age_off_days"`
-	MinFreePercent    int    `yaml:"min_free_percent" json:"min_free_percent"`
-	MaxFreePercent    int    `yaml:"max_free_percent" json:"max_free_percent"`       // Threshold to trigger cleanup (e.g., 90)
-	TargetFreePercent int    `yaml:"target_free_percent" json:"target_free_percent"` // Target after cleanup (e.g., 80)
-	Priority          int    `yaml:"priority" json:"priority"`                       // Lower number = higher priority (e.g., 1 = highest)
-	StackThreshold    int    `yaml:"stack_threshold" json:"stack_threshold"`         // Percentage where stacked cleanup triggers (e.g., 98)
-	StackAgeDays      int    `yaml:"stack_age_days" json:"stack_age_days"`           // Age threshold for stacked cleanup (e.g., 14)
+	Path              string   `yaml:"path" json:"path"`
+	AgeOffDays        int      `yaml:"age_off_days" json:"age_off_days"`
+	MaxAge            Duration `yaml:"max_age" json:"max_age,omitempty"` // Age off as a duration, e.g. "6h" or "7d"; replaces age_off_days
+	MinFreePercent    int      `yaml:"min_free_percent" json:"min_free_percent"`
+	MaxFreePercent    int      `yaml:"max_free_percent" json:"max_free_percent"`       // Threshold to trigger cleanup (e.g., 90)
+	TargetFreePercent int      `yaml:"target_free_percent" json:"target_free_percent"` // Target after cleanup (e.g., 80)
+	Priority          int      `yaml:"priority" json:"priority"`                       // Lower number = higher priority (e.g., 1 = highest)
+	StackThreshold    int      `yaml:"stack_threshold" json:"stack_threshold"`         // Percentage where stacked cleanup triggers (e.g., 98)
+	StackAgeDays      int      `yaml:"stack_age_days" json:"stack_age_days"`           // Age threshold for stacked cleanup (e.g., 14)
+	StackMaxAge       Duration `yaml:"stack_max_age" json:"stack_max_age,omitempty"`   // Stacked cleanup age as a duration; replaces stack_age_days
+	MinRetentionDays  int      `yaml:"min_retention_days" json:"min_retention_days"`   // Never delete anything younger than this, in any mode (0 = no floor)
+	ComplianceMode    bool     `yaml:"compliance_mode" json:"compliance_mode"`         // Floor covers nested rules, counts ctime, and fails closed
+
+	// Where age_off_days, stack_age_days and tier ages count from; entries it cannot
+	// date fall back to mtime. Retention floors always count from mtime.
+	AgeSource  string `yaml:"age_source" json:"age_source"`   // "mtime" (default), "ctime", "atime" or "filename"
+	AgeLayout  string `yaml:"age_layout" json:"age_layout"`   // Go time layout of the date in the name, e.g. "2006-01-02"
+	AgePattern string `yaml:"age_pattern" json:"age_pattern"` // Optional regex locating the date: its "date" group, or the whole match
+
+	ageRe *regexp.Regexp
+
+	// Escalation ladder replacing max_free_percent/stack_threshold/stack_age_days when set
+	Tiers                 []Tier  `yaml:"tiers" json:"tiers"`
+	TierHysteresisPercent float64 `yaml:"tier_hysteresis_percent" json:"tier_hysteresis_percent"` // A tier stays engaged until usage drops this far below its threshold (default: 2)
+	TierCooldownMinutes   int     `yaml:"tier_cooldown_minutes" json:"tier_cooldown_minutes"`     // Minimum time at a tier before stepping down (default: 30)
+
+	// Backup retention: when set the rule selects only the files GFS does not keep,
+	// and age_off_days, disk thresholds and tiers do not apply to it
+	GFS *GFS `yaml:"gfs" json:"gfs,omitempty"`
+
+	// Version retention: when set the rule selects only the files past the newest
+	// count of each group, and age_off_days, disk thresholds and tiers do not apply to it
+	KeepLatest *KeepLatest `yaml:"keep_latest" json:"keep_latest,omitempty"`
+
+	// How tiers with action compress compress files (default: gzip at level 6)
+	Compress *Compress `yaml:"compress" json:"compress,omitempty"`
+
+	// Oversized files to truncate in place: at any disk usage, in STACK mode, or
+	// while a tier with action truncate is engaged
+	Truncate *Truncate `yaml:"truncate" json:"truncate,omitempty"`
+
+	BlastRadius BlastRadius `yaml:"blast_radius" json:"blast_radius"` // Per-cycle deletion limits for this rule (0 = unlimited)
+	Overlap     string      `yaml:"overlap" json:"overlap"`           // Below another root: "override" (default) or "inherit" unset values from it
 }
 
 type PrometheusCfg struct {
@@ -31,20 +68,66 @@ type LoggingCfg struct {
 }
 
 type ResourceLimits struct {
-	MaxCPUPercent float64 `yaml:"max_cpu_percent" json:"max_cpu_percent"` // Maximum CPU usage (e.g., 10.0)
+	MaxCPUPercent         float64 `yaml:"max_cpu_percent" json:"max_cpu_percent"`                   // Maximum CPU usage (e.g., 10.0)
+	MaxDeletionsPerSecond float64 `yaml:"max_deletions_per_second" json:"max_deletions_per_second"` // Deletion rate budget (default: 0 = unlimited)
+	MaxBytesPerSecond     int64   `yaml:"max_bytes_per_second" json:"max_bytes_per_second"`         // Bytes removed per second budget (default: 0 = unlimited)
+	IOPriorityIdle        bool    `yaml:"io_priority_idle" json:"io_priority_idle"`                 // Run in the idle I/O scheduling class (Linux only)
 }
 
 type CleanupOptions struct {
-	Recursive  bool `yaml:"recursive" json:"recursive"`     // Recursive deletion flag
-	DeleteDirs bool `yaml:"delete_dirs" json:"delete_dirs"` // Allow directory deletion flag
+	Recursive        bool `yaml:"recursive" json:"recursive"`                   // Recursive deletion flag
+	DeleteDirs       bool `yaml:"delete_dirs" json:"delete_dirs"`               // Allow directory deletion flag
+	GracePeriodHours int  `yaml:"grace_period_hours" json:"grace_period_hours"` // Mark candidates pending and delete them only after this long unchanged (0 = delete immediately)
+
+	RetryAttempts   *int `yaml:"retry_attempts" json:"retry_attempts"`     // Retries of a delete failing with EBUSY, EAGAIN, ESTALE or EINTR within a cycle (0 = none; default: 3)
+	RetryBackoffMs  int  `yaml:"retry_backoff_ms" json:"retry_backoff_ms"` // Delay before the first retry, doubled for each further retry (default: 100)
+	QuarantineHours *int `yaml:"quarantine_hours" json:"quarantine_hours"` // Stop retrying a path after EACCES, EPERM or EROFS for this long (0 = never quarantine; default: 24)
+}
+
+// GracePeriod returns how long a candidate stays pending before it is removed
+func (o CleanupOptions) GracePeriod() time.Duration {
+	return time.Duration(o.GracePeriodHours) * time.Hour
+}
+
+const (
+	// defaultRetryAttempts is how often a transient failure is retried when retry_attempts is unset
+	defaultRetryAttempts = 3
+	// defaultQuarantineHours is how long a permanent failure is left alone when quarantine_hours is unset
+	defaultQuarantineHours = 24
+)
+
+// Retries returns how often a transient delete failure is retried within a cycle
+func (o CleanupOptions) Retries() int {
+	if o.RetryAttempts == nil {
+		return defaultRetryAttempts
+	}
+	return *o.RetryAttempts
+}
+
+// RetryBackoff returns the delay before the first retry of a transient failure
+func (o CleanupOptions) RetryBackoff() time.Duration {
+	return time.Duration(o.RetryBackoffMs) * time.Millisecond
+}
+
+// QuarantineDuration returns how long a permanently failing path is left
+// alone; zero means permanent failures are not quarantined
+func (o CleanupOptions) QuarantineDuration() time.Duration {
+	if o.QuarantineHours == nil {
+		return defaultQuarantineHours * time.Hour
+	}
+	return time.Duration(*o.QuarantineHours) * time.Hour
 }
 
 type ScanOptimizations struct {
-	FastScanThreshold int  `yaml:"fast_scan_threshold" json:"fast_scan_threshold"` // File count threshold for du -sb mode (default: 1M)
+	FastScanThreshold int  `yaml:"fast_scan_threshold" json:"fast_scan_threshold"` // File count threshold for the native fast counter (default: 1M)
 	CacheTTLMinutes   int  `yaml:"cache_ttl_minutes" json:"cache_ttl_minutes"`     // Cache TTL in minutes (default: 5)
 	ParallelScans     bool `yaml:"parallel_scans" json:"parallel_scans"`           // Enable parallel path scanning (default: true)
-	UseFastScan       bool `yaml:"use_fast_scan" json:"use_fast_scan"`             // Enable du -sb for large paths (default: true)
+	UseFastScan       bool `yaml:"use_fast_scan" json:"use_fast_scan"`             // Enable the native fast counter for large paths (default: true)
 	UseCache          bool `yaml:"use_cache" json:"use_cache"`                     // Enable scan caching (default: true)
+
+	UseIndex        bool   `yaml:"use_index" json:"use_index"`                 // Persist a directory index to skip unchanged subtrees (default: false)
+	IndexPath       string `yaml:"index_path" json:"index_path"`               // Index file location (default: next to database_path)
+	FullRescanHours int    `yaml:"full_rescan_hours" json:"full_rescan_hours"` // Walk every root in full at least this often (default: 24)
 }
 
 type WorkerPoolConfig struct {
@@ -54,10 +137,61 @@ type WorkerPoolConfig struct {
 	TimeoutSeconds int  `yaml:"timeout_seconds" json:"timeout_seconds"` // Timeout per batch in seconds (default: 30)
 }
 
+// ProtectionPolicy declares what must never be deleted, on top of the built-in system paths.
+// It can be written inline under `protection:` or kept in a separate policy_file.
+type ProtectionPolicy struct {
+	PolicyFile    string   `yaml:"policy_file" json:"policy_file"`       // Optional YAML file with additional rules (merged into this section)
+	Paths         []string `yaml:"paths" json:"paths"`                   // Protected path prefixes
+	Globs         []string `yaml:"globs" json:"globs"`                   // Glob patterns; a match protects the path and its subtree
+	MarkerFile    string   `yaml:"marker_file" json:"marker_file"`       // File name that protects its directory's subtree (default: .storage-sage-keep)
+	Xattr         string   `yaml:"xattr" json:"xattr"`                   // Extended attribute that protects a file or subtree (default: user.storage_sage.keep)
+	SkipImmutable bool     `yaml:"skip_immutable" json:"skip_immutable"` // Skip files with the immutable or append-only attribute
+	AllowUIDs     []uint32 `yaml:"allow_uids" json:"allow_uids"`         // Only delete files owned by these uids (empty = any)
+	DenyUIDs      []uint32 `yaml:"deny_uids" json:"deny_uids"`           // Never delete files owned by these uids
+}
+
+// BlastRadius caps how much a single cycle may delete. A cycle that would
+// exceed any limit is aborted before the first delete. Zero disables a limit.
+type BlastRadius struct {
+	MaxFiles    int     `yaml:"max_files" json:"max_files"`       // Maximum entries deleted per cycle
+	MaxBytes    int64   `yaml:"max_bytes" json:"max_bytes"`       // Maximum bytes deleted per cycle
+	MaxFraction float64 `yaml:"max_fraction" json:"max_fraction"` // Maximum share of the scanned files deleted per cycle (0-1)
+}
+
+// Enabled reports whether any limit is set
+func (b BlastRadius) Enabled() bool {
+	return b.MaxFiles > 0 || b.MaxBytes > 0 || b.MaxFraction > 0
+}
+
+func (b BlastRadius) validate() error {
+	if b.MaxFiles < 0 || b.MaxBytes < 0 || b.MaxFraction < 0 {
+		return errors.New("blast_radius limits cannot be negative")
+	}
+	if b.MaxFraction > 1 {
+		return fmt.Errorf("blast_radius.max_fraction must be between 0 and 1, got %g", b.MaxFraction)
+	}
+	return nil
+}
+
+type SandboxConfig struct {
+	Enabled bool `yaml:"enabled" json:"enabled"` // Confine deletes to the configured roots with Landlock (Linux 5.13+)
+}
+
+// ControlConfig configures the daemon's authenticated control API (trigger, reload, pause, resume, status).
+// The Unix socket is always served; the TCP listener is only started when listen is set.
+type ControlConfig struct {
+	Socket    string   `yaml:"socket" json:"socket"`         // Unix socket path (default: /run/storage-sage/control.sock)
+	AllowUIDs []uint32 `yaml:"allow_uids" json:"allow_uids"` // Socket peers allowed besides root and the daemon's own uid
+	AllowGIDs []uint32 `yaml:"allow_gids" json:"allow_gids"` // Socket peers allowed by primary group
+	Listen    string   `yaml:"listen" json:"listen"`         // Optional TCP address (e.g. ":9092"), requires token_file
+	TokenFile string   `yaml:"token_file" json:"token_file"` // File holding the bearer token for TCP clients
+}
+
 type Config struct {
 	ScanPaths         []string          `yaml:"scan_paths" json:"scan_paths"`
 	MinFreePercent    int               `yaml:"min_free_percent" json:"min_free_percent"`
 	AgeOffDays        int               `yaml:"age_off_days" json:"age_off_days"`
+	MaxAge            Duration          `yaml:"max_age" json:"max_age,omitempty"` // Age off for scan_paths as a duration; replaces age_off_days
 	IntervalMinutes   int               `yaml:"interval_minutes" json:"interval_minutes"`
 	Paths             []PathRule        `yaml:"paths" json:"paths"`
 	Prometheus        PrometheusCfg     `yaml:"prometheus" json:"prometheus"`
@@ -68,6 +202,10 @@ type Config struct {
 	WorkerPool        WorkerPoolConfig  `yaml:"worker_pool" json:"worker_pool"`                 // Worker pool configuration
 	NFSTimeout        int               `yaml:"nfs_timeout_seconds" json:"nfs_timeout_seconds"` // Timeout for NFS operations
 	DatabasePath      string            `yaml:"database_path" json:"database_path"`             // Path to SQLite database for deletion history
+	Protection        ProtectionPolicy  `yaml:"protection" json:"protection"`                   // Declarative protected-path policy
+	Sandbox           SandboxConfig     `yaml:"sandbox" json:"sandbox"`                         // Kernel-enforced filesystem confinement
+	Control           ControlConfig     `yaml:"control" json:"control"`                         // Authenticated control API
+	BlastRadius       BlastRadius       `yaml:"blast_radius" json:"blast_radius"`               // Per-cycle deletion limits across all rules
 }
 
 var (
@@ -87,6 +225,9 @@ func Load(path string) (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := cfg.Protection.mergePolicyFile(); err != nil {
+		return nil, err
+	}
 	if err := cfg.validateAndDefault(); err != nil {
 		return nil, err
 	}
@@ -107,9 +248,12 @@ func (c *Config) validateAndDefault() error {
 		return errNoPaths
 	}
 
-	if c.AgeOffDays < 0 {
+	if c.AgeOffDays < 0 || c.MaxAge < 0 {
 		return errNegativeAge
 	}
+	if c.AgeOffDays != 0 && c.MaxAge != 0 {
+		return errors.New("max_age replaces age_off_days; set one or the other")
+	}
 
 	if c.IntervalMinutes <= 0 {
 		c.IntervalMinutes = 15
@@ -128,10 +272,36 @@ func (c *Config) validateAndDefault() error {
 	if c.ResourceLimits.MaxCPUPercent <= 0 {
 		c.ResourceLimits.MaxCPUPercent = 10.0 // Default: 10% CPU limit
 	}
+	if c.ResourceLimits.MaxDeletionsPerSecond < 0 {
+		c.ResourceLimits.MaxDeletionsPerSecond = 0 // Default: unlimited
+	}
+	if c.ResourceLimits.MaxBytesPerSecond < 0 {
+		c.ResourceLimits.MaxBytesPerSecond = 0 // Default: unlimited
+	}
 
 	// Set defaults for cleanup options
 	// Recursive defaults to true for backward compatibility
 	// DeleteDirs defaults to false for safety
+	if c.CleanupOptions.GracePeriodHours < 0 {
+		return errors.New("cleanup_options.grace_period_hours cannot be negative")
+	}
+	if c.CleanupOptions.RetryAttempts != nil && *c.CleanupOptions.RetryAttempts < 0 {
+		return errors.New("cleanup_options.retry_attempts cannot be negative")
+	}
+	if c.CleanupOptions.RetryAttempts == nil {
+		retries := defaultRetryAttempts // Default: 3 retries of transient errors; 0 turns retries off
+		c.CleanupOptions.RetryAttempts = &retries
+	}
+	if c.CleanupOptions.RetryBackoffMs <= 0 {
+		c.CleanupOptions.RetryBackoffMs = 100 // Default: 100ms, 200ms, 400ms
+	}
+	if c.CleanupOptions.QuarantineHours != nil && *c.CleanupOptions.QuarantineHours < 0 {
+		return errors.New("cleanup_options.quarantine_hours cannot be negative")
+	}
+	if c.CleanupOptions.QuarantineHours == nil {
+		hours := defaultQuarantineHours // Default: retry permanent failures once a day; 0 turns quarantine off
+		c.CleanupOptions.QuarantineHours = &hours
+	}
 
 	// Set defaults for NFS timeout
 	if c.NFSTimeout <= 0 {
@@ -150,6 +320,12 @@ func (c *Config) validateAndDefault() error {
 	if c.ScanOptimizations.CacheTTLMinutes <= 0 {
 		c.ScanOptimizations.CacheTTLMinutes = 5 // Default: 5 minutes
 	}
+	if c.ScanOptimizations.IndexPath == "" {
+		c.ScanOptimizations.IndexPath = filepath.Join(filepath.Dir(c.DatabasePath), "scan-index.gob")
+	}
+	if c.ScanOptimizations.FullRescanHours <= 0 {
+		c.ScanOptimizations.FullRescanHours = 24 // Default: full walk once a day
+	}
 	// Booleans default to false, so explicitly set defaults only if needed
 	// For now, assume user wants optimizations enabled by default
 
@@ -166,32 +342,52 @@ func (c *Config) validateAndDefault() error {
 	// WorkerPool.Enabled defaults to false for backward compatibility
 	// Users must explicitly enable to use worker pool
 
-	// Set defaults for path rules
-	for i := range c.Paths {
-		if c.Paths[i].MaxFreePercent <= 0 {
-			c.Paths[i].MaxFreePercent = 90 // Default: trigger at 90% usage
-		}
-		if c.Paths[i].TargetFreePercent <= 0 {
-			c.Paths[i].TargetFreePercent = 80 // Default: target 80% usage
-		}
-		if c.Paths[i].Priority <= 0 {
-			c.Paths[i].Priority = 100 // Default: lower priority
-		}
-		if c.Paths[i].StackThreshold <= 0 {
-			c.Paths[i].StackThreshold = 98 // Default: stack cleanup at 98%
+	// Set defaults for protection policy
+	if c.Protection.MarkerFile == "" {
+		c.Protection.MarkerFile = ".storage-sage-keep"
+	}
+	if c.Protection.Xattr == "" {
+		c.Protection.Xattr = "user.storage_sage.keep"
+	}
+	if strings.ContainsRune(c.Protection.MarkerFile, filepath.Separator) {
+		return fmt.Errorf("protection.marker_file must be a file name, got %q", c.Protection.MarkerFile)
+	}
+	for _, g := range c.Protection.Globs {
+		if _, err := filepath.Match(g, ""); err != nil {
+			return fmt.Errorf("protection.globs: invalid pattern %q: %w", g, err)
 		}
-		if c.Paths[i].StackAgeDays <= 0 {
-			c.Paths[i].StackAgeDays = 14 // Default: 14 days for stacked cleanup
+	}
+	for i, p := range c.Protection.Paths {
+		cp, err := cleanAbsolute(p)
+		if err != nil {
+			return fmt.Errorf("protection.paths: %w", err)
 		}
+		c.Protection.Paths[i] = cp
+	}
+
+	// Set defaults for the control API
+	if c.Control.Socket == "" {
+		c.Control.Socket = "/run/storage-sage/control.sock"
+	}
+	if c.Control.Listen != "" && c.Control.TokenFile == "" {
+		return errors.New("control.listen requires control.token_file")
+	}
+
+	if err := c.BlastRadius.validate(); err != nil {
+		return err
 	}
 
 	cleaned := make([]string, 0, len(c.ScanPaths))
+	listed := make(map[string]bool, len(c.ScanPaths))
 	for _, p := range c.ScanPaths {
 		cp, err := cleanAbsolute(p)
 		if err != nil {
 			return err
 		}
-		cleaned = append(cleaned, cp)
+		if !listed[cp] {
+			listed[cp] = true
+			cleaned = append(cleaned, cp)
+		}
 	}
 	c.ScanPaths = cleaned
 
@@ -204,8 +400,112 @@ func (c *Config) validateAndDefault() error {
 		if c.Paths[i].AgeOffDays < 0 {
 			return fmt.Errorf("path %s: %w", c.Paths[i].Path, errNegativeAge)
 		}
+		if err := c.Paths[i].BlastRadius.validate(); err != nil {
+			return fmt.Errorf("path %s: %w", c.Paths[i].Path, err)
+		}
+		if err := c.Paths[i].validateDurations(); err != nil {
+			return fmt.Errorf("path %s: %w", c.Paths[i].Path, err)
+		}
+		if c.Paths[i].MinRetentionDays < 0 {
+			return fmt.Errorf("path %s: min_retention_days cannot be negative", c.Paths[i].Path)
+		}
+		if c.Paths[i].ComplianceMode && c.Paths[i].MinRetentionDays == 0 {
+			return fmt.Errorf("path %s: compliance_mode requires min_retention_days", c.Paths[i].Path)
+		}
+	}
+	if err := c.resolveOverlaps(); err != nil {
+		return err
+	}
+	c.applyComplianceFloors()
+	for i := range c.Paths {
+		if err := c.Paths[i].validateTiers(); err != nil {
+			return fmt.Errorf("path %s: %w", c.Paths[i].Path, err)
+		}
+		if g := c.Paths[i].GFS; g != nil {
+			if c.Paths[i].Tiered() {
+				return fmt.Errorf("path %s: gfs replaces tiers; set one or the other", c.Paths[i].Path)
+			}
+			if err := g.validate(); err != nil {
+				return fmt.Errorf("path %s: %w", c.Paths[i].Path, err)
+			}
+		}
+		if err := c.Paths[i].validateCompress(); err != nil {
+			return fmt.Errorf("path %s: %w", c.Paths[i].Path, err)
+		}
+		if err := c.Paths[i].validateTruncate(); err != nil {
+			return fmt.Errorf("path %s: %w", c.Paths[i].Path, err)
+		}
+		if err := c.Paths[i].validateAgeSource(); err != nil {
+			return fmt.Errorf("path %s: %w", c.Paths[i].Path, err)
+		}
+		if k := c.Paths[i].KeepLatest; k != nil {
+			if c.Paths[i].Tiered() || c.Paths[i].HasGFS() {
+				return fmt.Errorf("path %s: keep_latest replaces tiers and gfs; set only one", c.Paths[i].Path)
+			}
+			if err := k.validate(); err != nil {
+				return fmt.Errorf("path %s: %w", c.Paths[i].Path, err)
+			}
+		}
+	}
+
+	// Set defaults for path rules
+	for i := range c.Paths {
+		if c.Paths[i].MaxFreePercent <= 0 {
+			c.Paths[i].MaxFreePercent = 90 // Default: trigger at 90% usage
+		}
+		if c.Paths[i].TargetFreePercent <= 0 {
+			c.Paths[i].TargetFreePercent = 80 // Default: target 80% usage
+		}
+		if c.Paths[i].Priority <= 0 {
+			c.Paths[i].Priority = 100 // Default: lower priority
+		}
+		if c.Paths[i].StackThreshold <= 0 {
+			c.Paths[i].StackThreshold = 98 // Default: stack cleanup at 98%
+		}
+		if c.Paths[i].StackAgeDays <= 0 && c.Paths[i].StackMaxAge == 0 {
+			c.Paths[i].StackAgeDays = 14 // Default: 14 days for stacked cleanup
+		}
+		if c.Paths[i].TierHysteresisPercent <= 0 {
+			c.Paths[i].TierHysteresisPercent = 2 // Default: step down 2 points below a tier's threshold
+		}
+		if c.Paths[i].TierCooldownMinutes <= 0 {
+			c.Paths[i].TierCooldownMinutes = 30 // Default: hold a tier for at least 30 minutes
+		}
+	}
+
+	return nil
+}
+
+// mergePolicyFile loads PolicyFile (if set) and merges its rules into p.
+// Lists are appended; scalar settings from the file apply only when unset inline.
+func (p *ProtectionPolicy) mergePolicyFile() error {
+	if p.PolicyFile == "" {
+		return nil
 	}
 
+	data, err := os.ReadFile(p.PolicyFile)
+	if err != nil {
+		return fmt.Errorf("read protection policy: %w", err)
+	}
+	var file ProtectionPolicy
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("decode protection policy %s: %w", p.PolicyFile, err)
+	}
+	if file.PolicyFile != "" {
+		return fmt.Errorf("protection policy %s: nested policy_file is not supported", p.PolicyFile)
+	}
+
+	p.Paths = append(p.Paths, file.Paths...)
+	p.Globs = append(p.Globs, file.Globs...)
+	p.AllowUIDs = append(p.AllowUIDs, file.AllowUIDs...)
+	p.DenyUIDs = append(p.DenyUIDs, file.DenyUIDs...)
+	if p.MarkerFile == "" {
+		p.MarkerFile = file.MarkerFile
+	}
+	if p.Xattr == "" {
+		p.Xattr = file.Xattr
+	}
+	p.SkipImmutable = p.SkipImmutable || file.SkipImmutable
 	return nil
 }
 
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryAttempts(t *testing.T) {
+	tests := []struct {
+		yaml string
+		want int
+	}{
+		{"", 3},
+		{"cleanup_options:\n  retry_attempts: 0\n", 0},
+		{"cleanup_options:\n  retry_attempts: 5\n", 5},
+	}
+	for _, tt := range tests {
+		cfg, err := decode(strings.NewReader("scan_paths: [/var/log/app]\n" + tt.yaml))
+		if err == nil {
+			err = cfg.validateAndDefault()
+		}
+		if err != nil {
+			t.Fatalf("%q: %v", tt.yaml, err)
+		}
+		if got := cfg.CleanupOptions.RetryAttempts; got == nil || *got != tt.want || cfg.CleanupOptions.Retries() != tt.want {
+			t.Errorf("%q: retry_attempts = %v, want %d", tt.yaml, got, tt.want)
+		}
+	}
+}
+
+func TestQuarantineHours(t *testing.T) {
+	tests := []struct {
+		yaml string
+		want time.Duration
+	}{
+		{"", 24 * time.Hour},
+		{"cleanup_options:\n  quarantine_hours: 0\n", 0},
+		{"cleanup_options:\n  quarantine_hours: 2\n", 2 * time.Hour},
+	}
+	for _, tt := range tests {
+		cfg, err := decode(strings.NewReader("scan_paths: [/var/log/app]\n" + tt.yaml))
+		if err == nil {
+			err = cfg.validateAndDefault()
+		}
+		if err != nil {
+			t.Fatalf("%q: %v", tt.yaml, err)
+		}
+		if got := cfg.CleanupOptions.QuarantineDuration(); got != tt.want {
+			t.Errorf("%q: quarantine = %s, want %s", tt.yaml, got, tt.want)
+		}
+	}
+}
+
+func TestNegativeRetryOptionsRejected(t *testing.T) {
+	for _, yaml := range []string{
+		"cleanup_options:\n  retry_attempts: -1\n",
+		"cleanup_options:\n  quarantine_hours: -1\n",
+	} {
+		cfg, err := decode(strings.NewReader("scan_paths: [/var/log/app]\n" + yaml))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := cfg.validateAndDefault(); err == nil {
+			t.Errorf("%q: accepted a negative value", yaml)
+		}
+	}
+}
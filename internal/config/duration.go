@@ -0,0 +1,186 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Day and week units accepted by ParseDuration on top of time.ParseDuration's
+const (
+	Day  = 24 * time.Hour
+	Week = 7 * Day
+)
+
+// Duration is a time.Duration written as "6h", "36h", "7d" or "1d12h" in
+// YAML and JSON
+type Duration time.Duration
+
+// ParseDuration parses a duration as time.ParseDuration does, also accepting
+// the units "d" (24h) and "w" (7d), e.g. "7d", "2w" or "1d12h"
+func ParseDuration(s string) (time.Duration, error) {
+	rest := strings.TrimSpace(s)
+	var total time.Duration
+	for {
+		i := strings.IndexAny(rest, "dw")
+		if i < 0 {
+			break
+		}
+		n, err := strconv.ParseFloat(rest[:i], 64)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		unit := Day
+		if rest[i] == 'w' {
+			unit = Week
+		}
+		total += time.Duration(n * float64(unit))
+		rest = rest[i+1:]
+	}
+	if rest == "" {
+		if strings.TrimSpace(s) == "" {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return total, nil
+	}
+	d, err := time.ParseDuration(rest)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	if d < 0 && total > 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return total + d, nil
+}
+
+// FormatDuration writes d the way a config would: whole days as "7d",
+// anything else as days plus the rest, e.g. "1d12h" or "6h30m"
+func FormatDuration(d time.Duration) string {
+	if d == 0 {
+		return "0s"
+	}
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	days := d / Day
+	rest := d % Day
+	switch {
+	case rest == 0:
+		return fmt.Sprintf("%s%dd", sign, days)
+	case days == 0:
+		return sign + trimZeroUnits(rest.String())
+	default:
+		return fmt.Sprintf("%s%dd%s", sign, days, trimZeroUnits(rest.String()))
+	}
+}
+
+// trimZeroUnits drops the zero minutes and seconds time.Duration.String
+// writes, so 6h0m0s reads 6h
+func trimZeroUnits(s string) string {
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// String returns d in config syntax
+func (d Duration) String() string {
+	return FormatDuration(time.Duration(d))
+}
+
+// UnmarshalYAML accepts a duration string such as "7d" or "36h"
+func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
+	var s string
+	if err := value.Decode(&s); err != nil {
+		return err
+	}
+	v, err := ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", value.Line, err)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalYAML writes d in config syntax
+func (d Duration) MarshalYAML() (interface{}, error) {
+	return d.String(), nil
+}
+
+// UnmarshalJSON accepts a duration string such as "7d" or "36h"
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalJSON writes d in config syntax
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// ageLimit returns d when set, else days as a duration; this is how every
+// duration field takes over from its legacy day field
+func ageLimit(d Duration, days int) time.Duration {
+	if d != 0 {
+		return time.Duration(d)
+	}
+	return time.Duration(days) * Day
+}
+
+// AgeLimit returns how old an entry must be for age cleanup (0 = no age cleanup)
+func (r *PathRule) AgeLimit() time.Duration {
+	return ageLimit(r.MaxAge, r.AgeOffDays)
+}
+
+// StackAgeLimit returns how old an entry must be for stacked cleanup
+func (r *PathRule) StackAgeLimit() time.Duration {
+	return ageLimit(r.StackMaxAge, r.StackAgeDays)
+}
+
+// MinAgeLimit returns how old an entry must be for the tier to delete it (0 = any age)
+func (t Tier) MinAgeLimit() time.Duration {
+	return ageLimit(t.MinAge, t.MinAgeDays)
+}
+
+// durationField is a duration setting and the legacy day field it replaces
+type durationField struct {
+	name, legacy string
+	d            Duration
+	days         int
+}
+
+// validateDurations rejects negative ages and a duration set alongside the day field it replaces
+func (r *PathRule) validateDurations() error {
+	fields := []durationField{
+		{"max_age", "age_off_days", r.MaxAge, r.AgeOffDays},
+		{"stack_max_age", "stack_age_days", r.StackMaxAge, r.StackAgeDays},
+	}
+	for _, t := range r.Tiers {
+		fields = append(fields, durationField{fmt.Sprintf("tier at %g%%: min_age", t.UsagePercent), "min_age_days", t.MinAge, t.MinAgeDays})
+	}
+	for _, f := range fields {
+		if f.d < 0 {
+			return fmt.Errorf("%s cannot be negative", f.name)
+		}
+		if f.d != 0 && f.days != 0 {
+			return fmt.Errorf("%s replaces %s; set one or the other", f.name, f.legacy)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "6h", want: 6 * time.Hour},
+		{in: "36h", want: 36 * time.Hour},
+		{in: "7d", want: 7 * Day},
+		{in: "2w", want: 14 * Day},
+		{in: "1d12h", want: 36 * time.Hour},
+		{in: "0.5d", want: 12 * time.Hour},
+		{in: "90m", want: 90 * time.Minute},
+		{in: "", wantErr: true},
+		{in: "7", wantErr: true},
+		{in: "-1d", wantErr: true},
+		{in: "7days", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, %v; want %v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	for d, want := range map[time.Duration]string{
+		6 * time.Hour:                        "6h",
+		7 * Day:                              "7d",
+		36 * time.Hour:                       "1d12h",
+		6*time.Hour + 30*time.Minute:         "6h30m",
+		2*Day + 90*time.Minute + time.Second: "2d1h30m1s",
+	} {
+		if got := FormatDuration(d); got != want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestDurationFields(t *testing.T) {
+	tests := []struct {
+		name         string
+		yaml         string
+		wantMaxAge   time.Duration
+		wantStackAge time.Duration
+		wantErr      string
+	}{
+		{name: "durations", yaml: "max_age: 6h\n    stack_max_age: 36h", wantMaxAge: 6 * time.Hour, wantStackAge: 36 * time.Hour},
+		{name: "legacy days", yaml: "age_off_days: 7", wantMaxAge: 7 * Day, wantStackAge: 14 * Day},
+		{name: "both set", yaml: "age_off_days: 7\n    max_age: 7d", wantErr: "max_age replaces age_off_days"},
+		{name: "unparseable", yaml: "max_age: soon", wantErr: "invalid duration"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := decode(strings.NewReader("paths:\n  - path: /tmp/uploads\n    " + tt.yaml + "\n"))
+			if err == nil {
+				err = cfg.validateAndDefault()
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			rule := &cfg.Paths[0]
+			if rule.AgeLimit() != tt.wantMaxAge || rule.StackAgeLimit() != tt.wantStackAge {
+				t.Errorf("AgeLimit %v, StackAgeLimit %v; want %v, %v", rule.AgeLimit(), rule.StackAgeLimit(), tt.wantMaxAge, tt.wantStackAge)
+			}
+		})
+	}
+}
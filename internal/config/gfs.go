@@ -0,0 +1,77 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// GFS is grandfather-father-son retention: of the files it manages, the
+// newest in each of the latest Daily days, Weekly ISO weeks, Monthly months
+// and Yearly years is kept and every other one is selected
+type GFS struct {
+	Daily   int `yaml:"daily" json:"daily"`
+	Weekly  int `yaml:"weekly" json:"weekly"`
+	Monthly int `yaml:"monthly" json:"monthly"`
+	Yearly  int `yaml:"yearly" json:"yearly"`
+
+	// Regex on file names; only matching files are managed (default: every file).
+	// A named group "series" keeps a separate set per value (e.g. per database),
+	// a named group "date" parsed with date_layout dates the file instead of its mtime.
+	Pattern    string `yaml:"pattern" json:"pattern"`
+	DateLayout string `yaml:"date_layout" json:"date_layout"` // Go time layout, e.g. "20060102-1504"
+
+	re *regexp.Regexp
+}
+
+// HasGFS reports whether the rule selects files by GFS retention only
+func (r *PathRule) HasGFS() bool {
+	return r.GFS != nil
+}
+
+// validate compiles the pattern and rejects settings that keep nothing or cannot date files
+func (g *GFS) validate() error {
+	if g.Daily < 0 || g.Weekly < 0 || g.Monthly < 0 || g.Yearly < 0 {
+		return errors.New("gfs counts cannot be negative")
+	}
+	if g.Daily+g.Weekly+g.Monthly+g.Yearly == 0 {
+		return errors.New("gfs must keep at least one daily, weekly, monthly or yearly file")
+	}
+	re, err := regexp.Compile(g.Pattern)
+	if err != nil {
+		return fmt.Errorf("gfs pattern: %w", err)
+	}
+	hasDate := re.SubexpIndex("date") >= 0
+	if hasDate != (g.DateLayout != "") {
+		return errors.New(`gfs date_layout and a "date" group in pattern go together`)
+	}
+	g.re = re
+	return nil
+}
+
+// Match reports whether GFS manages a file named name, and returns its series
+// and, with a date group, the date its name carries
+func (g *GFS) Match(name string) (series string, date time.Time, dated, ok bool) {
+	if g.re == nil {
+		if g.validate() != nil {
+			return "", time.Time{}, false, false
+		}
+	}
+	m := g.re.FindStringSubmatch(name)
+	if m == nil {
+		return "", time.Time{}, false, false
+	}
+	if i := g.re.SubexpIndex("series"); i >= 0 {
+		series = m[i]
+	}
+	if i := g.re.SubexpIndex("date"); i >= 0 {
+		t, err := time.ParseInLocation(g.DateLayout, m[i], time.Local)
+		if err != nil {
+			// A name that does not carry a valid date is not one of the managed files
+			return "", time.Time{}, false, false
+		}
+		return series, t, true, true
+	}
+	return series, time.Time{}, false, true
+}
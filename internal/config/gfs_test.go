@@ -0,0 +1,35 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateGFS(t *testing.T) {
+	tests := []struct {
+		name    string
+		gfs     GFS
+		wantErr string
+	}{
+		{name: "dated by mtime", gfs: GFS{Daily: 7, Weekly: 4, Monthly: 12}},
+		{name: "dated by name", gfs: GFS{Daily: 7, Pattern: `^db-(?P<date>\d{8})\.dump$`, DateLayout: "20060102"}},
+		{name: "keeps nothing", gfs: GFS{}, wantErr: "at least one"},
+		{name: "negative count", gfs: GFS{Daily: -1, Weekly: 2}, wantErr: "negative"},
+		{name: "layout without date group", gfs: GFS{Daily: 7, Pattern: `\.dump$`, DateLayout: "20060102"}, wantErr: "go together"},
+		{name: "bad pattern", gfs: GFS{Daily: 7, Pattern: `(`}, wantErr: "gfs pattern"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.gfs.validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validate: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("validate() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
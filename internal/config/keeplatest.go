@@ -0,0 +1,83 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// Orders keep_latest ranks the files of a group by
+const (
+	OrderMtime  = "mtime"
+	OrderSemver = "semver"
+)
+
+// KeepLatest keeps the newest Count files of each group it manages and selects
+// every older one, however young
+type KeepLatest struct {
+	Count int `yaml:"count" json:"count"`
+
+	// Regex on file names; only matching files are managed (default: every file).
+	// Files are grouped by parent directory and, within it, by the value of a
+	// named group "name" (e.g. the artifact name).
+	Pattern string `yaml:"pattern" json:"pattern"`
+	Order   string `yaml:"order" json:"order"` // "mtime" (default) or "semver", parsed from the pattern's "version" group
+
+	re *regexp.Regexp
+}
+
+// HasKeepLatest reports whether the rule selects files by keep_latest only
+func (r *PathRule) HasKeepLatest() bool {
+	return r.KeepLatest != nil
+}
+
+// KeepsByCount reports whether the rule keeps a number of files (gfs or
+// keep_latest) instead of selecting them by age and disk usage
+func (r *PathRule) KeepsByCount() bool {
+	return r.HasGFS() || r.HasKeepLatest()
+}
+
+// validate compiles the pattern and rejects settings that keep nothing or cannot rank files
+func (k *KeepLatest) validate() error {
+	if k.Count < 1 {
+		return errors.New("keep_latest must keep at least one file per group")
+	}
+	re, err := regexp.Compile(k.Pattern)
+	if err != nil {
+		return fmt.Errorf("keep_latest pattern: %w", err)
+	}
+	switch k.Order {
+	case "":
+		k.Order = OrderMtime
+	case OrderMtime:
+	case OrderSemver:
+		if re.SubexpIndex("version") < 0 {
+			return errors.New(`keep_latest order semver needs a "version" group in pattern`)
+		}
+	default:
+		return fmt.Errorf("keep_latest order must be %q or %q, got %q", OrderMtime, OrderSemver, k.Order)
+	}
+	k.re = re
+	return nil
+}
+
+// Match reports whether keep_latest manages a file named name, and returns the
+// values of the pattern's "name" and "version" groups
+func (k *KeepLatest) Match(name string) (group, version string, ok bool) {
+	if k.re == nil {
+		if k.validate() != nil {
+			return "", "", false
+		}
+	}
+	m := k.re.FindStringSubmatch(name)
+	if m == nil {
+		return "", "", false
+	}
+	if i := k.re.SubexpIndex("name"); i >= 0 {
+		group = m[i]
+	}
+	if i := k.re.SubexpIndex("version"); i >= 0 {
+		version = m[i]
+	}
+	return group, version, true
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateKeepLatest(t *testing.T) {
+	tests := []struct {
+		name      string
+		keep      KeepLatest
+		wantOrder string
+		wantErr   string
+	}{
+		{name: "by mtime per directory", keep: KeepLatest{Count: 3}, wantOrder: OrderMtime},
+		{name: "by semver per name", keep: KeepLatest{Count: 2, Pattern: `^(?P<name>.+)-(?P<version>[\d.]+)\.tar\.gz$`, Order: "semver"}, wantOrder: OrderSemver},
+		{name: "keeps nothing", keep: KeepLatest{}, wantErr: "at least one"},
+		{name: "semver without version group", keep: KeepLatest{Count: 2, Pattern: `\.tar\.gz$`, Order: "semver"}, wantErr: `"version" group`},
+		{name: "unknown order", keep: KeepLatest{Count: 2, Order: "name"}, wantErr: "order must be"},
+		{name: "bad pattern", keep: KeepLatest{Count: 2, Pattern: `(`}, wantErr: "keep_latest pattern"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.keep.validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validate: %v", err)
+				}
+				if tt.keep.Order != tt.wantOrder {
+					t.Errorf("order = %q, want %q", tt.keep.Order, tt.wantOrder)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("validate() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Overlap settings for a rule whose root lies below another configured root.
+// Either way the deepest root owns the files below it and walks of enclosing
+// rules skip it; the setting only decides where unset values come from.
+const (
+	OverlapOverride = "override" // Unset values take the built-in defaults (default)
+	OverlapInherit  = "inherit"  // Unset values are copied from the enclosing rule
+)
+
+// Within reports whether path is root or lies below it. Both must be clean.
+func Within(path, root string) bool {
+	if path == root || root == string(os.PathSeparator) {
+		return true
+	}
+	return strings.HasPrefix(path, root+string(os.PathSeparator))
+}
+
+// Roots returns every configured root once: paths entries first, then the
+// scan_paths entries without a paths entry of their own
+func (c *Config) Roots() []string {
+	roots := make([]string, 0, len(c.Paths)+len(c.ScanPaths))
+	seen := make(map[string]bool, cap(roots))
+	for _, r := range c.Paths {
+		if !seen[r.Path] {
+			seen[r.Path] = true
+			roots = append(roots, r.Path)
+		}
+	}
+	for _, p := range c.ScanPaths {
+		if !seen[p] {
+			seen[p] = true
+			roots = append(roots, p)
+		}
+	}
+	return roots
+}
+
+// NestedRoots returns the configured roots strictly below root. Their
+// subtrees belong to their own rules, so a walk of root must skip them.
+func (c *Config) NestedRoots(root string) []string {
+	var nested []string
+	for _, r := range c.Roots() {
+		if r != root && Within(r, root) {
+			nested = append(nested, r)
+		}
+	}
+	return nested
+}
+
+// enclosingRoot returns the deepest configured root strictly above root, or "" if there is none
+func (c *Config) enclosingRoot(root string) string {
+	parent := ""
+	for _, r := range c.Roots() {
+		if r != root && Within(root, r) && len(r) > len(parent) {
+			parent = r
+		}
+	}
+	return parent
+}
+
+// resolveOverlaps rejects ambiguous rule sets and fills the unset values of
+// inherit rules from their enclosing rule. Paths must already be clean.
+func (c *Config) resolveOverlaps() error {
+	byPath := make(map[string]*PathRule, len(c.Paths))
+	for i := range c.Paths {
+		r := &c.Paths[i]
+		if byPath[r.Path] != nil {
+			return fmt.Errorf("path %s is configured more than once in paths; merge the rules so every file has exactly one", r.Path)
+		}
+		byPath[r.Path] = r
+		switch r.Overlap {
+		case "", OverlapOverride, OverlapInherit:
+		default:
+			return fmt.Errorf("path %s: overlap must be %q or %q, got %q", r.Path, OverlapOverride, OverlapInherit, r.Overlap)
+		}
+	}
+
+	// Resolve shallow rules first so an inherit chain sees its parent's final values
+	rules := make([]*PathRule, 0, len(c.Paths))
+	for _, r := range byPath {
+		rules = append(rules, r)
+	}
+	sort.Slice(rules, func(i, j int) bool { return len(rules[i].Path) < len(rules[j].Path) })
+
+	for _, r := range rules {
+		if r.Overlap != OverlapInherit {
+			continue
+		}
+		parent := c.enclosingRoot(r.Path)
+		if parent == "" {
+			return fmt.Errorf("path %s: overlap is inherit but no configured root encloses it", r.Path)
+		}
+		from, ok := byPath[parent]
+		if !ok {
+			// A scan_paths root applies the global settings
+			from = &PathRule{AgeOffDays: c.AgeOffDays, MaxAge: c.MaxAge, MinFreePercent: c.MinFreePercent}
+		}
+		r.inherit(from)
+	}
+	return nil
+}
+
+// inherit copies every value r leaves unset from parent
+func (r *PathRule) inherit(parent *PathRule) {
+	fill := func(v *int, from int) {
+		if *v == 0 {
+			*v = from
+		}
+	}
+	if r.AgeOffDays == 0 && r.MaxAge == 0 {
+		r.AgeOffDays, r.MaxAge = parent.AgeOffDays, parent.MaxAge
+	}
+	fill(&r.MinFreePercent, parent.MinFreePercent)
+	fill(&r.TargetFreePercent, parent.TargetFreePercent)
+	fill(&r.Priority, parent.Priority)
+	fill(&r.MinRetentionDays, parent.MinRetentionDays)
+	fill(&r.TierCooldownMinutes, parent.TierCooldownMinutes)
+	if r.TierHysteresisPercent == 0 {
+		r.TierHysteresisPercent = parent.TierHysteresisPercent
+	}
+	if r.AgeSource == "" && r.AgeLayout == "" && r.AgePattern == "" {
+		r.AgeSource, r.AgeLayout, r.AgePattern = parent.AgeSource, parent.AgeLayout, parent.AgePattern
+	}
+
+	// The escalation settings come as a set: tiers or the legacy thresholds, never a
+	// mix, and none for rules that keep by count
+	legacy := r.MaxFreePercent != 0 || r.StackThreshold != 0 || r.StackAgeDays != 0 || r.StackMaxAge != 0
+	switch {
+	case r.Tiered() || r.KeepsByCount():
+	case !legacy && parent.Tiered():
+		r.Tiers = append([]Tier(nil), parent.Tiers...)
+	default:
+		fill(&r.MaxFreePercent, parent.MaxFreePercent)
+		fill(&r.StackThreshold, parent.StackThreshold)
+		if r.StackAgeDays == 0 && r.StackMaxAge == 0 {
+			r.StackAgeDays, r.StackMaxAge = parent.StackAgeDays, parent.StackMaxAge
+		}
+	}
+	if r.Compress == nil && parent.Compress != nil && r.Compresses() {
+		settings := *parent.Compress
+		r.Compress = &settings
+	}
+	// Under tiers only a truncate tier truncates, and there is no STACK mode to wait for
+	if r.Truncate == nil && parent.Truncate != nil && !r.KeepsByCount() && (!r.Tiered() || r.TruncateTiered()) {
+		settings := *parent.Truncate
+		settings.Stack = settings.Stack && !r.Tiered()
+		r.Truncate = &settings
+	}
+	if !r.BlastRadius.Enabled() {
+		r.BlastRadius = parent.BlastRadius
+	}
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveOverlaps(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+		check   func(t *testing.T, cfg *Config)
+	}{
+		{
+			name: "duplicate root",
+			cfg: Config{Paths: []PathRule{
+				{Path: "/data/logs", AgeOffDays: 7},
+				{Path: "/data/logs/", AgeOffDays: 30},
+			}},
+			wantErr: "configured more than once",
+		},
+		{
+			name:    "unknown overlap",
+			cfg:     Config{Paths: []PathRule{{Path: "/data"}, {Path: "/data/logs", Overlap: "merge"}}},
+			wantErr: "overlap must be",
+		},
+		{
+			name:    "inherit without enclosing root",
+			cfg:     Config{Paths: []PathRule{{Path: "/data/logs", Overlap: OverlapInherit}}},
+			wantErr: "no configured root encloses it",
+		},
+		{
+			name: "inherit chain",
+			cfg: Config{Paths: []PathRule{
+				{Path: "/data/logs/app/debug", Overlap: OverlapInherit, StackAgeDays: 3},
+				{Path: "/data/logs/app", Overlap: OverlapInherit, AgeOffDays: 7},
+				{Path: "/data", AgeOffDays: 30, MaxFreePercent: 85, Priority: 2},
+			}},
+			check: func(t *testing.T, cfg *Config) {
+				debug := cfg.Paths[0]
+				if debug.AgeOffDays != 7 || debug.MaxFreePercent != 85 || debug.Priority != 2 || debug.StackAgeDays != 3 {
+					t.Errorf("debug rule = %+v, want age 7, max 85, priority 2, stack age 3", debug)
+				}
+			},
+		},
+		{
+			name: "override takes defaults",
+			cfg: Config{Paths: []PathRule{
+				{Path: "/data", AgeOffDays: 30, MaxFreePercent: 85},
+				{Path: "/data/logs", AgeOffDays: 7},
+			}},
+			check: func(t *testing.T, cfg *Config) {
+				if logs := cfg.Paths[1]; logs.MaxFreePercent != 90 {
+					t.Errorf("override rule max_free_percent = %d, want default 90", logs.MaxFreePercent)
+				}
+			},
+		},
+		{
+			name: "inherit from scan_paths",
+			cfg: Config{
+				ScanPaths:  []string{"/data", "/data/"},
+				AgeOffDays: 14,
+				Paths:      []PathRule{{Path: "/data/logs", Overlap: OverlapInherit}},
+			},
+			check: func(t *testing.T, cfg *Config) {
+				if len(cfg.ScanPaths) != 1 {
+					t.Errorf("scan_paths = %v, want duplicates dropped", cfg.ScanPaths)
+				}
+				if logs := cfg.Paths[0]; logs.AgeOffDays != 14 {
+					t.Errorf("inherited age_off_days = %d, want 14", logs.AgeOffDays)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			err := cfg.validateAndDefault()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("validateAndDefault() = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("validateAndDefault: %v", err)
+			}
+			tt.check(t, &cfg)
+		})
+	}
+}
+
+func TestNestedRoots(t *testing.T) {
+	cfg := &Config{
+		ScanPaths: []string{"/data", "/srv"},
+		Paths:     []PathRule{{Path: "/data/logs"}, {Path: "/data/logs/app"}, {Path: "/database"}},
+	}
+	got := cfg.NestedRoots("/data")
+	if strings.Join(got, ",") != "/data/logs,/data/logs/app" {
+		t.Errorf("NestedRoots(/data) = %v", got)
+	}
+	if got := cfg.NestedRoots("/srv"); len(got) != 0 {
+		t.Errorf("NestedRoots(/srv) = %v, want none", got)
+	}
+}
+
+func TestComplianceFloorsCoverNestedRules(t *testing.T) {
+	cfg := Config{Paths: []PathRule{
+		{Path: "/data", MinRetentionDays: 30, ComplianceMode: true},
+		{Path: "/data/cache", MinRetentionDays: 5},
+		{Path: "/data/keep", MinRetentionDays: 90},
+		{Path: "/scratch", MinRetentionDays: 2},
+	}}
+	if err := cfg.validateAndDefault(); err != nil {
+		t.Fatalf("validateAndDefault: %v", err)
+	}
+	want := []struct {
+		days       int
+		compliance bool
+	}{{30, true}, {30, true}, {90, true}, {2, false}}
+	for i, w := range want {
+		r := cfg.Paths[i]
+		if r.MinRetentionDays != w.days || r.ComplianceMode != w.compliance {
+			t.Errorf("%s: min_retention_days=%d compliance=%v, want %d %v", r.Path, r.MinRetentionDays, r.ComplianceMode, w.days, w.compliance)
+		}
+	}
+
+	bad := Config{Paths: []PathRule{{Path: "/data", ComplianceMode: true}}}
+	if err := bad.validateAndDefault(); err == nil || !strings.Contains(err.Error(), "requires min_retention_days") {
+		t.Errorf("compliance_mode without a floor: err = %v", err)
+	}
+}
@@ -0,0 +1,27 @@
+package config
+
+// ComplianceFloor returns the largest min_retention_days of the compliance
+// rules at or above root, or 0 when no compliance rule covers it
+func (c *Config) ComplianceFloor(root string) int {
+	floor := 0
+	for _, r := range c.Paths {
+		if r.ComplianceMode && Within(root, r.Path) && r.MinRetentionDays > floor {
+			floor = r.MinRetentionDays
+		}
+	}
+	return floor
+}
+
+// applyComplianceFloors extends each compliance floor to the rules nested
+// below it, which then run in compliance mode themselves
+func (c *Config) applyComplianceFloors() {
+	for i := range c.Paths {
+		r := &c.Paths[i]
+		if floor := c.ComplianceFloor(r.Path); floor > 0 {
+			r.ComplianceMode = true
+			if r.MinRetentionDays < floor {
+				r.MinRetentionDays = floor
+			}
+		}
+	}
+}
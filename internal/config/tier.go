@@ -0,0 +1,108 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Eviction strategies: the order in which a tier removes the files it selects
+const (
+	StrategyOldest  = "oldest"  // Least recently modified first (default)
+	StrategyLargest = "largest" // Biggest files first, freeing space with the fewest deletes
+)
+
+// Tier is one step of a rule's escalation ladder: at or above UsagePercent
+// disk usage, files at least MinAgeLimit old are deleted, or compressed or
+// truncated by a tier with that action
+type Tier struct {
+	UsagePercent float64  `yaml:"usage_percent" json:"usage_percent"` // Disk usage at which the tier engages (e.g., 92)
+	MinAgeDays   int      `yaml:"min_age_days" json:"min_age_days"`   // Files younger than this are kept (0 = any age)
+	MinAge       Duration `yaml:"min_age" json:"min_age,omitempty"`   // min_age_days as a duration, e.g. "12h"; replaces it
+	Strategy     string   `yaml:"strategy" json:"strategy"`           // "oldest" (default) or "largest"
+	Action       string   `yaml:"action" json:"action,omitempty"`     // "delete" (default), "compress" or "truncate"
+}
+
+// Tiered reports whether the rule escalates through tiers instead of the
+// max_free_percent/stack_threshold modes
+func (r *PathRule) Tiered() bool {
+	return len(r.Tiers) > 0
+}
+
+// TriggerPercent returns the disk usage at which the rule starts deleting regardless of age_off_days
+func (r *PathRule) TriggerPercent() float64 {
+	if r.Tiered() {
+		for _, t := range r.Tiers {
+			if t.Deletes() {
+				return t.UsagePercent
+			}
+		}
+		return 101 // A ladder that only compresses never deletes for disk usage
+	}
+	return float64(r.MaxFreePercent)
+}
+
+// TierCooldown returns how long a rule stays at a tier before it may step down
+func (r *PathRule) TierCooldown() time.Duration {
+	return time.Duration(r.TierCooldownMinutes) * time.Minute
+}
+
+// validateTiers sorts the ladder by threshold and rejects ladders where a
+// higher tier would keep files a lower tier deletes
+func (r *PathRule) validateTiers() error {
+	if !r.Tiered() {
+		return nil
+	}
+	if r.MaxFreePercent != 0 || r.StackThreshold != 0 || r.StackAgeDays != 0 || r.StackMaxAge != 0 {
+		return errors.New("tiers replace max_free_percent, stack_threshold and stack_age_days; set one or the other")
+	}
+	if r.TierHysteresisPercent < 0 || r.TierCooldownMinutes < 0 {
+		return errors.New("tier_hysteresis_percent and tier_cooldown_minutes cannot be negative")
+	}
+
+	sort.SliceStable(r.Tiers, func(i, j int) bool { return r.Tiers[i].UsagePercent < r.Tiers[j].UsagePercent })
+	for i, t := range r.Tiers {
+		switch t.Action {
+		case "", ActionDelete, ActionCompress, ActionTruncate:
+		default:
+			return fmt.Errorf("tier at %g%%: action must be %q, %q or %q, got %q", t.UsagePercent, ActionDelete, ActionCompress, ActionTruncate, t.Action)
+		}
+		// A compress tier at 0% compresses at any disk usage
+		if t.UsagePercent < 0 || t.UsagePercent > 100 || (t.UsagePercent == 0 && !t.Compresses()) {
+			return fmt.Errorf("tier usage_percent must be between 0 and 100, got %g", t.UsagePercent)
+		}
+		if t.MinAgeDays < 0 || t.MinAge < 0 {
+			return fmt.Errorf("tier at %g%%: min_age_days cannot be negative", t.UsagePercent)
+		}
+		switch t.Strategy {
+		case "", StrategyOldest, StrategyLargest:
+		default:
+			return fmt.Errorf("tier at %g%%: strategy must be %q or %q, got %q", t.UsagePercent, StrategyOldest, StrategyLargest, t.Strategy)
+		}
+		if i == 0 {
+			continue
+		}
+		if t.UsagePercent == r.Tiers[i-1].UsagePercent {
+			return fmt.Errorf("two tiers engage at %g%%", t.UsagePercent)
+		}
+		// Delete, compress and truncate tiers escalate separately, so a tier compares with the lower tiers of its action
+		j := r.EngagedTier(i, t.action())
+		if j == 0 {
+			continue
+		}
+		prev := r.Tiers[j-1]
+		if t.MinAgeLimit() > prev.MinAgeLimit() {
+			verb := "deletes"
+			switch {
+			case t.Compresses():
+				verb = "compresses"
+			case t.Truncates():
+				verb = "truncates"
+			}
+			return fmt.Errorf("tier at %g%%: min age %s is above the %s of the tier at %g%%; a higher tier must not spare files a lower one %s",
+				t.UsagePercent, FormatDuration(t.MinAgeLimit()), FormatDuration(prev.MinAgeLimit()), prev.UsagePercent, verb)
+		}
+	}
+	return nil
+}
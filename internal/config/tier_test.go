@@ -0,0 +1,92 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateTiers(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    PathRule
+		wantErr string
+	}{
+		{
+			name: "unsorted ladder",
+			rule: PathRule{Tiers: []Tier{{UsagePercent: 98, MinAgeDays: 1}, {UsagePercent: 85, MinAgeDays: 30}, {UsagePercent: 92, MinAgeDays: 7}}},
+		},
+		{
+			name:    "mixed with legacy thresholds",
+			rule:    PathRule{StackThreshold: 95, Tiers: []Tier{{UsagePercent: 85}}},
+			wantErr: "tiers replace",
+		},
+		{
+			name:    "higher tier spares files",
+			rule:    PathRule{Tiers: []Tier{{UsagePercent: 85, MinAgeDays: 7}, {UsagePercent: 92, MinAgeDays: 30}}},
+			wantErr: "must not spare",
+		},
+		{
+			name: "sub-day ages mixed with days",
+			rule: PathRule{Tiers: []Tier{{UsagePercent: 85, MinAgeDays: 2}, {UsagePercent: 92, MinAge: Duration(12 * time.Hour)}, {UsagePercent: 98}}},
+		},
+		{
+			name:    "higher tier spares files by hours",
+			rule:    PathRule{Tiers: []Tier{{UsagePercent: 85, MinAge: Duration(6 * time.Hour)}, {UsagePercent: 92, MinAgeDays: 1}}},
+			wantErr: "must not spare",
+		},
+		{
+			name:    "duplicate threshold",
+			rule:    PathRule{Tiers: []Tier{{UsagePercent: 90, MinAgeDays: 7}, {UsagePercent: 90, MinAgeDays: 1}}},
+			wantErr: "two tiers engage",
+		},
+		{
+			name:    "threshold out of range",
+			rule:    PathRule{Tiers: []Tier{{UsagePercent: 120}}},
+			wantErr: "between 0 and 100",
+		},
+		{
+			name:    "unknown strategy",
+			rule:    PathRule{Tiers: []Tier{{UsagePercent: 90, Strategy: "random"}}},
+			wantErr: "strategy must be",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.validateTiers()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("validateTiers() = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("validateTiers: %v", err)
+			}
+			for i := 1; i < len(tt.rule.Tiers); i++ {
+				if tt.rule.Tiers[i].UsagePercent <= tt.rule.Tiers[i-1].UsagePercent {
+					t.Errorf("tiers not sorted: %+v", tt.rule.Tiers)
+				}
+			}
+		})
+	}
+}
+
+func TestInheritTiers(t *testing.T) {
+	cfg := Config{Paths: []PathRule{
+		{Path: "/data", Tiers: []Tier{{UsagePercent: 85, MinAgeDays: 30}, {UsagePercent: 95, MinAgeDays: 3}}, TierCooldownMinutes: 10},
+		{Path: "/data/cache", Overlap: OverlapInherit},
+		{Path: "/data/logs", Overlap: OverlapInherit, MaxFreePercent: 80},
+	}}
+	if err := cfg.validateAndDefault(); err != nil {
+		t.Fatalf("validateAndDefault: %v", err)
+	}
+	cache, logs := cfg.Paths[1], cfg.Paths[2]
+	if len(cache.Tiers) != 2 || cache.TierCooldownMinutes != 10 || cache.TierHysteresisPercent != 2 {
+		t.Errorf("cache rule = %+v, want the parent's tiers and cooldown", cache)
+	}
+	if logs.Tiered() || logs.MaxFreePercent != 80 || logs.StackThreshold != 98 {
+		t.Errorf("logs rule = %+v, want its own legacy thresholds", logs)
+	}
+}
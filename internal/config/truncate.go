@@ -0,0 +1,63 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ActionTruncate is the tier action that truncates oversized files in place
+const ActionTruncate = "truncate"
+
+// Truncate empties regular files over a size limit in place, keeping the last
+// KeepTailBytes. Unlike a delete this frees the space of a file a process
+// still holds open.
+type Truncate struct {
+	MaxSizeBytes  int64 `yaml:"max_size_bytes" json:"max_size_bytes"`   // Files larger than this are truncated
+	KeepTailBytes int64 `yaml:"keep_tail_bytes" json:"keep_tail_bytes"` // Bytes kept from the end of the file (0 = empty it)
+
+	// Truncate only in STACK mode, at or above stack_threshold (rules without tiers;
+	// tiered rules use a tier with action truncate instead)
+	Stack bool `yaml:"stack" json:"stack"`
+}
+
+// Truncates reports whether the tier truncates oversized files instead of deleting them
+func (t Tier) Truncates() bool {
+	return t.action() == ActionTruncate
+}
+
+// TruncateTiered reports whether the rule truncates only while a truncate tier is engaged
+func (r *PathRule) TruncateTiered() bool {
+	for _, t := range r.Tiers {
+		if t.Truncates() {
+			return true
+		}
+	}
+	return false
+}
+
+// validateTruncate rejects truncate tiers without size settings and sizes that would keep the whole file
+func (r *PathRule) validateTruncate() error {
+	if r.Truncate == nil {
+		if r.TruncateTiered() {
+			return errors.New("a tier with action truncate needs truncate.max_size_bytes")
+		}
+		return nil
+	}
+	if r.KeepsByCount() {
+		return errors.New("truncate does not combine with gfs or keep_latest")
+	}
+	t := r.Truncate
+	if t.MaxSizeBytes <= 0 {
+		return errors.New("truncate.max_size_bytes must be positive")
+	}
+	if t.KeepTailBytes < 0 || t.KeepTailBytes >= t.MaxSizeBytes {
+		return fmt.Errorf("truncate.keep_tail_bytes must be at least 0 and below max_size_bytes (%d), got %d", t.MaxSizeBytes, t.KeepTailBytes)
+	}
+	if t.Stack && r.Tiered() {
+		return errors.New("truncate.stack is for rules without tiers; add a tier with action truncate instead")
+	}
+	if r.Tiered() && !r.TruncateTiered() {
+		return errors.New("truncate on a rule with tiers needs a tier with action truncate")
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateTruncate(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{name: "baseline", yaml: "truncate: {max_size_bytes: 1073741824, keep_tail_bytes: 1048576}"},
+		{name: "stack", yaml: "max_age_days: 7\n    truncate: {max_size_bytes: 1048576, stack: true}"},
+		{
+			name: "truncate tier",
+			yaml: "truncate: {max_size_bytes: 1048576}\n    tiers:\n      - {usage_percent: 85, min_age: 7d}\n      - {usage_percent: 95, action: truncate}",
+		},
+		{name: "tier without settings", yaml: "tiers:\n      - {usage_percent: 95, action: truncate}", wantErr: "needs truncate.max_size_bytes"},
+		{name: "no size", yaml: "truncate: {keep_tail_bytes: 10}", wantErr: "must be positive"},
+		{name: "tail keeps everything", yaml: "truncate: {max_size_bytes: 100, keep_tail_bytes: 100}", wantErr: "below max_size_bytes"},
+		{name: "negative tail", yaml: "truncate: {max_size_bytes: 100, keep_tail_bytes: -1}", wantErr: "at least 0"},
+		{
+			name:    "stack with tiers",
+			yaml:    "truncate: {max_size_bytes: 100, stack: true}\n    tiers:\n      - {usage_percent: 95}",
+			wantErr: "add a tier with action truncate",
+		},
+		{
+			name:    "tiers without a truncate tier",
+			yaml:    "truncate: {max_size_bytes: 100}\n    tiers:\n      - {usage_percent: 95}",
+			wantErr: "needs a tier with action truncate",
+		},
+		{
+			name:    "with keep_latest",
+			yaml:    "truncate: {max_size_bytes: 100}\n    keep_latest: {count: 3}",
+			wantErr: "does not combine",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := decode(strings.NewReader("paths:\n  - path: /var/log/app\n    " + tt.yaml + "\n"))
+			if err == nil {
+				err = cfg.validateAndDefault()
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package control
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Client talks to a daemon's control API
+type Client struct {
+	base  string
+	token string
+	http  *http.Client
+}
+
+// NewClient connects to addr, which is either a Unix socket path (optionally
+// prefixed with unix://) or an http:// or https:// URL. The token is sent as a
+// bearer token and is only required for TCP listeners.
+func NewClient(addr, token string) (*Client, error) {
+	c := &Client{token: token, http: &http.Client{Timeout: 10 * time.Second}}
+
+	switch {
+	case strings.HasPrefix(addr, "http://"), strings.HasPrefix(addr, "https://"):
+		c.base = strings.TrimSuffix(addr, "/")
+	case strings.HasPrefix(addr, "unix://"), strings.HasPrefix(addr, "/"):
+		socket := strings.TrimPrefix(addr, "unix://")
+		c.base = "http://unix"
+		c.http.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			},
+		}
+	default:
+		return nil, fmt.Errorf("control: unsupported address %q (want a socket path or http(s) URL)", addr)
+	}
+	return c, nil
+}
+
+// Trigger asks the daemon to run a cleanup cycle now, limited to req.Rules when set
+func (c *Client) Trigger(ctx context.Context, req TriggerRequest) error {
+	return c.do(ctx, http.MethodPost, "/v1/trigger", req, nil)
+}
+
+// Reload asks the daemon to re-read its configuration
+func (c *Client) Reload(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/v1/reload", nil, nil)
+}
+
+// Pause stops cleanup for req.Rules, or for the whole daemon when no rules are given
+func (c *Client) Pause(ctx context.Context, req PauseRequest) error {
+	return c.do(ctx, http.MethodPost, "/v1/pause", req, nil)
+}
+
+// Resume lifts the pause for req.Rules, or the global pause when no rules are given
+func (c *Client) Resume(ctx context.Context, req PauseRequest) error {
+	return c.do(ctx, http.MethodPost, "/v1/resume", req, nil)
+}
+
+// Override lets the daemon's next cycle exceed its blast-radius limits once
+func (c *Client) Override(ctx context.Context, req OverrideRequest) error {
+	return c.do(ctx, http.MethodPost, "/v1/override", req, nil)
+}
+
+// Status returns the daemon's current state
+func (c *Client) Status(ctx context.Context) (Status, error) {
+	var st Status
+	err := c.do(ctx, http.MethodGet, "/v1/status", nil, &st)
+	return st, err
+}
+
+func (c *Client) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.base+path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("control: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("control: read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error string `json:"error"`
+		}
+		_ = json.Unmarshal(respBody, &e)
+		switch {
+		case resp.StatusCode == http.StatusUnauthorized:
+			return ErrUnauthorized
+		case resp.StatusCode == http.StatusNotFound && e.Error == ErrUnknownRule.Error():
+			return ErrUnknownRule
+		case resp.StatusCode == http.StatusConflict:
+			return ErrPaused
+		case resp.StatusCode == http.StatusServiceUnavailable:
+			return ErrBusy
+		}
+		if e.Error == "" {
+			e.Error = http.StatusText(resp.StatusCode)
+		}
+		return fmt.Errorf("control: daemon returned %d: %s", resp.StatusCode, e.Error)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("control: decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+// Package control serves the daemon's control API (trigger, reload, pause,
+// resume, override, status) and provides the client used by `storage-sage ctl` and the
+// web backend.
+//
+// The API is plain HTTP with JSON bodies, served on a Unix socket that admits
+// peers by SO_PEERCRED and, optionally, on a TCP address that requires a
+// bearer token. It is deliberately kept off the unauthenticated metrics port.
+package control
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrPaused is returned by Trigger while cleanup is paused
+	ErrPaused = errors.New("cleanup is paused")
+	// ErrBusy is returned when a request of the same kind is already queued
+	ErrBusy = errors.New("request already queued")
+	// ErrUnauthorized is returned by the client when the daemon rejects its credentials
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrUnknownRule is returned when a request names a rule that is not configured
+	ErrUnknownRule = errors.New("unknown rule")
+)
+
+// TriggerRequest is the optional body of POST /v1/trigger
+type TriggerRequest struct {
+	Rules  []string `json:"rules,omitempty"`   // Rule paths to run; empty runs every active rule
+	DryRun bool     `json:"dry_run,omitempty"` // Run this cycle as a dry run
+}
+
+// PauseRequest is the optional body of POST /v1/pause and /v1/resume
+type PauseRequest struct {
+	Rules  []string `json:"rules,omitempty"`  // Rule paths to pause or resume; empty means the whole daemon
+	Reason string   `json:"reason,omitempty"` // Recorded with the pause
+	By     string   `json:"by,omitempty"`     // Who paused, for the audit trail
+}
+
+// OverrideRequest is the optional body of POST /v1/override
+type OverrideRequest struct {
+	Reason string `json:"reason,omitempty"` // Why the limits may be exceeded, for the audit trail
+	By     string `json:"by,omitempty"`     // Who armed the override
+}
+
+// RuleStatus is the pause state of one configured rule
+type RuleStatus struct {
+	Path     string    `json:"path"`
+	Paused   bool      `json:"paused"`
+	Reason   string    `json:"reason,omitempty"`
+	PausedBy string    `json:"paused_by,omitempty"`
+	PausedAt time.Time `json:"paused_at,omitempty"`
+}
+
+// Status is the daemon state returned by GET /v1/status
+type Status struct {
+	PID         int          `json:"pid"`
+	DryRun      bool         `json:"dry_run"`
+	Paused      bool         `json:"paused"`
+	PauseReason string       `json:"pause_reason,omitempty"`
+	Running     bool         `json:"running"`
+	Interval    string       `json:"interval"`
+	LastRun     time.Time    `json:"last_run"`
+	LastError   string       `json:"last_error,omitempty"`
+	NextRun     time.Time    `json:"next_run"`
+	Rules       []RuleStatus `json:"rules"`
+
+	OverrideArmed bool   `json:"override_armed"`        // The next cycle may exceed its blast-radius limits
+	OverrideBy    string `json:"override_by,omitempty"` // Who armed the override, and why
+}
+
+// Daemon is implemented by the process being controlled
+type Daemon interface {
+	Trigger(req TriggerRequest) error
+	Reload() error
+	Pause(req PauseRequest) error
+	Resume(req PauseRequest) error
+	Override(req OverrideRequest) error
+	Status() Status
+}
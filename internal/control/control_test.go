@@ -0,0 +1,283 @@
+package control
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeDaemon struct {
+	mu          sync.Mutex
+	paused      bool
+	pausedBy    string
+	triggers    int
+	reloads     int
+	overrides   int
+	overrideBy  string
+	lastTrigger TriggerRequest
+}
+
+func (d *fakeDaemon) Trigger(req TriggerRequest) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, r := range req.Rules {
+		if r != "/data" {
+			return ErrUnknownRule
+		}
+	}
+	if d.paused && !req.DryRun {
+		return ErrPaused
+	}
+	d.triggers++
+	d.lastTrigger = req
+	return nil
+}
+
+func (d *fakeDaemon) Reload() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.reloads++
+	return nil
+}
+
+func (d *fakeDaemon) Pause(req PauseRequest) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.paused = true
+	d.pausedBy = req.By
+	return nil
+}
+
+func (d *fakeDaemon) Resume(req PauseRequest) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.paused = false
+	return nil
+}
+
+func (d *fakeDaemon) Override(req OverrideRequest) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.overrides++
+	d.overrideBy = req.By
+	return nil
+}
+
+func (d *fakeDaemon) Status() Status {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return Status{PID: 42, Paused: d.paused, Interval: "15m0s", OverrideArmed: d.overrides > 0}
+}
+
+func quietLogger() *log.Logger {
+	return log.New(io.Discard, "", 0)
+}
+
+func TestUnixSocketRoundTrip(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "run", "control.sock")
+	d := &fakeDaemon{}
+	srv := NewServer(d, Options{Socket: socket}, quietLogger())
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer srv.Shutdown(context.Background())
+
+	info, err := os.Stat(socket)
+	if err != nil {
+		t.Fatalf("stat socket: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0660 {
+		t.Errorf("socket mode = %o, want 660", perm)
+	}
+
+	client, err := NewClient(socket, "")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	ctx := context.Background()
+
+	// The test process is always the daemon's own uid, so the peer is admitted
+	if err := client.Trigger(ctx, TriggerRequest{}); err != nil {
+		t.Fatalf("Trigger: %v", err)
+	}
+	if err := client.Reload(ctx); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if err := client.Pause(ctx, PauseRequest{Reason: "incident"}); err != nil {
+		t.Fatalf("Pause: %v", err)
+	}
+	if want := fmt.Sprintf("uid:%d", os.Getuid()); d.pausedBy != want {
+		t.Errorf("pausedBy = %q, want %q", d.pausedBy, want)
+	}
+	if err := client.Trigger(ctx, TriggerRequest{}); !errors.Is(err, ErrPaused) {
+		t.Errorf("Trigger while paused = %v, want ErrPaused", err)
+	}
+
+	// A dry-run trigger scoped to one rule passes through while paused
+	if err := client.Trigger(ctx, TriggerRequest{Rules: []string{"/data"}, DryRun: true}); err != nil {
+		t.Fatalf("scoped dry-run Trigger: %v", err)
+	}
+	if got := d.lastTrigger; len(got.Rules) != 1 || got.Rules[0] != "/data" || !got.DryRun {
+		t.Errorf("lastTrigger = %+v, want /data dry run", got)
+	}
+	if err := client.Trigger(ctx, TriggerRequest{Rules: []string{"/nope"}, DryRun: true}); !errors.Is(err, ErrUnknownRule) {
+		t.Errorf("Trigger unknown rule = %v, want ErrUnknownRule", err)
+	}
+
+	if err := client.Override(ctx, OverrideRequest{Reason: "planned purge"}); err != nil {
+		t.Fatalf("Override: %v", err)
+	}
+	if want := fmt.Sprintf("uid:%d", os.Getuid()); d.overrideBy != want {
+		t.Errorf("overrideBy = %q, want %q", d.overrideBy, want)
+	}
+
+	st, err := client.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if !st.Paused || st.PID != 42 || !st.OverrideArmed {
+		t.Errorf("Status = %+v, want paused with pid 42 and override armed", st)
+	}
+
+	if err := client.Resume(ctx, PauseRequest{}); err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+	if d.triggers != 2 || d.reloads != 1 || d.paused {
+		t.Errorf("daemon state = %+v, want 2 triggers, 1 reload, not paused", d)
+	}
+
+	if err := srv.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if _, err := os.Stat(socket); !os.IsNotExist(err) {
+		t.Errorf("socket not removed on shutdown: %v", err)
+	}
+}
+
+func TestUnixSocketRefusesRegularFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "control.sock")
+	if err := os.WriteFile(path, []byte("keep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	srv := NewServer(&fakeDaemon{}, Options{Socket: path}, quietLogger())
+	if err := srv.Start(); err == nil {
+		srv.Shutdown(context.Background())
+		t.Fatal("Start replaced a regular file with a socket")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "keep" {
+		t.Error("regular file was modified")
+	}
+}
+
+func TestPeerAllowList(t *testing.T) {
+	srv := NewServer(&fakeDaemon{}, Options{AllowUIDs: []uint32{1001}, AllowGIDs: []uint32{2000}}, quietLogger())
+
+	tests := []struct {
+		name  string
+		peer  peer
+		allow bool
+	}{
+		{"root", peer{unix: true, uid: 0, gid: 0}, true},
+		{"self", peer{unix: true, uid: uint32(os.Getuid()), gid: 9999}, true},
+		{"allowed uid", peer{unix: true, uid: 1001, gid: 9999}, true},
+		{"allowed gid", peer{unix: true, uid: 5000, gid: 2000}, true},
+		{"stranger", peer{unix: true, uid: 5000, gid: 5000}, false},
+		{"credential error", peer{unix: true, err: errors.New("no creds")}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.name == "stranger" && os.Getuid() == 5000 {
+				t.Skip("test process runs as the stranger uid")
+			}
+			err := srv.allowPeer(tt.peer)
+			if (err == nil) != tt.allow {
+				t.Errorf("allowPeer = %v, want allow=%v", err, tt.allow)
+			}
+		})
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	d := &fakeDaemon{}
+	srv := NewServer(d, Options{Token: "s3cret"}, quietLogger())
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"valid token", "s3cret", nil},
+		{"wrong token", "guess", ErrUnauthorized},
+		{"no token", "", ErrUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(ts.URL, tt.token)
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			if err := client.Trigger(ctx, TriggerRequest{}); !errors.Is(err, tt.want) {
+				t.Errorf("Trigger = %v, want %v", err, tt.want)
+			}
+		})
+	}
+	if d.triggers != 1 {
+		t.Errorf("triggers = %d, want 1", d.triggers)
+	}
+
+	// Request bodies are validated
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/v1/trigger", strings.NewReader(`{"rulez": ["/data"]}`))
+	req.Header.Set("Authorization", "Bearer s3cret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("POST /v1/trigger with unknown field = %d, want 400", resp.StatusCode)
+	}
+
+	// Authentication is checked before the method
+	resp, err = http.Get(ts.URL + "/v1/trigger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("unauthenticated GET /v1/trigger = %d, want 401", resp.StatusCode)
+	}
+}
+
+func TestTCPListenerRequiresToken(t *testing.T) {
+	srv := NewServer(&fakeDaemon{}, Options{Listen: "127.0.0.1:0"}, quietLogger())
+	if err := srv.Start(); err == nil {
+		srv.Shutdown(context.Background())
+		t.Fatal("Start accepted a TCP listener without a token")
+	}
+}
+
+func TestNewClientAddress(t *testing.T) {
+	for _, addr := range []string{"/run/storage-sage/control.sock", "unix:///tmp/c.sock", "http://daemon:9092", "https://daemon:9092/"} {
+		if _, err := NewClient(addr, ""); err != nil {
+			t.Errorf("NewClient(%q): %v", addr, err)
+		}
+	}
+	if _, err := NewClient("daemon:9092", ""); err == nil {
+		t.Error("NewClient accepted an address without scheme")
+	}
+}
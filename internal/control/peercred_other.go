@@ -0,0 +1,13 @@
+//go:build !linux
+
+package control
+
+import (
+	"errors"
+	"net"
+)
+
+// peerCredentials is only implemented on Linux; other platforms must use the token-authenticated TCP listener
+func peerCredentials(c net.Conn) (uint32, uint32, error) {
+	return 0, 0, errors.New("peer credentials not supported on this platform")
+}
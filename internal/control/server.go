@@ -0,0 +1,328 @@
+package control
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Options selects the listeners and who may use them
+type Options struct {
+	Socket    string   // Unix socket path; peers are checked with SO_PEERCRED
+	AllowUIDs []uint32 // Socket peers allowed besides root and the daemon's own uid
+	AllowGIDs []uint32 // Socket peers allowed by primary group
+	Listen    string   // Optional TCP address; requests must carry Token
+	Token     string   // Bearer token for the TCP listener
+}
+
+// Server serves the control API
+type Server struct {
+	daemon Daemon
+	opts   Options
+	logger *log.Logger
+
+	mu      sync.Mutex
+	servers []*http.Server
+}
+
+type ctxKey int
+
+const peerKey ctxKey = 0
+
+// peer identifies the client of a connection
+type peer struct {
+	unix bool
+	uid  uint32
+	gid  uint32
+	err  error
+}
+
+// NewServer returns a server for d; call Start to begin listening
+func NewServer(d Daemon, opts Options, logger *log.Logger) *Server {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &Server{daemon: d, opts: opts, logger: logger}
+}
+
+// Start opens the configured listeners. Listeners that cannot be opened are
+// reported in the returned error; the others keep serving.
+func (s *Server) Start() error {
+	if s.opts.Listen != "" && s.opts.Token == "" {
+		return errors.New("control: TCP listener requires a token")
+	}
+
+	var errs []error
+	if s.opts.Socket != "" {
+		ln, err := listenUnix(s.opts.Socket)
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			s.serve(ln)
+		}
+	}
+	if s.opts.Listen != "" {
+		ln, err := net.Listen("tcp", s.opts.Listen)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("control: listen %s: %w", s.opts.Listen, err))
+		} else {
+			s.serve(ln)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// listenUnix replaces a stale socket left by a previous run and restricts
+// the new one to owner and group; SO_PEERCRED still decides who is admitted
+func listenUnix(path string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, fmt.Errorf("control: create socket directory: %w", err)
+	}
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("control: %s exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("control: remove stale socket: %w", err)
+		}
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("control: listen %s: %w", path, err)
+	}
+	if err := os.Chmod(path, 0660); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("control: chmod socket: %w", err)
+	}
+	return ln, nil
+}
+
+func (s *Server) serve(ln net.Listener) {
+	srv := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+		ConnContext: func(ctx context.Context, c net.Conn) context.Context {
+			if _, ok := c.(*net.UnixConn); !ok {
+				return context.WithValue(ctx, peerKey, peer{})
+			}
+			uid, gid, err := peerCredentials(c)
+			return context.WithValue(ctx, peerKey, peer{unix: true, uid: uid, gid: gid, err: err})
+		},
+	}
+
+	s.mu.Lock()
+	s.servers = append(s.servers, srv)
+	s.mu.Unlock()
+
+	go func() {
+		s.logger.Printf("control API listening on %s", ln.Addr())
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			s.logger.Printf("control API error on %s: %v", ln.Addr(), err)
+		}
+	}()
+}
+
+// Shutdown stops all listeners and removes the socket
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	servers := s.servers
+	s.servers = nil
+	s.mu.Unlock()
+
+	var errs []error
+	for _, srv := range servers {
+		if err := srv.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if s.opts.Socket != "" && len(servers) > 0 {
+		if err := os.Remove(s.opts.Socket); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Handler returns the authenticated control API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/status", s.handle(http.MethodGet, func(r *http.Request) (interface{}, error) {
+		return s.daemon.Status(), nil
+	}))
+	mux.HandleFunc("/v1/trigger", s.handle(http.MethodPost, func(r *http.Request) (interface{}, error) {
+		var req TriggerRequest
+		if err := decodeBody(r, &req); err != nil {
+			return nil, err
+		}
+		return message("cleanup triggered"), s.daemon.Trigger(req)
+	}))
+	mux.HandleFunc("/v1/reload", s.handle(http.MethodPost, func(r *http.Request) (interface{}, error) {
+		return message("config reload triggered"), s.daemon.Reload()
+	}))
+	mux.HandleFunc("/v1/pause", s.handle(http.MethodPost, func(r *http.Request) (interface{}, error) {
+		var req PauseRequest
+		if err := decodeBody(r, &req); err != nil {
+			return nil, err
+		}
+		if req.By == "" {
+			req.By = describePeer(r)
+		}
+		return message("cleanup paused"), s.daemon.Pause(req)
+	}))
+	mux.HandleFunc("/v1/resume", s.handle(http.MethodPost, func(r *http.Request) (interface{}, error) {
+		var req PauseRequest
+		if err := decodeBody(r, &req); err != nil {
+			return nil, err
+		}
+		return message("cleanup resumed"), s.daemon.Resume(req)
+	}))
+	mux.HandleFunc("/v1/override", s.handle(http.MethodPost, func(r *http.Request) (interface{}, error) {
+		var req OverrideRequest
+		if err := decodeBody(r, &req); err != nil {
+			return nil, err
+		}
+		if req.By == "" {
+			req.By = describePeer(r)
+		}
+		s.logger.Printf("control API: blast-radius override armed by %s (reason: %q)", req.By, req.Reason)
+		return message("blast-radius override armed for the next cycle"), s.daemon.Override(req)
+	}))
+	return s.authenticate(mux)
+}
+
+// errBadRequest marks malformed request bodies
+var errBadRequest = errors.New("bad request")
+
+// decodeBody reads an optional JSON body; an empty body leaves v unchanged
+func decodeBody(r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(io.LimitReader(r.Body, 1<<20))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil && err != io.EOF {
+		return fmt.Errorf("%w: %v", errBadRequest, err)
+	}
+	return nil
+}
+
+func message(m string) map[string]string {
+	return map[string]string{"message": m}
+}
+
+func (s *Server) handle(method string, fn func(r *http.Request) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		body, err := fn(r)
+		switch {
+		case errors.Is(err, errBadRequest):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, ErrUnknownRule):
+			writeError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, ErrPaused):
+			writeError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, ErrBusy):
+			writeError(w, http.StatusServiceUnavailable, err.Error())
+		case err != nil:
+			writeError(w, http.StatusInternalServerError, err.Error())
+		default:
+			writeJSON(w, http.StatusOK, body)
+		}
+	}
+}
+
+// authenticate admits Unix socket peers by uid/gid and TCP clients by bearer token
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, _ := r.Context().Value(peerKey).(peer)
+		var err error
+		if p.unix {
+			err = s.allowPeer(p)
+		} else {
+			err = s.checkToken(r.Header.Get("Authorization"))
+		}
+		if err != nil {
+			s.logger.Printf("control API: rejected %s %s from %s: %v", r.Method, r.URL.Path, r.RemoteAddr, err)
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// describePeer names the authenticated client for the pause and override audit trail
+func describePeer(r *http.Request) string {
+	p, _ := r.Context().Value(peerKey).(peer)
+	if p.unix {
+		return fmt.Sprintf("uid:%d", p.uid)
+	}
+	return "token:" + r.RemoteAddr
+}
+
+func (s *Server) allowPeer(p peer) error {
+	if p.err != nil {
+		return p.err
+	}
+	if p.uid == 0 || p.uid == uint32(os.Getuid()) {
+		return nil
+	}
+	for _, uid := range s.opts.AllowUIDs {
+		if p.uid == uid {
+			return nil
+		}
+	}
+	for _, gid := range s.opts.AllowGIDs {
+		if p.gid == gid {
+			return nil
+		}
+	}
+	return fmt.Errorf("peer uid %d gid %d not allowed", p.uid, p.gid)
+}
+
+func (s *Server) checkToken(header string) error {
+	if s.opts.Token == "" {
+		return errors.New("no token configured")
+	}
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		return errors.New("missing bearer token")
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.Token)) != 1 {
+		return errors.New("invalid bearer token")
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+// ReadToken reads a bearer token from path, ignoring surrounding whitespace
+func ReadToken(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read control token: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("control token file %s is empty", path)
+	}
+	return token, nil
+}
@@ -36,8 +36,14 @@ type DeletionRecord struct {
 	ActualDiskPercent       *float64
 	StackedThresholdPercent *float64
 	StackedAgeDays          *int
+	AgeSeconds              *int64 // Precise actual age (age_days rounds down to whole days)
+	AgeThresholdSeconds     *int64 // Precise age limit of the mode that selected the entry
 	PathRule                string
 	ErrorMessage            string
+	UID                     *int64 // Owning user at scan time (nil for older records)
+	GID                     *int64 // Owning group at scan time (nil for older records)
+	Errno                   string // Errno name of a failed delete, e.g. EACCES (ERROR rows only)
+	SizeAfter               *int64 // Size left on disk after the action (COMPRESS and TRUNCATE rows only; size minus this is the bytes saved or freed)
 	CreatedAt               time.Time
 }
 
@@ -114,10 +120,17 @@ func (d *DeletionDB) initSchema() error {
 		actual_disk_percent REAL,
 		stacked_threshold_percent REAL,
 		stacked_age_days INTEGER,
+		age_seconds INTEGER,
+		age_threshold_seconds INTEGER,
 
 		path_rule TEXT,
 		error_message TEXT,
 
+		uid INTEGER,
+		gid INTEGER,
+		errno TEXT,
+		size_after INTEGER,
+
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	);
 
@@ -136,21 +149,200 @@ func (d *DeletionDB) initSchema() error {
 	);
 
 	INSERT OR IGNORE INTO schema_version (version) VALUES (2);
+
+	-- Paused cleanup scopes: '*' for the whole daemon, otherwise a rule path
+	CREATE TABLE IF NOT EXISTS pause_state (
+		scope TEXT PRIMARY KEY,
+		reason TEXT,
+		paused_by TEXT,
+		paused_at DATETIME NOT NULL
+	);
+
+	-- Candidates waiting out the grace period; mod_time_ns detects changes between cycles
+	CREATE TABLE IF NOT EXISTS pending_deletions (
+		path TEXT PRIMARY KEY,
+		path_rule TEXT NOT NULL,
+		object_type TEXT NOT NULL,
+		size INTEGER NOT NULL,
+		mod_time_ns INTEGER NOT NULL,
+		reason TEXT,
+		first_seen DATETIME NOT NULL,
+		due_at DATETIME NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_pending_rule ON pending_deletions(path_rule);
+	CREATE INDEX IF NOT EXISTS idx_pending_due ON pending_deletions(due_at);
+
+	-- Files and directory subtrees users have exempted from deletion
+	CREATE TABLE IF NOT EXISTS reprieves (
+		path TEXT PRIMARY KEY,
+		reason TEXT,
+		reprieved_by TEXT,
+		reprieved_at DATETIME NOT NULL,
+		expires_at DATETIME
+	);
+
+	-- Legal holds: nothing matching pattern is deleted while a hold is active.
+	-- Holds are released, never deleted, so the table keeps their history.
+	CREATE TABLE IF NOT EXISTS legal_holds (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		pattern TEXT NOT NULL,
+		owner TEXT NOT NULL,
+		reason TEXT NOT NULL,
+		created_by TEXT,
+		created_at DATETIME NOT NULL,
+		expires_at DATETIME,
+		released_by TEXT,
+		released_at DATETIME
+	);
+
+	-- Audit trail of legal holds, append-only
+	CREATE TABLE IF NOT EXISTS legal_hold_events (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		hold_id INTEGER NOT NULL,
+		action TEXT NOT NULL,
+		actor TEXT,
+		at DATETIME NOT NULL,
+		detail TEXT
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_legal_hold_events_hold ON legal_hold_events(hold_id);
+
+	-- Paths whose deletes failed permanently; retries are suppressed until "until"
+	CREATE TABLE IF NOT EXISTS quarantine (
+		path TEXT PRIMARY KEY,
+		errno TEXT NOT NULL,
+		error_message TEXT,
+		failures INTEGER NOT NULL,
+		first_failed DATETIME NOT NULL,
+		last_failed DATETIME NOT NULL,
+		until DATETIME NOT NULL
+	);
+
+	-- One row per cleanup cycle
+	CREATE TABLE IF NOT EXISTS cycle_runs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		started_at DATETIME NOT NULL,
+		finished_at DATETIME NOT NULL,
+		dry_run INTEGER NOT NULL,
+		candidates INTEGER NOT NULL,
+		deleted INTEGER NOT NULL,
+		bytes_freed INTEGER NOT NULL,
+		scan_errors INTEGER NOT NULL,
+		skipped_bytes INTEGER NOT NULL,
+		message TEXT,
+		error TEXT
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_cycle_runs_started ON cycle_runs(started_at);
+
+	-- Entries a cycle's scan could not read (capped per rule; cycle_runs.scan_errors has the full count)
+	CREATE TABLE IF NOT EXISTS scan_errors (
+		run_id INTEGER NOT NULL,
+		path_rule TEXT NOT NULL,
+		path TEXT NOT NULL,
+		kind TEXT NOT NULL,
+		error_message TEXT,
+		is_dir INTEGER NOT NULL,
+		estimated_bytes INTEGER NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_scan_errors_run ON scan_errors(run_id);
 	`
 
-	_, err := d.db.Exec(schema)
+	if _, err := d.db.Exec(schema); err != nil {
+		return err
+	}
+
+	// Columns added after the initial release; older databases need them backfilled
+	if err := d.ensureColumn("deletions", "uid", "INTEGER"); err != nil {
+		return err
+	}
+	if err := d.ensureColumn("deletions", "gid", "INTEGER"); err != nil {
+		return err
+	}
+	if err := d.ensureColumn("deletions", "errno", "TEXT"); err != nil {
+		return err
+	}
+	if err := d.ensureColumn("deletions", "age_seconds", "INTEGER"); err != nil {
+		return err
+	}
+	if err := d.ensureColumn("deletions", "age_threshold_seconds", "INTEGER"); err != nil {
+		return err
+	}
+	if err := d.ensureColumn("deletions", "size_after", "INTEGER"); err != nil {
+		return err
+	}
+
+	_, err := d.db.Exec(`
+	CREATE INDEX IF NOT EXISTS idx_uid ON deletions(uid);
+	CREATE INDEX IF NOT EXISTS idx_gid ON deletions(gid);
+	`)
 	return err
 }
 
+// ensureColumn adds a column to an existing table if it is missing
+func (d *DeletionDB) ensureColumn(table, column, definition string) error {
+	rows, err := d.db.Query("PRAGMA table_info(" + table + ")")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var dflt sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dflt, &pk); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	_ = rows.Close()
+
+	_, err = d.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	if err != nil {
+		return fmt.Errorf("failed to add column %s.%s: %w", table, column, err)
+	}
+	return nil
+}
+
 // RecordDeletion inserts a deletion event into the database
 func (d *DeletionDB) RecordDeletion(
 	action string,
 	candidate scan.Candidate,
 	errorMsg string,
 ) error {
+	return d.recordDeletion(action, candidate, errorMsg, "", nil)
+}
+
+// RecordFailure records a failed delete as an ERROR row with the errno that caused it
+func (d *DeletionDB) RecordFailure(candidate scan.Candidate, errno, errorMsg string) error {
+	return d.recordDeletion("ERROR", candidate, errorMsg, errno, nil)
+}
+
+// RecordCompression records a compressed file as a COMPRESS row: size is the
+// original size and sizeAfter the size of the compressed file replacing it
+func (d *DeletionDB) RecordCompression(candidate scan.Candidate, sizeAfter int64) error {
+	return d.recordDeletion("COMPRESS", candidate, "", "", &sizeAfter)
+}
+
+// RecordTruncation records a file truncated in place as a TRUNCATE row: size
+// is the size before truncation and sizeAfter the tail that was kept
+func (d *DeletionDB) RecordTruncation(candidate scan.Candidate, sizeAfter int64) error {
+	return d.recordDeletion("TRUNCATE", candidate, "", "", &sizeAfter)
+}
+
+func (d *DeletionDB) recordDeletion(action string, candidate scan.Candidate, errorMsg, errno string, sizeAfter *int64) error {
 	reason := candidate.DeletionReason
 
 	var ageThresholdDays, actualAgeDays, stackedAgeDays, ageDays *int
+	var ageSeconds, ageThresholdSeconds *int64
 	var diskThresholdPercent, actualDiskPercent, stackedThresholdPercent *float64
 	var priority *int
 
@@ -159,6 +351,7 @@ func (d *DeletionDB) RecordDeletion(
 		ageThresholdDays = &reason.AgeThreshold.ConfiguredDays
 		actualAgeDays = &reason.AgeThreshold.ActualAgeDays
 		ageDays = &reason.AgeThreshold.ActualAgeDays
+		ageSeconds, ageThresholdSeconds = durationSeconds(reason.AgeThreshold.Age, reason.AgeThreshold.MaxAge)
 	}
 
 	if reason.DiskThreshold != nil {
@@ -170,10 +363,46 @@ func (d *DeletionDB) RecordDeletion(
 		stackedThresholdPercent = &reason.StackedCleanup.StackThreshold
 		stackedAgeDays = &reason.StackedCleanup.StackAgeDays
 		ageDays = &reason.StackedCleanup.ActualAgeDays
+		ageSeconds, ageThresholdSeconds = durationSeconds(reason.StackedCleanup.Age, reason.StackedCleanup.StackAge)
+	}
+
+	if reason.Tier != nil {
+		diskThresholdPercent = &reason.Tier.UsagePercent
+		actualDiskPercent = &reason.Tier.ActualPercent
+		ageDays = &reason.Tier.ActualAgeDays
+		ageSeconds, ageThresholdSeconds = durationSeconds(reason.Tier.Age, reason.Tier.MinAge)
+	}
+
+	if reason.Compress != nil {
+		diskThresholdPercent = &reason.Compress.UsagePercent
+		actualDiskPercent = &reason.Compress.ActualPercent
+		ageDays = &reason.Compress.ActualAgeDays
+		ageSeconds, ageThresholdSeconds = durationSeconds(reason.Compress.Age, reason.Compress.MinAge)
+	}
+
+	if t := reason.Truncate; t != nil {
+		actualDiskPercent = &t.ActualPercent
+		switch {
+		case t.Level > 0:
+			diskThresholdPercent = &t.UsagePercent
+			ageDays = &t.ActualAgeDays
+			ageSeconds, ageThresholdSeconds = durationSeconds(t.Age, t.MinAge)
+		case t.Stack:
+			stackedThresholdPercent = &t.UsagePercent
+		}
 	}
 
 	// Determine cleanup mode based on primary reason
 	mode := determineMode(reason.GetPrimaryReason())
+	if reason.Tier != nil {
+		mode = scan.TierMode(reason.Tier.Level)
+	}
+
+	var uid, gid *int64
+	if candidate.HasOwner {
+		u, g := int64(candidate.UID), int64(candidate.GID)
+		uid, gid = &u, &g
+	}
 
 	query := `
 	INSERT INTO deletions (
@@ -182,8 +411,9 @@ func (d *DeletionDB) RecordDeletion(
 		age_threshold_days, actual_age_days,
 		disk_threshold_percent, actual_disk_percent,
 		stacked_threshold_percent, stacked_age_days,
-		path_rule, error_message
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		age_seconds, age_threshold_seconds,
+		path_rule, error_message, uid, gid, errno, size_after
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	_, err := d.db.Exec(
@@ -205,13 +435,29 @@ func (d *DeletionDB) RecordDeletion(
 		actualDiskPercent,
 		stackedThresholdPercent,
 		stackedAgeDays,
+		ageSeconds,
+		ageThresholdSeconds,
 		reason.PathRule,
 		errorMsg,
+		uid,
+		gid,
+		sql.NullString{String: errno, Valid: errno != ""},
+		sizeAfter,
 	)
 
 	return err
 }
 
+// durationSeconds returns an age and its limit in whole seconds for the
+// deletions table, or nils when the reason predates precise ages
+func durationSeconds(age, limit time.Duration) (*int64, *int64) {
+	if age == 0 && limit == 0 {
+		return nil, nil
+	}
+	a, l := int64(age/time.Second), int64(limit/time.Second)
+	return &a, &l
+}
+
 // determineMode maps primary reason to cleanup mode
 func determineMode(primaryReason string) string {
 	switch primaryReason {
@@ -221,6 +467,14 @@ func determineMode(primaryReason string) string {
 		return "DISK"
 	case "age_threshold":
 		return "AGE"
+	case "gfs":
+		return "GFS"
+	case "keep_latest":
+		return "KEEP_LATEST"
+	case "compress":
+		return "COMPRESS"
+	case "truncate":
+		return "TRUNCATE"
 	default:
 		return "UNKNOWN"
 	}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -1079,3 +1080,362 @@ func TestNullFieldHandling(t *testing.T) {
 		t.Errorf("Path mismatch: expected /test/minimal.log, got %s", record.Path)
 	}
 }
+
+// TestBytesFreedByOwner verifies ownership is stored and aggregated per uid and gid
+func TestBytesFreedByOwner(t *testing.T) {
+	db, err := NewDeletionDB(filepath.Join(t.TempDir(), "test_owner.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	now := time.Now()
+	records := []struct {
+		action string
+		cand   scan.Candidate
+	}{
+		{"DELETE", scan.Candidate{Path: "/scratch/a", Size: 100, UID: 1001, GID: 2000, HasOwner: true}},
+		{"DELETE", scan.Candidate{Path: "/scratch/b", Size: 300, UID: 1001, GID: 2000, HasOwner: true}},
+		{"DELETE", scan.Candidate{Path: "/scratch/c", Size: 1000, UID: 1002, GID: 2000, HasOwner: true}},
+		{"SKIP", scan.Candidate{Path: "/scratch/d", Size: 5000, UID: 1003, GID: 3000, HasOwner: true}},
+		{"DELETE", scan.Candidate{Path: "/scratch/e", Size: 50}}, // ownership unknown
+	}
+	for _, r := range records {
+		r.cand.DeletionReason.EvaluatedAt = now
+		if err := db.RecordDeletion(r.action, r.cand, ""); err != nil {
+			t.Fatalf("Failed to record %s: %v", r.cand.Path, err)
+		}
+	}
+
+	start, end := now.Add(-time.Hour), now.Add(time.Hour)
+
+	byOwner, err := db.GetBytesFreedByOwner(start, end)
+	if err != nil {
+		t.Fatalf("GetBytesFreedByOwner failed: %v", err)
+	}
+	wantOwner := []OwnerUsage{{ID: 1002, Files: 1, Bytes: 1000}, {ID: 1001, Files: 2, Bytes: 400}}
+	if fmt.Sprint(byOwner) != fmt.Sprint(wantOwner) {
+		t.Errorf("by owner: got %v, want %v", byOwner, wantOwner)
+	}
+
+	byGroup, err := db.GetBytesFreedByGroup(start, end)
+	if err != nil {
+		t.Fatalf("GetBytesFreedByGroup failed: %v", err)
+	}
+	wantGroup := []OwnerUsage{{ID: 2000, Files: 3, Bytes: 1400}}
+	if fmt.Sprint(byGroup) != fmt.Sprint(wantGroup) {
+		t.Errorf("by group: got %v, want %v", byGroup, wantGroup)
+	}
+
+	recent, err := db.GetRecentDeletions(10)
+	if err != nil {
+		t.Fatalf("GetRecentDeletions failed: %v", err)
+	}
+	for _, r := range recent {
+		if r.Path == "/scratch/e" && r.UID != nil {
+			t.Errorf("expected nil uid for record without ownership, got %d", *r.UID)
+		}
+		if r.Path == "/scratch/a" && (r.UID == nil || *r.UID != 1001) {
+			t.Errorf("expected uid 1001 for /scratch/a, got %v", r.UID)
+		}
+	}
+}
+
+// TestOwnerColumnsMigration verifies uid/gid are added to databases created before they existed
+func TestOwnerColumnsMigration(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test_migrate.db")
+
+	db, err := NewDeletionDB(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	// Simulate a database from before ownership tracking
+	if _, err := db.db.Exec("DROP INDEX idx_uid; DROP INDEX idx_gid; ALTER TABLE deletions DROP COLUMN uid; ALTER TABLE deletions DROP COLUMN gid"); err != nil {
+		t.Fatalf("Failed to drop ownership columns: %v", err)
+	}
+	_ = db.Close()
+
+	db, err = NewDeletionDB(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to reopen database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	cand := scan.Candidate{Path: "/scratch/x", Size: 1, UID: 7, GID: 8, HasOwner: true}
+	cand.DeletionReason.EvaluatedAt = time.Now()
+	if err := db.RecordDeletion("DELETE", cand, ""); err != nil {
+		t.Fatalf("RecordDeletion after migration failed: %v", err)
+	}
+}
+
+// TestPauseState verifies pauses persist across reopen and re-pausing keeps the original time
+func TestPauseState(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test_pause.db")
+	db, err := NewDeletionDB(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+
+	if err := db.SetPaused(PauseScopeGlobal, "maintenance", "uid:0"); err != nil {
+		t.Fatalf("SetPaused global failed: %v", err)
+	}
+	if err := db.SetPaused("/data/volume1", "incident 42", "alice"); err != nil {
+		t.Fatalf("SetPaused rule failed: %v", err)
+	}
+	first, err := db.GetPaused()
+	if err != nil {
+		t.Fatalf("GetPaused failed: %v", err)
+	}
+	if err := db.SetPaused("/data/volume1", "incident 42 (still open)", "bob"); err != nil {
+		t.Fatalf("SetPaused again failed: %v", err)
+	}
+	_ = db.Close()
+
+	db, err = NewDeletionDB(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to reopen database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	entries, err := db.GetPaused()
+	if err != nil {
+		t.Fatalf("GetPaused failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Scope != PauseScopeGlobal || entries[1].Scope != "/data/volume1" {
+		t.Fatalf("unexpected pause entries: %+v", entries)
+	}
+	if entries[1].Reason != "incident 42 (still open)" || entries[1].PausedBy != "bob" {
+		t.Errorf("re-pause did not update reason/by: %+v", entries[1])
+	}
+	if !entries[1].PausedAt.Equal(first[1].PausedAt) {
+		t.Errorf("re-pause changed paused_at from %v to %v", first[1].PausedAt, entries[1].PausedAt)
+	}
+
+	if err := db.ClearPaused(PauseScopeGlobal); err != nil {
+		t.Fatalf("ClearPaused failed: %v", err)
+	}
+	entries, err = db.GetPaused()
+	if err != nil {
+		t.Fatalf("GetPaused failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Scope != "/data/volume1" {
+		t.Errorf("expected only the rule pause to remain, got %+v", entries)
+	}
+}
+
+func TestPendingDeletionsAndReprieves(t *testing.T) {
+	db, err := NewDeletionDB(filepath.Join(t.TempDir(), "test_pending.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	now := time.Now()
+	mod := time.Unix(1700000000, 123456789)
+	entry := func(path, rule string) PendingDeletion {
+		cand := scan.Candidate{Path: path, Size: 10, ModTime: mod, DeletionReason: scan.DeletionReason{PathRule: rule}}
+		return NewPendingDeletion(cand, now, now.Add(24*time.Hour))
+	}
+
+	if err := db.ReplacePending([]string{"/scratch", "/logs"}, []PendingDeletion{
+		entry("/scratch/a/out.csv", "/scratch"),
+		entry("/scratch/b/out.csv", "/scratch"),
+		entry("/logs/app.log", "/logs"),
+	}); err != nil {
+		t.Fatalf("ReplacePending failed: %v", err)
+	}
+
+	// Replacing one rule leaves the other rule's entries alone
+	if err := db.ReplacePending([]string{"/logs"}, nil); err != nil {
+		t.Fatalf("ReplacePending failed: %v", err)
+	}
+	pending, err := db.GetPendingByRule([]string{"/scratch", "/logs"})
+	if err != nil {
+		t.Fatalf("GetPendingByRule failed: %v", err)
+	}
+	if len(pending) != 2 {
+		t.Fatalf("expected 2 pending deletions, got %+v", pending)
+	}
+	if p := pending["/scratch/a/out.csv"]; !p.ModTime.Equal(mod) || p.Size != 10 || p.ObjectType != "file" {
+		t.Errorf("pending entry did not round-trip: %+v", p)
+	}
+
+	// A reprieve on a directory drops pending deletions below it
+	expires := now.Add(time.Hour)
+	if err := db.AddReprieve(Reprieve{Path: "/scratch/a/", Reason: "thesis data", ReprievedBy: "alice", ExpiresAt: &expires}); err != nil {
+		t.Fatalf("AddReprieve failed: %v", err)
+	}
+	if err := db.AddReprieve(Reprieve{Path: "relative/path"}); err == nil {
+		t.Error("AddReprieve accepted a relative path")
+	}
+	list, err := db.GetPendingDeletions(0)
+	if err != nil {
+		t.Fatalf("GetPendingDeletions failed: %v", err)
+	}
+	if len(list) != 1 || list[0].Path != "/scratch/b/out.csv" {
+		t.Errorf("expected only /scratch/b/out.csv pending, got %+v", list)
+	}
+
+	reprieves, err := db.GetReprieves(now)
+	if err != nil {
+		t.Fatalf("GetReprieves failed: %v", err)
+	}
+	if len(reprieves) != 1 || reprieves[0].Path != "/scratch/a" || reprieves[0].ReprievedBy != "alice" {
+		t.Fatalf("unexpected reprieves: %+v", reprieves)
+	}
+	if expired, _ := db.GetReprieves(now.Add(2 * time.Hour)); len(expired) != 0 {
+		t.Errorf("expired reprieve still returned: %+v", expired)
+	}
+
+	if removed, err := db.RemoveReprieve("/scratch/a"); err != nil || !removed {
+		t.Errorf("RemoveReprieve = %t, %v; want true", removed, err)
+	}
+	if removed, _ := db.RemoveReprieve("/scratch/a"); removed {
+		t.Error("RemoveReprieve reported removing a missing reprieve")
+	}
+
+	if err := db.ClearPending([]string{"/scratch/b/out.csv"}); err != nil {
+		t.Fatalf("ClearPending failed: %v", err)
+	}
+	if list, _ := db.GetPendingDeletions(0); len(list) != 0 {
+		t.Errorf("expected no pending deletions, got %+v", list)
+	}
+}
+
+func TestCycleRunsAndScanErrors(t *testing.T) {
+	db, err := NewDeletionDB(filepath.Join(t.TempDir(), "test_runs.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	now := time.Now()
+	if _, err := db.RecordRun(CycleRun{StartedAt: now.Add(-time.Hour), FinishedAt: now.Add(-time.Hour), Deleted: 3}, nil); err != nil {
+		t.Fatalf("RecordRun failed: %v", err)
+	}
+	errs := []scan.ScanError{
+		{PathRule: "/data", Path: "/data/locked", Kind: scan.ScanErrPermission, Error: "permission denied", IsDir: true, EstimatedBytes: 4096},
+		{PathRule: "/data", Path: "/data/bad.dat", Kind: scan.ScanErrIO, Error: "input/output error"},
+	}
+	id, err := db.RecordRun(CycleRun{
+		StartedAt:    now,
+		FinishedAt:   now.Add(time.Minute),
+		DryRun:       true,
+		Candidates:   5,
+		ScanErrors:   2,
+		SkippedBytes: 4096,
+		Message:      "target not reached on /data",
+	}, errs)
+	if err != nil {
+		t.Fatalf("RecordRun failed: %v", err)
+	}
+
+	runs, err := db.GetRecentRuns(10)
+	if err != nil {
+		t.Fatalf("GetRecentRuns failed: %v", err)
+	}
+	if len(runs) != 2 || runs[0].ID != id {
+		t.Fatalf("expected the latest run first, got %+v", runs)
+	}
+	if !runs[0].DryRun || runs[0].ScanErrors != 2 || runs[0].SkippedBytes != 4096 || runs[0].Message == "" {
+		t.Errorf("run not stored as recorded: %+v", runs[0])
+	}
+
+	got, err := db.GetScanErrors(id)
+	if err != nil {
+		t.Fatalf("GetScanErrors failed: %v", err)
+	}
+	if len(got) != 2 || got[0].Path != "/data/bad.dat" || got[1] != errs[0] {
+		t.Errorf("scan errors = %+v, want %+v", got, errs)
+	}
+	if got, _ := db.GetScanErrors(runs[1].ID); len(got) != 0 {
+		t.Errorf("expected no scan errors for the first run, got %+v", got)
+	}
+}
+
+func TestLegalHolds(t *testing.T) {
+	db, err := NewDeletionDB(filepath.Join(t.TempDir(), "test_holds.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	for _, bad := range []LegalHold{
+		{Pattern: "relative", Owner: "legal", Reason: "case"},
+		{Pattern: "/data/case", Reason: "case"},
+		{Pattern: "/data/case", Owner: "legal", Reason: "case", ExpiresAt: &now, CreatedAt: now.Add(time.Hour)},
+	} {
+		if _, err := db.PlaceHold(bad); !errors.Is(err, ErrInvalidHold) {
+			t.Errorf("PlaceHold(%+v) = %v, want ErrInvalidHold", bad, err)
+		}
+	}
+
+	expires := now.Add(time.Hour)
+	keep, err := db.PlaceHold(LegalHold{Pattern: "/data/case/", Owner: "legal", Reason: "case 114", CreatedBy: "alice"})
+	if err != nil {
+		t.Fatalf("PlaceHold: %v", err)
+	}
+	brief, err := db.PlaceHold(LegalHold{Pattern: "/data/*.pst", Owner: "legal", Reason: "audit", CreatedBy: "alice", ExpiresAt: &expires})
+	if err != nil {
+		t.Fatalf("PlaceHold: %v", err)
+	}
+
+	if released, err := db.ReleaseHold(keep, "bob", "case closed", now); err != nil || !released {
+		t.Fatalf("ReleaseHold = %v, %v", released, err)
+	}
+	if released, _ := db.ReleaseHold(keep, "bob", "again", now); released {
+		t.Error("released hold was released twice")
+	}
+
+	active, err := db.GetHolds(now, false)
+	if err != nil || len(active) != 1 || active[0].ID != brief {
+		t.Fatalf("active holds = %+v, %v; want only #%d", active, err, brief)
+	}
+	if later, _ := db.GetHolds(now.Add(2*time.Hour), false); len(later) != 0 {
+		t.Errorf("expired hold still active: %+v", later)
+	}
+	all, _ := db.GetHolds(now, true)
+	if len(all) != 2 || all[0].Pattern != "/data/case" || all[0].ReleasedBy != "bob" {
+		t.Errorf("all holds = %+v, want the released hold with its cleaned path", all)
+	}
+
+	events, err := db.GetHoldEvents(keep)
+	if err != nil {
+		t.Fatalf("GetHoldEvents: %v", err)
+	}
+	if len(events) != 2 || events[0].Action != HoldPlaced || events[0].Actor != "alice" ||
+		events[1].Action != HoldReleased || events[1].Actor != "bob" || events[1].Detail != "case closed" {
+		t.Errorf("audit trail = %+v, want placed by alice then released by bob", events)
+	}
+	if every, _ := db.GetHoldEvents(0); len(every) != 3 {
+		t.Errorf("full audit trail has %d events, want 3", len(every))
+	}
+}
+
+// TestPreciseAgeColumns verifies sub-day ages are stored in seconds next to the whole-day columns
+func TestPreciseAgeColumns(t *testing.T) {
+	db, err := NewDeletionDB(filepath.Join(t.TempDir(), "test_age.db"))
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	cand := scan.Candidate{Path: "/tmp/uploads/part-1", Size: 1}
+	cand.DeletionReason = scan.DeletionReason{
+		PathRule:     "/tmp/uploads",
+		EvaluatedAt:  time.Now(),
+		AgeThreshold: &scan.AgeReason{MaxAge: 6 * time.Hour, Age: 7*time.Hour + 30*time.Second},
+	}
+	if err := db.RecordDeletion("DELETE", cand, ""); err != nil {
+		t.Fatalf("RecordDeletion failed: %v", err)
+	}
+
+	var ageDays, ageSeconds, thresholdSeconds int64
+	if err := db.db.QueryRow("SELECT age_days, age_seconds, age_threshold_seconds FROM deletions").Scan(&ageDays, &ageSeconds, &thresholdSeconds); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if ageDays != 0 || ageSeconds != 7*3600+30 || thresholdSeconds != 6*3600 {
+		t.Errorf("age_days=%d age_seconds=%d age_threshold_seconds=%d, want 0, 25230, 21600", ageDays, ageSeconds, thresholdSeconds)
+	}
+}
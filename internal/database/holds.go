@@ -0,0 +1,195 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"storage-sage/internal/safety"
+)
+
+// Legal hold audit actions
+const (
+	HoldPlaced   = "placed"
+	HoldReleased = "released"
+)
+
+// ErrInvalidHold is wrapped by every error PlaceHold returns for a hold it refuses to record
+var ErrInvalidHold = errors.New("invalid legal hold")
+
+// LegalHold preserves every file matching Pattern until it is released or expires
+type LegalHold struct {
+	ID         int64      `json:"id"`
+	Pattern    string     `json:"pattern"` // Absolute path (file or subtree) or absolute glob
+	Owner      string     `json:"owner"`   // Who answers for the hold, e.g. the requesting counsel
+	Reason     string     `json:"reason"`
+	CreatedBy  string     `json:"created_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"` // nil keeps the hold until it is released
+	ReleasedBy string     `json:"released_by,omitempty"`
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
+}
+
+// Active reports whether the hold is in force at now
+func (h LegalHold) Active(now time.Time) bool {
+	return h.ReleasedAt == nil && (h.ExpiresAt == nil || now.Before(*h.ExpiresAt))
+}
+
+// HoldEvent is one entry of the legal hold audit trail
+type HoldEvent struct {
+	HoldID int64     `json:"hold_id"`
+	Action string    `json:"action"` // HoldPlaced or HoldReleased
+	Actor  string    `json:"actor,omitempty"`
+	At     time.Time `json:"at"`
+	Detail string    `json:"detail,omitempty"`
+}
+
+// PlaceHold records h and its audit entry and returns its ID
+func (d *DeletionDB) PlaceHold(h LegalHold) (int64, error) {
+	if err := safety.ValidateHoldPattern(h.Pattern); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidHold, err)
+	}
+	if !safety.IsHoldGlob(h.Pattern) {
+		h.Pattern = filepath.Clean(h.Pattern)
+	}
+	if h.Owner == "" || h.Reason == "" {
+		return 0, fmt.Errorf("%w: an owner and a reason are required", ErrInvalidHold)
+	}
+	if h.CreatedAt.IsZero() {
+		h.CreatedAt = time.Now()
+	}
+	var expires sql.NullTime
+	if h.ExpiresAt != nil {
+		if !h.ExpiresAt.After(h.CreatedAt) {
+			return 0, fmt.Errorf("%w: expiry must be in the future", ErrInvalidHold)
+		}
+		expires = sql.NullTime{Time: *h.ExpiresAt, Valid: true}
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec(`
+		INSERT INTO legal_holds (pattern, owner, reason, created_by, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		h.Pattern, h.Owner, h.Reason, h.CreatedBy, h.CreatedAt, expires)
+	if err != nil {
+		return 0, fmt.Errorf("failed to place legal hold on %s: %w", h.Pattern, err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	detail := fmt.Sprintf("pattern=%s owner=%s reason=%q", h.Pattern, h.Owner, h.Reason)
+	if h.ExpiresAt != nil {
+		detail += " expires=" + h.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	if err := recordHoldEvent(tx, id, HoldPlaced, h.CreatedBy, h.CreatedAt, detail); err != nil {
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+// ReleaseHold lifts hold id on behalf of actor; it reports false if there is
+// no such hold or it was already released
+func (d *DeletionDB) ReleaseHold(id int64, actor, note string, now time.Time) (bool, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec(`UPDATE legal_holds SET released_by = ?, released_at = ? WHERE id = ? AND released_at IS NULL`,
+		actor, now, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to release legal hold #%d: %w", id, err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+	if err := recordHoldEvent(tx, id, HoldReleased, actor, now, note); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// GetHolds returns the holds in force at now, or every hold ever placed when
+// all is set, ordered by ID
+func (d *DeletionDB) GetHolds(now time.Time, all bool) ([]LegalHold, error) {
+	rows, err := d.db.Query(`SELECT id, pattern, owner, reason, COALESCE(created_by, ''), created_at,
+		expires_at, COALESCE(released_by, ''), released_at FROM legal_holds ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query legal holds: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var holds []LegalHold
+	for rows.Next() {
+		var h LegalHold
+		var expires, released sql.NullTime
+		if err := rows.Scan(&h.ID, &h.Pattern, &h.Owner, &h.Reason, &h.CreatedBy, &h.CreatedAt,
+			&expires, &h.ReleasedBy, &released); err != nil {
+			return nil, err
+		}
+		if expires.Valid {
+			t := expires.Time
+			h.ExpiresAt = &t
+		}
+		if released.Valid {
+			t := released.Time
+			h.ReleasedAt = &t
+		}
+		if all || h.Active(now) {
+			holds = append(holds, h)
+		}
+	}
+	return holds, rows.Err()
+}
+
+// HoldsGeneration returns a number that grows whenever a hold is placed or
+// released, so a reader can tell cheaply whether its copy of the holds is stale
+func (d *DeletionDB) HoldsGeneration() (int64, error) {
+	var gen int64
+	if err := d.db.QueryRow(`SELECT COALESCE(MAX(rowid), 0) FROM legal_hold_events`).Scan(&gen); err != nil {
+		return 0, fmt.Errorf("failed to query legal hold generation: %w", err)
+	}
+	return gen, nil
+}
+
+// GetHoldEvents returns the audit trail of hold id, or of every hold when id is 0, oldest first
+func (d *DeletionDB) GetHoldEvents(id int64) ([]HoldEvent, error) {
+	query := `SELECT hold_id, action, COALESCE(actor, ''), at, COALESCE(detail, '') FROM legal_hold_events`
+	var args []interface{}
+	if id != 0 {
+		query += ` WHERE hold_id = ?`
+		args = append(args, id)
+	}
+	rows, err := d.db.Query(query+` ORDER BY id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query legal hold events: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var events []HoldEvent
+	for rows.Next() {
+		var e HoldEvent
+		if err := rows.Scan(&e.HoldID, &e.Action, &e.Actor, &e.At, &e.Detail); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+func recordHoldEvent(tx *sql.Tx, id int64, action, actor string, at time.Time, detail string) error {
+	if _, err := tx.Exec(`INSERT INTO legal_hold_events (hold_id, action, actor, at, detail) VALUES (?, ?, ?, ?, ?)`,
+		id, action, actor, at, detail); err != nil {
+		return fmt.Errorf("failed to audit legal hold #%d: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+// PauseScopeGlobal is the scope that pauses every rule
+const PauseScopeGlobal = "*"
+
+// PauseEntry is a persisted pause for the whole daemon or one rule
+type PauseEntry struct {
+	Scope    string    `json:"scope"` // PauseScopeGlobal or a rule path
+	Reason   string    `json:"reason,omitempty"`
+	PausedBy string    `json:"paused_by,omitempty"`
+	PausedAt time.Time `json:"paused_at"`
+}
+
+// SetPaused records a pause for scope; pausing an already paused scope keeps the original time
+func (d *DeletionDB) SetPaused(scope, reason, pausedBy string) error {
+	_, err := d.db.Exec(`
+		INSERT INTO pause_state (scope, reason, paused_by, paused_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(scope) DO UPDATE SET reason = excluded.reason, paused_by = excluded.paused_by`,
+		scope, reason, pausedBy, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to pause %s: %w", scope, err)
+	}
+	return nil
+}
+
+// ClearPaused removes the pause for scope
+func (d *DeletionDB) ClearPaused(scope string) error {
+	if _, err := d.db.Exec(`DELETE FROM pause_state WHERE scope = ?`, scope); err != nil {
+		return fmt.Errorf("failed to resume %s: %w", scope, err)
+	}
+	return nil
+}
+
+// GetPaused returns all persisted pauses ordered by scope
+func (d *DeletionDB) GetPaused() ([]PauseEntry, error) {
+	rows, err := d.db.Query(`SELECT scope, COALESCE(reason, ''), COALESCE(paused_by, ''), paused_at FROM pause_state ORDER BY scope`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pause state: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var entries []PauseEntry
+	for rows.Next() {
+		var e PauseEntry
+		if err := rows.Scan(&e.Scope, &e.Reason, &e.PausedBy, &e.PausedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,215 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"storage-sage/internal/scan"
+)
+
+// PendingDeletion is a candidate marked for deletion that is waiting out the grace period
+type PendingDeletion struct {
+	Path       string    `json:"path"`
+	PathRule   string    `json:"path_rule"`
+	ObjectType string    `json:"object_type"`
+	Size       int64     `json:"size"`
+	ModTime    time.Time `json:"mod_time"`
+	Reason     string    `json:"reason,omitempty"`
+	FirstSeen  time.Time `json:"first_seen"` // When the candidate was first selected unchanged
+	DueAt      time.Time `json:"due_at"`     // When it may be removed if it still qualifies
+}
+
+// NewPendingDeletion describes cand as pending from firstSeen until dueAt
+func NewPendingDeletion(cand scan.Candidate, firstSeen, dueAt time.Time) PendingDeletion {
+	return PendingDeletion{
+		Path:       cand.Path,
+		PathRule:   cand.DeletionReason.PathRule,
+		ObjectType: objectType(cand),
+		Size:       cand.Size,
+		ModTime:    cand.ModTime,
+		Reason:     cand.DeletionReason.ToLogString(),
+		FirstSeen:  firstSeen,
+		DueAt:      dueAt,
+	}
+}
+
+// Reprieve exempts a file or a directory subtree from deletion
+type Reprieve struct {
+	Path        string     `json:"path"`
+	Reason      string     `json:"reason,omitempty"`
+	ReprievedBy string     `json:"reprieved_by,omitempty"`
+	ReprievedAt time.Time  `json:"reprieved_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"` // nil keeps the reprieve until it is removed
+}
+
+// Active reports whether the reprieve is in force at now
+func (r Reprieve) Active(now time.Time) bool {
+	return r.ExpiresAt == nil || now.Before(*r.ExpiresAt)
+}
+
+// GetPendingByRule returns the pending deletions under the given rules, keyed by path
+func (d *DeletionDB) GetPendingByRule(rules []string) (map[string]PendingDeletion, error) {
+	pending := make(map[string]PendingDeletion)
+	if len(rules) == 0 {
+		return pending, nil
+	}
+	query := `SELECT path, path_rule, object_type, size, mod_time_ns, COALESCE(reason, ''), first_seen, due_at
+		FROM pending_deletions WHERE path_rule IN (?` + strings.Repeat(", ?", len(rules)-1) + `)`
+	args := make([]interface{}, len(rules))
+	for i, r := range rules {
+		args[i] = r
+	}
+	entries, err := d.queryPending(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range entries {
+		pending[p.Path] = p
+	}
+	return pending, nil
+}
+
+// GetPendingDeletions returns pending deletions ordered by due time; limit <= 0 returns all
+func (d *DeletionDB) GetPendingDeletions(limit int) ([]PendingDeletion, error) {
+	query := `SELECT path, path_rule, object_type, size, mod_time_ns, COALESCE(reason, ''), first_seen, due_at
+		FROM pending_deletions ORDER BY due_at, path`
+	if limit > 0 {
+		return d.queryPending(query+` LIMIT ?`, limit)
+	}
+	return d.queryPending(query)
+}
+
+func (d *DeletionDB) queryPending(query string, args ...interface{}) ([]PendingDeletion, error) {
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending deletions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var entries []PendingDeletion
+	for rows.Next() {
+		var p PendingDeletion
+		var modNs int64
+		if err := rows.Scan(&p.Path, &p.PathRule, &p.ObjectType, &p.Size, &modNs, &p.Reason, &p.FirstSeen, &p.DueAt); err != nil {
+			return nil, err
+		}
+		p.ModTime = time.Unix(0, modNs)
+		entries = append(entries, p)
+	}
+	return entries, rows.Err()
+}
+
+// ReplacePending replaces the pending deletions under rules with entries.
+// Entries that are no longer selected drop out, which restarts their grace
+// period if they qualify again later.
+func (d *DeletionDB) ReplacePending(rules []string, entries []PendingDeletion) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, rule := range rules {
+		if _, err := tx.Exec(`DELETE FROM pending_deletions WHERE path_rule = ?`, rule); err != nil {
+			return fmt.Errorf("failed to clear pending deletions for %s: %w", rule, err)
+		}
+	}
+	stmt, err := tx.Prepare(`
+		INSERT OR REPLACE INTO pending_deletions
+			(path, path_rule, object_type, size, mod_time_ns, reason, first_seen, due_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = stmt.Close() }()
+	for _, p := range entries {
+		if _, err := stmt.Exec(p.Path, p.PathRule, p.ObjectType, p.Size, p.ModTime.UnixNano(), p.Reason, p.FirstSeen, p.DueAt); err != nil {
+			return fmt.Errorf("failed to record pending deletion %s: %w", p.Path, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ClearPending removes pending deletions for paths that have been removed
+func (d *DeletionDB) ClearPending(paths []string) error {
+	for _, p := range paths {
+		if _, err := d.db.Exec(`DELETE FROM pending_deletions WHERE path = ?`, p); err != nil {
+			return fmt.Errorf("failed to clear pending deletion %s: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// AddReprieve exempts r.Path and everything below it from deletion and drops
+// its pending deletions. Reprieving an already reprieved path replaces it.
+func (d *DeletionDB) AddReprieve(r Reprieve) error {
+	path := filepath.Clean(r.Path)
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("reprieve path must be absolute: %q", r.Path)
+	}
+	if r.ReprievedAt.IsZero() {
+		r.ReprievedAt = time.Now()
+	}
+	var expires sql.NullTime
+	if r.ExpiresAt != nil {
+		expires = sql.NullTime{Time: *r.ExpiresAt, Valid: true}
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`
+		INSERT OR REPLACE INTO reprieves (path, reason, reprieved_by, reprieved_at, expires_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		path, r.Reason, r.ReprievedBy, r.ReprievedAt, expires); err != nil {
+		return fmt.Errorf("failed to reprieve %s: %w", path, err)
+	}
+	prefix := strings.TrimSuffix(path, string(filepath.Separator)) + string(filepath.Separator)
+	if _, err := tx.Exec(`DELETE FROM pending_deletions WHERE path = ? OR substr(path, 1, ?) = ?`,
+		path, len(prefix), prefix); err != nil {
+		return fmt.Errorf("failed to drop pending deletions under %s: %w", path, err)
+	}
+	return tx.Commit()
+}
+
+// RemoveReprieve lifts the reprieve on path; it reports false if there was none
+func (d *DeletionDB) RemoveReprieve(path string) (bool, error) {
+	res, err := d.db.Exec(`DELETE FROM reprieves WHERE path = ?`, filepath.Clean(path))
+	if err != nil {
+		return false, fmt.Errorf("failed to remove reprieve %s: %w", path, err)
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// GetReprieves returns the reprieves in force at now, ordered by path
+func (d *DeletionDB) GetReprieves(now time.Time) ([]Reprieve, error) {
+	rows, err := d.db.Query(`SELECT path, COALESCE(reason, ''), COALESCE(reprieved_by, ''), reprieved_at, expires_at FROM reprieves ORDER BY path`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reprieves: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var reprieves []Reprieve
+	for rows.Next() {
+		var r Reprieve
+		var expires sql.NullTime
+		if err := rows.Scan(&r.Path, &r.Reason, &r.ReprievedBy, &r.ReprievedAt, &expires); err != nil {
+			return nil, err
+		}
+		if expires.Valid {
+			t := expires.Time
+			r.ExpiresAt = &t
+		}
+		if r.Active(now) {
+			reprieves = append(reprieves, r)
+		}
+	}
+	return reprieves, rows.Err()
+}
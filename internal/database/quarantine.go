@@ -0,0 +1,75 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+// QuarantineEntry is a path whose deletes failed with a permanent error
+type QuarantineEntry struct {
+	Path         string    `json:"path"`
+	Errno        string    `json:"errno"`
+	ErrorMessage string    `json:"error_message,omitempty"`
+	Failures     int       `json:"failures"` // Permanent failures recorded since the path was first quarantined
+	FirstFailed  time.Time `json:"first_failed"`
+	LastFailed   time.Time `json:"last_failed"`
+	Until        time.Time `json:"until"` // Deletes are not retried before this time
+}
+
+// Active reports whether retries of the path are still suppressed at now
+func (q QuarantineEntry) Active(now time.Time) bool {
+	return now.Before(q.Until)
+}
+
+// Quarantine suppresses retries of path until until. Quarantining a path
+// again, after its quarantine expired and the retry failed, counts another failure.
+func (d *DeletionDB) Quarantine(path, errno, errorMsg string, now, until time.Time) error {
+	_, err := d.db.Exec(`
+		INSERT INTO quarantine (path, errno, error_message, failures, first_failed, last_failed, until)
+		VALUES (?, ?, ?, 1, ?, ?, ?)
+		ON CONFLICT(path) DO UPDATE SET
+			errno = excluded.errno,
+			error_message = excluded.error_message,
+			failures = quarantine.failures + 1,
+			last_failed = excluded.last_failed,
+			until = excluded.until`,
+		path, errno, errorMsg, now, now, until)
+	if err != nil {
+		return fmt.Errorf("failed to quarantine %s: %w", path, err)
+	}
+	return nil
+}
+
+// GetQuarantine returns every quarantined path, including expired entries, ordered by path
+func (d *DeletionDB) GetQuarantine() ([]QuarantineEntry, error) {
+	rows, err := d.db.Query(`SELECT path, errno, error_message, failures, first_failed, last_failed, until
+		FROM quarantine ORDER BY path`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query quarantine: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var entries []QuarantineEntry
+	for rows.Next() {
+		var q QuarantineEntry
+		var errMsg sql.NullString
+		if err := rows.Scan(&q.Path, &q.Errno, &errMsg, &q.Failures, &q.FirstFailed, &q.LastFailed, &q.Until); err != nil {
+			return nil, err
+		}
+		q.ErrorMessage = errMsg.String
+		entries = append(entries, q)
+	}
+	return entries, rows.Err()
+}
+
+// ReleaseQuarantine lets path be retried on the next cycle; it reports false if it was not quarantined
+func (d *DeletionDB) ReleaseQuarantine(path string) (bool, error) {
+	res, err := d.db.Exec(`DELETE FROM quarantine WHERE path = ?`, filepath.Clean(path))
+	if err != nil {
+		return false, fmt.Errorf("failed to release %s from quarantine: %w", path, err)
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
@@ -9,7 +9,7 @@ import (
 func (d *DeletionDB) GetRecentDeletions(limit int) ([]DeletionRecord, error) {
 	query := `
 	SELECT id, timestamp, action, path, file_name, object_type, size,
-	       deletion_reason, primary_reason, path_rule, error_message
+	       deletion_reason, primary_reason, path_rule, error_message, uid, gid, errno, size_after
 	FROM deletions
 	ORDER BY timestamp DESC
 	LIMIT ?
@@ -22,7 +22,7 @@ func (d *DeletionDB) GetRecentDeletions(limit int) ([]DeletionRecord, error) {
 func (d *DeletionDB) GetDeletionsByDateRange(start, end time.Time) ([]DeletionRecord, error) {
 	query := `
 	SELECT id, timestamp, action, path, file_name, object_type, size,
-	       deletion_reason, primary_reason, path_rule, error_message
+	       deletion_reason, primary_reason, path_rule, error_message, uid, gid, errno, size_after
 	FROM deletions
 	WHERE timestamp BETWEEN ? AND ?
 	ORDER BY timestamp DESC
@@ -35,7 +35,7 @@ func (d *DeletionDB) GetDeletionsByDateRange(start, end time.Time) ([]DeletionRe
 func (d *DeletionDB) GetDeletionsByReason(primaryReason string) ([]DeletionRecord, error) {
 	query := `
 	SELECT id, timestamp, action, path, file_name, object_type, size,
-	       deletion_reason, primary_reason, path_rule, error_message
+	       deletion_reason, primary_reason, path_rule, error_message, uid, gid, errno, size_after
 	FROM deletions
 	WHERE primary_reason = ?
 	ORDER BY timestamp DESC
@@ -48,7 +48,7 @@ func (d *DeletionDB) GetDeletionsByReason(primaryReason string) ([]DeletionRecor
 func (d *DeletionDB) GetDeletionsByPath(pathPattern string) ([]DeletionRecord, error) {
 	query := `
 	SELECT id, timestamp, action, path, file_name, object_type, size,
-	       deletion_reason, primary_reason, path_rule, error_message
+	       deletion_reason, primary_reason, path_rule, error_message, uid, gid, errno, size_after
 	FROM deletions
 	WHERE path LIKE ?
 	ORDER BY timestamp DESC
@@ -61,7 +61,7 @@ func (d *DeletionDB) GetDeletionsByPath(pathPattern string) ([]DeletionRecord, e
 func (d *DeletionDB) GetDeletionsByAction(action string) ([]DeletionRecord, error) {
 	query := `
 	SELECT id, timestamp, action, path, file_name, object_type, size,
-	       deletion_reason, primary_reason, path_rule, error_message
+	       deletion_reason, primary_reason, path_rule, error_message, uid, gid, errno, size_after
 	FROM deletions
 	WHERE action = ?
 	ORDER BY timestamp DESC
@@ -74,7 +74,7 @@ func (d *DeletionDB) GetDeletionsByAction(action string) ([]DeletionRecord, erro
 func (d *DeletionDB) GetLargestDeletions(limit int) ([]DeletionRecord, error) {
 	query := `
 	SELECT id, timestamp, action, path, file_name, object_type, size,
-	       deletion_reason, primary_reason, path_rule, error_message
+	       deletion_reason, primary_reason, path_rule, error_message, uid, gid, errno, size_after
 	FROM deletions
 	WHERE action = 'DELETE'
 	ORDER BY size DESC
@@ -238,6 +238,53 @@ func (d *DeletionDB) GetTopPathsByDeletionCount(limit int) (map[string]int, erro
 	return counts, rows.Err()
 }
 
+// OwnerUsage aggregates freed space for one uid or gid
+type OwnerUsage struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name,omitempty"` // Filled in by callers that resolve account names
+	Files int    `json:"files"`
+	Bytes int64  `json:"bytes"`
+}
+
+// GetBytesFreedByOwner returns freed space per owning user in a time range
+func (d *DeletionDB) GetBytesFreedByOwner(start, end time.Time) ([]OwnerUsage, error) {
+	return d.bytesFreedBy("uid", start, end)
+}
+
+// GetBytesFreedByGroup returns freed space per owning group in a time range
+func (d *DeletionDB) GetBytesFreedByGroup(start, end time.Time) ([]OwnerUsage, error) {
+	return d.bytesFreedBy("gid", start, end)
+}
+
+// bytesFreedBy groups DELETE rows by an ownership column, largest first.
+// Records written before ownership was tracked are excluded.
+func (d *DeletionDB) bytesFreedBy(column string, start, end time.Time) ([]OwnerUsage, error) {
+	query := `
+	SELECT ` + column + `, COUNT(*), COALESCE(SUM(size), 0)
+	FROM deletions
+	WHERE action = 'DELETE' AND ` + column + ` IS NOT NULL AND timestamp BETWEEN ? AND ?
+	GROUP BY ` + column + `
+	ORDER BY SUM(size) DESC
+	`
+
+	rows, err := d.db.Query(query, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var usage []OwnerUsage
+	for rows.Next() {
+		var u OwnerUsage
+		if err := rows.Scan(&u.ID, &u.Files, &u.Bytes); err != nil {
+			return nil, err
+		}
+		usage = append(usage, u)
+	}
+
+	return usage, rows.Err()
+}
+
 // DeleteOldRecords removes records older than specified days (for cleanup)
 func (d *DeletionDB) DeleteOldRecords(olderThanDays int) (int64, error) {
 	cutoff := time.Now().AddDate(0, 0, -olderThanDays)
@@ -263,12 +310,12 @@ func (d *DeletionDB) queryDeletions(query string, args ...interface{}) ([]Deleti
 	var records []DeletionRecord
 	for rows.Next() {
 		var r DeletionRecord
-		var errMsg sql.NullString
+		var errMsg, errno sql.NullString
 
 		err := rows.Scan(
 			&r.ID, &r.Timestamp, &r.Action, &r.Path, &r.FileName,
 			&r.ObjectType, &r.Size, &r.DeletionReason,
-			&r.PrimaryReason, &r.PathRule, &errMsg,
+			&r.PrimaryReason, &r.PathRule, &errMsg, &r.UID, &r.GID, &errno, &r.SizeAfter,
 		)
 		if err != nil {
 			return nil, err
@@ -277,6 +324,7 @@ func (d *DeletionDB) queryDeletions(query string, args ...interface{}) ([]Deleti
 		if errMsg.Valid {
 			r.ErrorMessage = errMsg.String
 		}
+		r.Errno = errno.String
 
 		records = append(records, r)
 	}
@@ -296,7 +344,7 @@ func (d *DeletionDB) GetRecentDeletionsPaginated(limit, offset int) ([]DeletionR
 	// Get paginated records
 	query := `
 	SELECT id, timestamp, action, path, file_name, object_type, size,
-	       deletion_reason, primary_reason, path_rule, error_message
+	       deletion_reason, primary_reason, path_rule, error_message, uid, gid, errno, size_after
 	FROM deletions
 	ORDER BY timestamp DESC
 	LIMIT ? OFFSET ?
@@ -318,7 +366,7 @@ func (d *DeletionDB) GetDeletionsByActionPaginated(action string, limit, offset
 	// Get paginated records
 	query := `
 	SELECT id, timestamp, action, path, file_name, object_type, size,
-	       deletion_reason, primary_reason, path_rule, error_message
+	       deletion_reason, primary_reason, path_rule, error_message, uid, gid, errno, size_after
 	FROM deletions
 	WHERE action = ?
 	ORDER BY timestamp DESC
@@ -340,7 +388,7 @@ func (d *DeletionDB) GetDeletionsByReasonPaginated(reason string, limit, offset
 
 	query := `
 	SELECT id, timestamp, action, path, file_name, object_type, size,
-	       deletion_reason, primary_reason, path_rule, error_message
+	       deletion_reason, primary_reason, path_rule, error_message, uid, gid, errno, size_after
 	FROM deletions
 	WHERE primary_reason = ?
 	ORDER BY timestamp DESC
@@ -362,7 +410,7 @@ func (d *DeletionDB) GetDeletionsByPathPaginated(pathPattern string, limit, offs
 
 	query := `
 	SELECT id, timestamp, action, path, file_name, object_type, size,
-	       deletion_reason, primary_reason, path_rule, error_message
+	       deletion_reason, primary_reason, path_rule, error_message, uid, gid, errno, size_after
 	FROM deletions
 	WHERE path LIKE ?
 	ORDER BY timestamp DESC
@@ -0,0 +1,108 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"storage-sage/internal/scan"
+)
+
+// CycleRun is the record of one cleanup cycle
+type CycleRun struct {
+	ID           int64     `json:"id"`
+	StartedAt    time.Time `json:"started_at"`
+	FinishedAt   time.Time `json:"finished_at"`
+	DryRun       bool      `json:"dry_run"`
+	Candidates   int       `json:"candidates"`
+	Deleted      int       `json:"deleted"`
+	BytesFreed   int64     `json:"bytes_freed"`
+	ScanErrors   int       `json:"scan_errors"`   // Entries the scan could not read
+	SkippedBytes int64     `json:"skipped_bytes"` // Estimated bytes below unreadable directories
+	Message      string    `json:"message,omitempty"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// RecordRun stores a cycle and the entries its scan could not read, and returns the run ID
+func (d *DeletionDB) RecordRun(run CycleRun, scanErrors []scan.ScanError) (int64, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec(`
+		INSERT INTO cycle_runs (started_at, finished_at, dry_run, candidates, deleted, bytes_freed, scan_errors, skipped_bytes, message, error)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		run.StartedAt, run.FinishedAt, run.DryRun, run.Candidates, run.Deleted, run.BytesFreed,
+		run.ScanErrors, run.SkippedBytes, run.Message, run.Error)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record cycle: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if len(scanErrors) > 0 {
+		stmt, err := tx.Prepare(`
+			INSERT INTO scan_errors (run_id, path_rule, path, kind, error_message, is_dir, estimated_bytes)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`)
+		if err != nil {
+			return 0, err
+		}
+		defer func() { _ = stmt.Close() }()
+		for _, e := range scanErrors {
+			if _, err := stmt.Exec(id, e.PathRule, e.Path, e.Kind, e.Error, e.IsDir, e.EstimatedBytes); err != nil {
+				return 0, fmt.Errorf("failed to record scan error %s: %w", e.Path, err)
+			}
+		}
+	}
+	return id, tx.Commit()
+}
+
+// GetRecentRuns returns the N most recent cycles, newest first
+func (d *DeletionDB) GetRecentRuns(limit int) ([]CycleRun, error) {
+	rows, err := d.db.Query(`
+		SELECT id, started_at, finished_at, dry_run, candidates, deleted, bytes_freed, scan_errors, skipped_bytes,
+		       COALESCE(message, ''), COALESCE(error, '')
+		FROM cycle_runs ORDER BY id DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cycles: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var runs []CycleRun
+	for rows.Next() {
+		var r CycleRun
+		if err := rows.Scan(&r.ID, &r.StartedAt, &r.FinishedAt, &r.DryRun, &r.Candidates, &r.Deleted, &r.BytesFreed,
+			&r.ScanErrors, &r.SkippedBytes, &r.Message, &r.Error); err != nil {
+			return nil, err
+		}
+		runs = append(runs, r)
+	}
+	return runs, rows.Err()
+}
+
+// GetScanErrors returns the unreadable entries recorded for a cycle, ordered by rule and path
+func (d *DeletionDB) GetScanErrors(runID int64) ([]scan.ScanError, error) {
+	rows, err := d.db.Query(`
+		SELECT path_rule, path, kind, error_message, is_dir, estimated_bytes
+		FROM scan_errors WHERE run_id = ? ORDER BY path_rule, path`, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query scan errors: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var errs []scan.ScanError
+	for rows.Next() {
+		var e scan.ScanError
+		var msg sql.NullString
+		if err := rows.Scan(&e.PathRule, &e.Path, &e.Kind, &msg, &e.IsDir, &e.EstimatedBytes); err != nil {
+			return nil, err
+		}
+		e.Error = msg.String
+		errs = append(errs, e)
+	}
+	return errs, rows.Err()
+}
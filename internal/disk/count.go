@@ -0,0 +1,82 @@
+package disk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// TreeCount holds the totals gathered by CountTree
+type TreeCount struct {
+	ApparentBytes  int64 // Sum of st_size of regular files (what `du -sb` reports for files)
+	AllocatedBytes int64 // Sum of st_blocks*512 of regular files (what is actually used on disk)
+	Files          int64 // Regular files
+	Dirs           int64 // Directories below the root (root excluded)
+	Symlinks       int64 // Symbolic links (never followed)
+}
+
+// ErrCountStalled is returned when the filesystem stops making progress for
+// longer than the stall timeout, which usually means a hung NFS mount.
+var ErrCountStalled = errors.New("tree count stalled")
+
+// StallTimeout aborts CountTree when no entry has been processed for this long.
+// The scheduler sets it from nfs_timeout_seconds.
+var StallTimeout = 5 * time.Second
+
+// SetStallTimeout allows runtime configuration of the stall timeout
+func SetStallTimeout(timeout time.Duration) {
+	StallTimeout = timeout
+}
+
+// CountTree counts files, directories and symlinks under root and sums their
+// apparent and allocated sizes in a single pass, without following symlinks
+// or shelling out. It stops early when ctx is cancelled or when the walk makes
+// no progress for stallTimeout (0 disables the stall check). A stalled
+// syscall cannot be interrupted, so the walking goroutine is abandoned.
+func CountTree(ctx context.Context, root string, stallTimeout time.Duration) (*TreeCount, error) {
+	var progress atomic.Int64
+
+	type result struct {
+		count *TreeCount
+		err   error
+	}
+	done := make(chan result, 1)
+
+	walkCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		tc := &TreeCount{}
+		err := countTree(walkCtx, root, tc, &progress)
+		done <- result{count: tc, err: err}
+	}()
+
+	var stall <-chan time.Time
+	var ticker *time.Ticker
+	if stallTimeout > 0 {
+		ticker = time.NewTicker(stallTimeout)
+		defer ticker.Stop()
+		stall = ticker.C
+	}
+
+	last := progress.Load()
+	for {
+		select {
+		case r := <-done:
+			if r.err != nil {
+				return nil, fmt.Errorf("count %s: %w", root, r.err)
+			}
+			return r.count, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-stall:
+			current := progress.Load()
+			if current == last {
+				return nil, fmt.Errorf("count %s: %w after %s", root, ErrCountStalled, stallTimeout)
+			}
+			last = current
+		}
+	}
+}
@@ -0,0 +1,121 @@
+//go:build linux
+
+package disk
+
+import (
+	"context"
+	"encoding/binary"
+	"sync/atomic"
+
+	"golang.org/x/sys/unix"
+)
+
+// direntBufSize is the getdents64 batch size; large batches keep the number
+// of syscalls low on directories with millions of entries.
+const direntBufSize = 64 * 1024
+
+// countTree walks root with getdents64 batch reads and fstatat relative to
+// the parent directory fd, so paths are never re-resolved from "/".
+func countTree(ctx context.Context, root string, tc *TreeCount, progress *atomic.Int64) error {
+	fd, err := unix.Open(root, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, direntBufSize)
+	return countDir(ctx, fd, buf, tc, progress)
+}
+
+// countDir counts the entries of the directory open at fd and closes it
+func countDir(ctx context.Context, fd int, buf []byte, tc *TreeCount, progress *atomic.Int64) error {
+	defer func() { _ = unix.Close(fd) }()
+
+	var subdirs []string
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		n, err := unix.Getdents(fd, buf)
+		if err == unix.EINTR {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if n <= 0 {
+			break
+		}
+
+		for off := 0; off < n; {
+			reclen := int(binary.NativeEndian.Uint16(buf[off+16 : off+18]))
+			typ := buf[off+18]
+			name := direntName(buf[off+19 : off+reclen])
+			off += reclen
+
+			if name == "." || name == ".." {
+				continue
+			}
+			progress.Add(1)
+
+			if typ == unix.DT_UNKNOWN || typ == unix.DT_REG {
+				var st unix.Stat_t
+				if err := unix.Fstatat(fd, name, &st, unix.AT_SYMLINK_NOFOLLOW); err != nil {
+					// Entry vanished or is unreadable - skip it like WalkDir does
+					continue
+				}
+				typ = modeToDirentType(st.Mode)
+				if typ == unix.DT_REG {
+					tc.Files++
+					tc.ApparentBytes += st.Size
+					tc.AllocatedBytes += st.Blocks * 512
+					continue
+				}
+			}
+
+			switch typ {
+			case unix.DT_DIR:
+				subdirs = append(subdirs, name)
+			case unix.DT_LNK:
+				tc.Symlinks++
+			}
+		}
+	}
+
+	// Descend after the listing is complete so at most one buffer's worth of
+	// state is held per level and each directory fd is released quickly.
+	for _, name := range subdirs {
+		childFd, err := unix.Openat(fd, name, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+		if err != nil {
+			continue
+		}
+		tc.Dirs++
+		if err := countDir(ctx, childFd, buf, tc, progress); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// direntName extracts the NUL-terminated name from a dirent record
+func direntName(b []byte) string {
+	for i, c := range b {
+		if c == 0 {
+			return string(b[:i])
+		}
+	}
+	return string(b)
+}
+
+// modeToDirentType maps st_mode to the matching d_type value
+func modeToDirentType(mode uint32) uint8 {
+	switch mode & unix.S_IFMT {
+	case unix.S_IFREG:
+		return unix.DT_REG
+	case unix.S_IFDIR:
+		return unix.DT_DIR
+	case unix.S_IFLNK:
+		return unix.DT_LNK
+	default:
+		return unix.DT_UNKNOWN
+	}
+}
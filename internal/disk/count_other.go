@@ -0,0 +1,42 @@
+//go:build !linux
+
+package disk
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"sync/atomic"
+	"syscall"
+)
+
+// countTree is the portable fallback used where getdents64 is unavailable
+func countTree(ctx context.Context, root string, tc *TreeCount, progress *atomic.Int64) error {
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil || p == root {
+			return nil
+		}
+		progress.Add(1)
+
+		switch {
+		case d.Type()&fs.ModeSymlink != 0:
+			tc.Symlinks++
+		case d.IsDir():
+			tc.Dirs++
+		case d.Type().IsRegular():
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			tc.Files++
+			tc.ApparentBytes += info.Size()
+			if st, ok := info.Sys().(*syscall.Stat_t); ok {
+				tc.AllocatedBytes += int64(st.Blocks) * 512
+			}
+		}
+		return nil
+	})
+}
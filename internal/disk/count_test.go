@@ -0,0 +1,64 @@
+package disk
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestCountTree verifies the native counter matches a known tree
+func TestCountTree(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "a", "b"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	files := map[string]int{
+		"top.txt":       10,
+		"a/one.txt":     20,
+		"a/b/two.txt":   30,
+		"a/b/three.txt": 0,
+	}
+	for name, size := range files {
+		if err := os.WriteFile(filepath.Join(root, name), make([]byte, size), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	if err := os.Symlink("/etc", filepath.Join(root, "a", "link")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	tc, err := CountTree(context.Background(), root, time.Second)
+	if err != nil {
+		t.Fatalf("CountTree failed: %v", err)
+	}
+
+	if tc.Files != 4 {
+		t.Errorf("Files = %d, want 4", tc.Files)
+	}
+	if tc.Dirs != 2 {
+		t.Errorf("Dirs = %d, want 2", tc.Dirs)
+	}
+	if tc.Symlinks != 1 {
+		t.Errorf("Symlinks = %d, want 1 (symlinks must not be followed)", tc.Symlinks)
+	}
+	if tc.ApparentBytes != 60 {
+		t.Errorf("ApparentBytes = %d, want 60", tc.ApparentBytes)
+	}
+	if tc.AllocatedBytes < 0 {
+		t.Errorf("AllocatedBytes = %d, must not be negative", tc.AllocatedBytes)
+	}
+}
+
+// TestCountTreeCancelled verifies a cancelled context stops the count
+func TestCountTreeCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := CountTree(ctx, t.TempDir(), 0)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -1,22 +1,26 @@
 package disk
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/fs"
-	"os/exec"
 	"path/filepath"
-	"strconv"
-	"strings"
 	"sync"
+	"syscall"
 	"time"
 )
 
 // PathStats contains detailed statistics about a filesystem path
 type PathStats struct {
-	UsedBytes  int64 // Total bytes used by files in this path
-	FileCount  int64 // Total number of regular files
-	FreeBytes  int64 // Free space available on the filesystem
-	TotalBytes int64 // Total capacity of the filesystem
+	UsedBytes      int64 // Total bytes used by files in this path (apparent size)
+	AllocatedBytes int64 // Total bytes allocated on disk for files in this path
+	FileCount      int64 // Total number of regular files
+	DirCount       int64 // Total number of directories below the path
+	SymlinkCount   int64 // Total number of symbolic links
+	FreeBytes      int64 // Free space available on the filesystem
+	TotalBytes     int64 // Total capacity of the filesystem
+	WalkErrors     int64 // Entries that could not be read; usage below them is not counted
 }
 
 // ScanCache stores previous scan results for incremental updates
@@ -36,7 +40,7 @@ var (
 		cache: make(map[string]*cachedScan),
 	}
 
-	// FastScanThreshold: if file count exceeds this, use du -sb
+	// FastScanThreshold: if file count exceeds this, use the native tree counter
 	FastScanThreshold int64 = 1000000 // 1M files
 
 	// CacheTTL: how long to trust cached results
@@ -46,7 +50,7 @@ var (
 // ScanPath walks a directory tree and computes detailed usage statistics.
 // Automatically uses optimizations:
 // - Incremental caching (5 min TTL)
-// - Fast scan mode (du -sb) for >1M files
+// - Fast scan mode (native getdents64 counter) for >1M files
 // - Parallel scanning when multiple paths provided via ScanPathsParallel
 func ScanPath(path string) (*PathStats, error) {
 	return ScanPathWithOptions(path, true, true)
@@ -54,6 +58,11 @@ func ScanPath(path string) (*PathStats, error) {
 
 // ScanPathWithOptions provides control over scan optimizations
 func ScanPathWithOptions(path string, useCache bool, useFastScan bool) (*PathStats, error) {
+	return ScanPathContext(context.Background(), path, useCache, useFastScan)
+}
+
+// ScanPathContext is ScanPathWithOptions with cancellation support
+func ScanPathContext(ctx context.Context, path string, useCache bool, useFastScan bool) (*PathStats, error) {
 	stats := &PathStats{}
 
 	// Get filesystem-level statistics (free/total space)
@@ -77,39 +86,66 @@ func ScanPathWithOptions(path string, useCache bool, useFastScan bool) (*PathSta
 		}
 	}
 
-	// Perform path-level scan
-	var pathUsedBytes int64
-	var fileCount int64
-
 	// Check if we should use fast scan mode
 	if useFastScan {
 		// Quick sample: count first 10K files to estimate total
 		sample := estimateFileCount(path, 10000)
 		if sample >= FastScanThreshold {
-			// Use du -sb for very large trees
-			usedBytes, count, err := scanWithDu(path)
+			// Use the native single-pass counter for very large trees
+			tc, err := CountTree(ctx, path, StallTimeout)
 			if err == nil {
-				pathUsedBytes = usedBytes
-				fileCount = count
+				stats.UsedBytes = tc.ApparentBytes
+				stats.AllocatedBytes = tc.AllocatedBytes
+				stats.FileCount = tc.Files
+				stats.DirCount = tc.Dirs
+				stats.SymlinkCount = tc.Symlinks
 				goto cacheAndReturn
 			}
-			// Fall through to WalkDir on error
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			// A walk of the same hung mount would only hang in its place
+			if errors.Is(err, ErrCountStalled) {
+				return nil, err
+			}
+			// Fall through to WalkDir on other errors
 		}
 	}
 
 	// Standard WalkDir scan
 	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
 		if err != nil {
-			return nil // Skip errors
+			// Keep walking, but count what could not be read so the totals are known to be partial
+			if !errors.Is(err, fs.ErrNotExist) {
+				stats.WalkErrors++
+			}
+			return nil
+		}
+		if p == path {
+			return nil
 		}
 
-		if d.Type().IsRegular() {
+		switch {
+		case d.Type()&fs.ModeSymlink != 0:
+			stats.SymlinkCount++
+		case d.IsDir():
+			stats.DirCount++
+		case d.Type().IsRegular():
 			info, err := d.Info()
 			if err != nil {
+				if !errors.Is(err, fs.ErrNotExist) {
+					stats.WalkErrors++
+				}
 				return nil
 			}
-			pathUsedBytes += info.Size()
-			fileCount++
+			stats.UsedBytes += info.Size()
+			if st, ok := info.Sys().(*syscall.Stat_t); ok {
+				stats.AllocatedBytes += int64(st.Blocks) * 512
+			}
+			stats.FileCount++
 		}
 
 		return nil
@@ -120,9 +156,6 @@ func ScanPathWithOptions(path string, useCache bool, useFastScan bool) (*PathSta
 	}
 
 cacheAndReturn:
-	stats.UsedBytes = pathUsedBytes
-	stats.FileCount = fileCount
-
 	// Update cache
 	if useCache {
 		globalScanCache.set(path, stats)
@@ -131,39 +164,6 @@ cacheAndReturn:
 	return stats, nil
 }
 
-// scanWithDu uses external `du -sb` command for fast scanning of huge trees
-func scanWithDu(path string) (usedBytes int64, fileCount int64, err error) {
-	// Get used bytes with du -sb
-	cmd := exec.Command("du", "-sb", path)
-	output, err := cmd.Output()
-	if err != nil {
-		return 0, 0, fmt.Errorf("du failed: %w", err)
-	}
-
-	// Parse: "123456\t/path/to/dir"
-	parts := strings.Fields(string(output))
-	if len(parts) < 1 {
-		return 0, 0, fmt.Errorf("invalid du output")
-	}
-
-	usedBytes, err = strconv.ParseInt(parts[0], 10, 64)
-	if err != nil {
-		return 0, 0, fmt.Errorf("parse du output: %w", err)
-	}
-
-	// Get file count with find (faster than WalkDir for count only)
-	cmd = exec.Command("find", path, "-type", "f", "-printf", ".")
-	output, err = cmd.Output()
-	if err != nil {
-		// Non-fatal: return bytes with estimated count
-		fileCount = usedBytes / 4096 // Rough estimate: avg 4KB/file
-		return usedBytes, fileCount, nil
-	}
-
-	fileCount = int64(len(output))
-	return usedBytes, fileCount, nil
-}
-
 // estimateFileCount does a limited walk to estimate total file count
 func estimateFileCount(path string, sampleLimit int) int64 {
 	var count int64
@@ -181,6 +181,11 @@ func estimateFileCount(path string, sampleLimit int) int64 {
 
 // ScanPathsParallel scans multiple paths concurrently
 func ScanPathsParallel(paths []string) (map[string]*PathStats, error) {
+	return ScanPathsParallelContext(context.Background(), paths)
+}
+
+// ScanPathsParallelContext scans multiple paths concurrently and stops when ctx is cancelled
+func ScanPathsParallelContext(ctx context.Context, paths []string) (map[string]*PathStats, error) {
 	results := make(map[string]*PathStats)
 	var mu sync.Mutex
 	var wg sync.WaitGroup
@@ -191,7 +196,7 @@ func ScanPathsParallel(paths []string) (map[string]*PathStats, error) {
 		go func(p string) {
 			defer wg.Done()
 
-			stats, err := ScanPath(p)
+			stats, err := ScanPathContext(ctx, p, true, true)
 			if err != nil {
 				errChan <- fmt.Errorf("scan %s: %w", p, err)
 				return
@@ -0,0 +1,140 @@
+package fsops
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression formats CompressFile writes, as named in config
+const (
+	FormatGzip = "gzip"
+	FormatZstd = "zstd"
+)
+
+// Suffixes CompressFile appends to the name of the file it compresses
+const (
+	GzipExt = ".gz"
+	ZstdExt = ".zst"
+)
+
+var (
+	// ErrCompressedExists is returned when the compressed file's name is already taken
+	ErrCompressedExists = errors.New("compressed file already exists")
+
+	// ErrChangedWhileCompressing is returned when the file was replaced or written to during compression
+	ErrChangedWhileCompressing = errors.New("file changed while it was compressed")
+
+	// ErrOriginalNotRemoved is returned, with the compressed file's name, when
+	// the copy was linked into place but the original could not be removed;
+	// the caller removes the copy rather than keep both
+	ErrOriginalNotRemoved = errors.New("compressed copy linked but original not removed")
+)
+
+// CompressedExt returns the suffix of a file compressed in format
+func CompressedExt(format string) (string, error) {
+	switch format {
+	case FormatGzip:
+		return GzipExt, nil
+	case FormatZstd:
+		return ZstdExt, nil
+	default:
+		return "", fmt.Errorf("unknown compression format %q", format)
+	}
+}
+
+// compressInto writes a copy of src, the file info describes, compressed in
+// format to tmp, syncs it, reads it back and checks it against what was read,
+// and gives it src's owner and mode through its descriptor. It returns the
+// bytes read from src.
+func compressInto(tmp, src *os.File, info os.FileInfo, format string, level int) (int64, error) {
+	zw, err := newCompressWriter(tmp, src, info, format, level)
+	if err != nil {
+		return 0, err
+	}
+	// Compress, keeping a checksum of what was read to compare the copy against
+	sum := crc32.NewIEEE()
+	n, err := io.Copy(zw, io.TeeReader(src, sum))
+	if err != nil {
+		return 0, err
+	}
+	if err := zw.Close(); err != nil {
+		return 0, err
+	}
+	if err := tmp.Sync(); err != nil {
+		return 0, err
+	}
+	if err := verifyCompressed(tmp, format, n, sum.Sum32()); err != nil {
+		return 0, err
+	}
+
+	// Owner first: chown may clear setuid and setgid bits that chmod restores
+	if err := preserveOwner(tmp, info); err != nil {
+		return 0, err
+	}
+	if err := tmp.Chmod(info.Mode() & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky)); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// newCompressWriter returns a writer compressing to w in format; gzip's
+// header records src's name and mtime
+func newCompressWriter(w io.Writer, src *os.File, info os.FileInfo, format string, level int) (io.WriteCloser, error) {
+	switch format {
+	case FormatGzip:
+		zw, err := gzip.NewWriterLevel(w, level)
+		if err != nil {
+			return nil, err
+		}
+		zw.Name = filepath.Base(src.Name())
+		zw.ModTime = info.ModTime()
+		return zw, nil
+	case FormatZstd:
+		return zstd.NewWriter(w, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+	default:
+		return nil, fmt.Errorf("unknown compression format %q", format)
+	}
+}
+
+// verifyCompressed reads f back from the start and checks it decompresses
+// from format to size bytes with the given CRC-32
+func verifyCompressed(f *os.File, format string, size int64, crc uint32) error {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	var zr io.Reader
+	closeReader := func() error { return nil }
+	switch format {
+	case FormatGzip:
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("verify compressed copy: %w", err)
+		}
+		zr, closeReader = gr, gr.Close
+	case FormatZstd:
+		dr, err := zstd.NewReader(f, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return fmt.Errorf("verify compressed copy: %w", err)
+		}
+		defer dr.Close()
+		zr = dr
+	default:
+		return fmt.Errorf("unknown compression format %q", format)
+	}
+	sum := crc32.NewIEEE()
+	n, err := io.Copy(sum, zr)
+	if err != nil {
+		return fmt.Errorf("verify compressed copy: %w", err)
+	}
+	if n != size || sum.Sum32() != crc {
+		return fmt.Errorf("verify compressed copy: got %d bytes (crc %08x), want %d (crc %08x)", n, sum.Sum32(), size, crc)
+	}
+	return closeReader()
+}
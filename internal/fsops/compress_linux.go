@@ -0,0 +1,175 @@
+//go:build linux
+
+package fsops
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// CompressFile replaces the regular file at path with a copy compressed in
+// format, named path plus CompressedExt(format), and returns that name and the
+// compressed size. The copy is written to a temp file in the same directory,
+// synced, read back and checked against the original, given the original's
+// mode, owner and times, and only then linked into place, so the compressed
+// name never holds a partial file. The original is unlinked right after it is
+// checked once more to be the file that was read, unchanged. The parent
+// directory is resolved by path; SecureDeleter.Compress reaches it from a rule
+// root without following symlinks.
+func CompressFile(path, format string, level int) (string, int64, error) {
+	dirfd, err := unix.Open(filepath.Dir(path), unix.O_RDONLY|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return "", 0, &os.PathError{Op: "open", Path: filepath.Dir(path), Err: err}
+	}
+	defer func() { _ = unix.Close(dirfd) }()
+	return compressAt(dirfd, filepath.Base(path), path, format, level)
+}
+
+// compressAt compresses name under dirfd like CompressFile. Every step is relative to
+// dirfd and none follows a symlink, so a directory swapped after dirfd was
+// opened cannot send the copy, its owner or its link anywhere else.
+func compressAt(dirfd int, name, fullPath, format string, level int) (string, int64, error) {
+	ext, err := CompressedExt(format)
+	if err != nil {
+		return "", 0, err
+	}
+	dstName := name + ext
+	dst := fullPath + ext
+	var st unix.Stat_t
+	if err := unix.Fstatat(dirfd, dstName, &st, unix.AT_SYMLINK_NOFOLLOW); err == nil {
+		return "", 0, fmt.Errorf("%w: %s", ErrCompressedExists, dst)
+	}
+
+	src, info, err := openRegularAt(dirfd, name, fullPath, unix.O_RDONLY)
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() { _ = src.Close() }()
+
+	tmp, tmpName, err := createTempAt(dirfd, name, fullPath)
+	if err != nil {
+		return "", 0, err
+	}
+	linked := false
+	defer func() {
+		_ = tmp.Close()
+		if !linked {
+			_ = unix.Unlinkat(dirfd, tmpName, 0)
+		}
+	}()
+
+	n, err := compressInto(tmp, src, info, format, level)
+	if err != nil {
+		return "", 0, err
+	}
+	compressed, err := tmp.Stat()
+	if err != nil {
+		return "", 0, err
+	}
+	times := []unix.Timespec{unix.NsecToTimespec(accessTime(info).UnixNano()), unix.NsecToTimespec(info.ModTime().UnixNano())}
+	if err := unix.UtimesNanoAt(dirfd, tmpName, times, unix.AT_SYMLINK_NOFOLLOW); err != nil {
+		return "", 0, &os.PathError{Op: "utimensat", Path: tmp.Name(), Err: err}
+	}
+
+	// A writer that appended after the copy was read would lose data with the original
+	unchanged := func() bool {
+		return unix.Fstatat(dirfd, name, &st, unix.AT_SYMLINK_NOFOLLOW) == nil && sameStat(info, &st) &&
+			st.Size == n && time.Unix(st.Mtim.Unix()).Equal(info.ModTime())
+	}
+	if !unchanged() {
+		return "", 0, fmt.Errorf("%w: %s", ErrChangedWhileCompressing, fullPath)
+	}
+
+	// linkat fails rather than replace a file created under the name meanwhile
+	if err := unix.Linkat(dirfd, tmpName, dirfd, dstName, 0); err != nil {
+		if errors.Is(err, unix.EEXIST) {
+			return "", 0, fmt.Errorf("%w: %s", ErrCompressedExists, dst)
+		}
+		return "", 0, &os.LinkError{Op: "linkat", Old: tmp.Name(), New: dst, Err: err}
+	}
+	linked = true
+	_ = unix.Unlinkat(dirfd, tmpName, 0)
+
+	// Check again right before the unlink, on the same directory, so nothing written since the link is lost
+	if !unchanged() {
+		_ = unix.Unlinkat(dirfd, dstName, 0)
+		return "", 0, fmt.Errorf("%w: %s", ErrChangedWhileCompressing, fullPath)
+	}
+	if err := unix.Unlinkat(dirfd, name, 0); err != nil {
+		return dst, 0, fmt.Errorf("%w: %w", ErrOriginalNotRemoved, &os.PathError{Op: "unlinkat", Path: fullPath, Err: err})
+	}
+	return dst, compressed.Size(), nil
+}
+
+// openRegularAt opens name under dirfd with flags, refusing symlinks and
+// anything but a regular file, and returns it with its fstat
+func openRegularAt(dirfd int, name, fullPath string, flags int) (*os.File, os.FileInfo, error) {
+	// O_NONBLOCK keeps a FIFO swapped in for the file from blocking the open
+	fd, err := unix.Openat(dirfd, name, flags|unix.O_NOFOLLOW|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	if errors.Is(err, unix.ELOOP) {
+		return nil, nil, fmt.Errorf("%s is not a regular file", fullPath)
+	}
+	if err != nil {
+		return nil, nil, &os.PathError{Op: "openat", Path: fullPath, Err: err}
+	}
+	f := os.NewFile(uintptr(fd), fullPath)
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, nil, err
+	}
+	if !info.Mode().IsRegular() {
+		_ = f.Close()
+		return nil, nil, fmt.Errorf("%s is not a regular file", fullPath)
+	}
+	return f, info, nil
+}
+
+// createTempAt creates a new, empty temp file for name under dirfd
+func createTempAt(dirfd int, name, fullPath string) (*os.File, string, error) {
+	for range 100 {
+		tmpName := "." + name + "." + strconv.FormatUint(rand.Uint64(), 36) + ".tmp"
+		fd, err := unix.Openat(dirfd, tmpName, unix.O_RDWR|unix.O_CREAT|unix.O_EXCL|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0600)
+		if errors.Is(err, unix.EEXIST) {
+			continue
+		}
+		tmpPath := filepath.Join(filepath.Dir(fullPath), tmpName)
+		if err != nil {
+			return nil, "", &os.PathError{Op: "openat", Path: tmpPath, Err: err}
+		}
+		return os.NewFile(uintptr(fd), tmpPath), tmpName, nil
+	}
+	return nil, "", &os.PathError{Op: "openat", Path: fullPath, Err: unix.EEXIST}
+}
+
+// sameStat reports whether st describes the file info was taken from
+func sameStat(info os.FileInfo, st *unix.Stat_t) bool {
+	sys, ok := info.Sys().(*syscall.Stat_t)
+	return ok && uint64(sys.Dev) == uint64(st.Dev) && sys.Ino == st.Ino
+}
+
+// preserveOwner gives f the owner and group recorded in info
+func preserveOwner(f *os.File, info os.FileInfo) error {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+	return f.Chown(int(st.Uid), int(st.Gid))
+}
+
+// accessTime returns the access time recorded in info
+func accessTime(info os.FileInfo) time.Time {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return info.ModTime()
+	}
+	return time.Unix(int64(st.Atim.Sec), int64(st.Atim.Nsec))
+}
@@ -0,0 +1,108 @@
+//go:build !linux
+
+package fsops
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CompressFile replaces the regular file at path with a copy compressed in
+// format, named path plus CompressedExt(format), and returns that name and the
+// compressed size. The copy is written to a temp file in the same directory,
+// synced, read back and checked against the original, given the original's
+// mode and times, and only then linked into place, so the compressed name
+// never holds a partial file. The original is removed right after it is
+// checked once more to be the file that was read, unchanged. Without openat on
+// this platform every step goes by path.
+func CompressFile(path, format string, level int) (string, int64, error) {
+	ext, err := CompressedExt(format)
+	if err != nil {
+		return "", 0, err
+	}
+	info, err := os.Lstat(path)
+	if err != nil {
+		return "", 0, err
+	}
+	if !info.Mode().IsRegular() {
+		return "", 0, fmt.Errorf("%s is not a regular file", path)
+	}
+	dst := path + ext
+	if _, err := os.Lstat(dst); err == nil {
+		return "", 0, fmt.Errorf("%w: %s", ErrCompressedExists, dst)
+	}
+
+	src, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() { _ = src.Close() }()
+	if opened, err := src.Stat(); err != nil || !os.SameFile(info, opened) {
+		return "", 0, fmt.Errorf("%w: %s", ErrChangedWhileCompressing, path)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return "", 0, err
+	}
+	linked := false
+	defer func() {
+		_ = tmp.Close()
+		if !linked {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	n, err := compressInto(tmp, src, info, format, level)
+	if err != nil {
+		return "", 0, err
+	}
+	compressed, err := tmp.Stat()
+	if err != nil {
+		return "", 0, err
+	}
+	if err := os.Chtimes(tmp.Name(), accessTime(info), info.ModTime()); err != nil {
+		return "", 0, err
+	}
+
+	// A writer that appended after the copy was read would lose data with the original
+	unchanged := func() bool {
+		now, err := os.Lstat(path)
+		return err == nil && os.SameFile(info, now) && now.Size() == n && now.ModTime().Equal(info.ModTime())
+	}
+	if !unchanged() {
+		return "", 0, fmt.Errorf("%w: %s", ErrChangedWhileCompressing, path)
+	}
+
+	// link fails rather than replace a file created under the name meanwhile
+	if err := os.Link(tmp.Name(), dst); err != nil {
+		if os.IsExist(err) {
+			return "", 0, fmt.Errorf("%w: %s", ErrCompressedExists, dst)
+		}
+		return "", 0, err
+	}
+	linked = true
+	_ = os.Remove(tmp.Name())
+
+	// Check again right before the removal, so nothing written since the link is lost
+	if !unchanged() {
+		_ = os.Remove(dst)
+		return "", 0, fmt.Errorf("%w: %s", ErrChangedWhileCompressing, path)
+	}
+	if err := os.Remove(path); err != nil {
+		return dst, 0, fmt.Errorf("%w: %w", ErrOriginalNotRemoved, err)
+	}
+	return dst, compressed.Size(), nil
+}
+
+// preserveOwner is not supported on this platform; the copy keeps the daemon's owner
+func preserveOwner(f *os.File, info os.FileInfo) error {
+	return nil
+}
+
+// accessTime is not available on this platform; the modification time stands in
+func accessTime(info os.FileInfo) time.Time {
+	return info.ModTime()
+}
@@ -0,0 +1,139 @@
+package fsops
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestCompressFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	content := []byte(strings.Repeat("GET /healthz 200\n", 1000))
+	if err := os.WriteFile(path, content, 0640); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Now().Add(-72 * time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	dst, size, err := CompressFile(path, FormatGzip, 6)
+	if err != nil {
+		t.Fatalf("CompressFile: %v", err)
+	}
+	if dst != path+GzipExt {
+		t.Errorf("dst = %s, want %s", dst, path+GzipExt)
+	}
+	info, err := os.Stat(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != size || size >= int64(len(content)) {
+		t.Errorf("compressed size %d (reported %d), want below %d", info.Size(), size, len(content))
+	}
+	if info.Mode().Perm() != 0640 || !info.ModTime().Equal(mtime) {
+		t.Errorf("mode %v mtime %v, want 0640 and %v", info.Mode().Perm(), info.ModTime(), mtime)
+	}
+	if _, err := os.Lstat(path); !os.IsNotExist(err) {
+		t.Errorf("original should be removed once its copy is linked: %v", err)
+	}
+
+	f, err := os.Open(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(zr)
+	if err != nil || !bytes.Equal(got, content) {
+		t.Errorf("decompressed %d bytes (err %v), want the original %d", len(got), err, len(content))
+	}
+
+	// The compressed name is taken now; a second run must not replace it
+	if err := os.WriteFile(path, content, 0640); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := CompressFile(path, FormatGzip, 6); !errors.Is(err, ErrCompressedExists) {
+		t.Errorf("second CompressFile = %v, want ErrCompressedExists", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("directory holds %d entries, want the original and its copy without temp files", len(entries))
+	}
+}
+
+func TestCompressFileRejectsNonRegular(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Symlink("/etc/passwd", filepath.Join(dir, "link")); err != nil {
+		t.Skip("symlinks unsupported")
+	}
+	if _, _, err := CompressFile(filepath.Join(dir, "link"), FormatGzip, 6); err == nil {
+		t.Error("CompressFile followed a symlink")
+	}
+	if _, err := os.Lstat(filepath.Join(dir, "link.gz")); !os.IsNotExist(err) {
+		t.Error("CompressFile wrote a copy of a symlink target")
+	}
+}
+
+func TestCompressFileZstd(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	content := []byte(strings.Repeat("worker 7 finished job\n", 500))
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dst, size, err := CompressFile(path, FormatZstd, 3)
+	if err != nil {
+		t.Fatalf("CompressFile: %v", err)
+	}
+	if dst != path+ZstdExt || size >= int64(len(content)) {
+		t.Errorf("dst = %s size %d, want %s below %d bytes", dst, size, path+ZstdExt, len(content))
+	}
+	if _, err := os.Lstat(path); !os.IsNotExist(err) {
+		t.Errorf("original should be removed once its copy is linked: %v", err)
+	}
+
+	f, err := os.Open(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := zstd.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	got, err := io.ReadAll(zr)
+	if err != nil || !bytes.Equal(got, content) {
+		t.Errorf("decompressed %d bytes (err %v), want the original %d", len(got), err, len(content))
+	}
+}
+
+func TestCompressFileUnknownFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := CompressFile(path, "brotli", 6); err == nil {
+		t.Error("CompressFile accepted an unknown format")
+	}
+	if _, err := os.Lstat(path); err != nil {
+		t.Errorf("original: %v, want it kept", err)
+	}
+}
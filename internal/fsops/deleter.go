@@ -6,3 +6,15 @@ type Deleter interface {
 	Remove(path string) error
 	RemoveAll(path string) error
 }
+
+// Compressor is implemented by deleters that can replace a file with a
+// compressed copy under the same confinement as their deletes; see CompressFile
+type Compressor interface {
+	Compress(path, format string, level int) (dst string, size int64, err error)
+}
+
+// Truncator is implemented by deleters that can truncate a file in place
+// under the same confinement as their deletes; see TruncateFile
+type Truncator interface {
+	Truncate(path string, id FileID, keepTail int64) (before, after int64, err error)
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package fsops
+
+import "os"
+
+// matchesFileID cannot compare inodes on this platform; scans here never
+// record a valid id, so every file matches
+func matchesFileID(info os.FileInfo, id FileID) bool {
+	return true
+}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package fsops
+
+import (
+	"os"
+	"syscall"
+)
+
+// matchesFileID reports whether info describes the inode id names; an
+// invalid id matches any file
+func matchesFileID(info os.FileInfo, id FileID) bool {
+	if !id.Valid {
+		return true
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	return ok && uint64(st.Dev) == id.Dev && uint64(st.Ino) == id.Ino
+}
@@ -12,3 +12,11 @@ func (OSDeleter) Remove(path string) error {
 func (OSDeleter) RemoveAll(path string) error {
 	return os.RemoveAll(path)
 }
+
+func (OSDeleter) Compress(path, format string, level int) (string, int64, error) {
+	return CompressFile(path, format, level)
+}
+
+func (OSDeleter) Truncate(path string, id FileID, keepTail int64) (before, after int64, err error) {
+	return TruncateFile(path, id, keepTail)
+}
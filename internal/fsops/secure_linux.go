@@ -0,0 +1,259 @@
+//go:build linux
+
+package fsops
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/sys/unix"
+
+	"storage-sage/internal/safety"
+)
+
+// SecureDeleter removes files relative to directory file descriptors so a
+// parent directory swapped for a symlink after validation cannot redirect
+// the delete. Each rule root is opened once; every path below it is reached
+// with openat(O_NOFOLLOW|O_DIRECTORY) and removed with unlinkat.
+type SecureDeleter struct {
+	roots []secureRoot // sorted longest path first so nested roots win
+}
+
+type secureRoot struct {
+	path string
+	fd   int   // -1 when the root could not be opened
+	err  error // why the root could not be opened
+}
+
+// NewSecureDeleter opens each root. Roots are trusted configuration, so a
+// symlinked root is followed once here; nothing below it is followed. A root
+// that cannot be opened is kept unopened rather than failing the others:
+// every operation below it fails with safety.ErrRootUnavailable, and
+// Unavailable reports it.
+func NewSecureDeleter(roots []string) (*SecureDeleter, error) {
+	d := &SecureDeleter{}
+	seen := make(map[string]bool)
+	for _, r := range roots {
+		clean := filepath.Clean(r)
+		if seen[clean] {
+			continue
+		}
+		seen[clean] = true
+
+		fd, err := unix.Open(clean, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+		if err != nil {
+			if errors.Is(err, unix.ENOENT) {
+				continue // nothing to delete under a missing root
+			}
+			d.roots = append(d.roots, secureRoot{path: clean, fd: -1, err: &os.PathError{Op: "open", Path: clean, Err: err}})
+			continue
+		}
+		d.roots = append(d.roots, secureRoot{path: clean, fd: fd})
+	}
+	sort.Slice(d.roots, func(i, j int) bool {
+		return len(d.roots[i].path) > len(d.roots[j].path)
+	})
+	return d, nil
+}
+
+// Unavailable returns why each root that could not be opened was refused
+func (d *SecureDeleter) Unavailable() []error {
+	var errs []error
+	for _, r := range d.roots {
+		if r.err != nil {
+			errs = append(errs, r.err)
+		}
+	}
+	return errs
+}
+
+// Close releases the root descriptors
+func (d *SecureDeleter) Close() error {
+	var firstErr error
+	for _, r := range d.roots {
+		if r.fd < 0 {
+			continue
+		}
+		if err := unix.Close(r.fd); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	d.roots = nil
+	return firstErr
+}
+
+// Remove deletes a file or empty directory, like os.Remove
+func (d *SecureDeleter) Remove(path string) error {
+	parent, name, err := d.openParent(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = unix.Close(parent) }()
+
+	return removeAt(parent, name, path)
+}
+
+// RemoveAll deletes path and everything below it without following symlinks, like os.RemoveAll
+func (d *SecureDeleter) RemoveAll(path string) error {
+	parent, name, err := d.openParent(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer func() { _ = unix.Close(parent) }()
+
+	err = removeTreeAt(parent, name, path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Compress compresses path like CompressFile, with its parent reached from the
+// enclosing root without following symlinks
+func (d *SecureDeleter) Compress(path, format string, level int) (string, int64, error) {
+	parent, name, err := d.openParent(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() { _ = unix.Close(parent) }()
+
+	return compressAt(parent, name, path, format, level)
+}
+
+// Truncate truncates path like TruncateFile, with its parent reached from the
+// enclosing root without following symlinks
+func (d *SecureDeleter) Truncate(path string, id FileID, keepTail int64) (before, after int64, err error) {
+	parent, name, err := d.openParent(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() { _ = unix.Close(parent) }()
+
+	return truncateAt(parent, name, path, id, keepTail)
+}
+
+// openParent returns an fd for path's parent directory, reached from the
+// enclosing root without following symlinks, and the final path element.
+func (d *SecureDeleter) openParent(path string) (int, string, error) {
+	clean := filepath.Clean(path)
+	root, ok := d.rootFor(clean)
+	if !ok {
+		return -1, "", &os.PathError{Op: "remove", Path: path, Err: safety.ErrOutsideAllowed}
+	}
+	if clean == root.path {
+		return -1, "", &os.PathError{Op: "remove", Path: path, Err: safety.ErrProtectedPath}
+	}
+	if root.fd < 0 {
+		return -1, "", &os.PathError{Op: "remove", Path: path, Err: fmt.Errorf("%w: %v", safety.ErrRootUnavailable, root.err)}
+	}
+
+	rel := strings.TrimPrefix(clean, root.path+string(os.PathSeparator))
+	if root.path == string(os.PathSeparator) {
+		rel = strings.TrimPrefix(clean, root.path)
+	}
+	parts := strings.Split(rel, string(os.PathSeparator))
+
+	fd, err := unix.Dup(root.fd)
+	if err != nil {
+		return -1, "", &os.PathError{Op: "dup", Path: root.path, Err: err}
+	}
+	current := root.path
+	for _, part := range parts[:len(parts)-1] {
+		current = filepath.Join(current, part)
+		next, err := openDirAt(fd, part, current)
+		_ = unix.Close(fd)
+		if err != nil {
+			return -1, "", err
+		}
+		fd = next
+	}
+	return fd, parts[len(parts)-1], nil
+}
+
+// rootFor returns the deepest root containing path
+func (d *SecureDeleter) rootFor(path string) (secureRoot, bool) {
+	for _, r := range d.roots {
+		if path == r.path || r.path == string(os.PathSeparator) ||
+			strings.HasPrefix(path, r.path+string(os.PathSeparator)) {
+			return r, true
+		}
+	}
+	return secureRoot{}, false
+}
+
+// openDirAt opens name under dirfd as a directory, refusing symlinks
+func openDirAt(dirfd int, name, fullPath string) (int, error) {
+	fd, err := unix.Openat(dirfd, name, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+	if err == nil {
+		return fd, nil
+	}
+	if errors.Is(err, unix.ELOOP) || errors.Is(err, unix.ENOTDIR) {
+		var st unix.Stat_t
+		if unix.Fstatat(dirfd, name, &st, unix.AT_SYMLINK_NOFOLLOW) == nil && st.Mode&unix.S_IFMT == unix.S_IFLNK {
+			return -1, &os.PathError{Op: "openat", Path: fullPath, Err: safety.ErrSymlinkAncestor}
+		}
+	}
+	return -1, &os.PathError{Op: "openat", Path: fullPath, Err: err}
+}
+
+// removeAt unlinks name under dirfd, falling back to rmdir for directories
+func removeAt(dirfd int, name, fullPath string) error {
+	err := unix.Unlinkat(dirfd, name, 0)
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, unix.EISDIR) || errors.Is(err, unix.EPERM) {
+		if rmErr := unix.Unlinkat(dirfd, name, unix.AT_REMOVEDIR); rmErr == nil {
+			return nil
+		} else if !errors.Is(rmErr, unix.ENOTDIR) {
+			err = rmErr
+		}
+	}
+	return &os.PathError{Op: "unlinkat", Path: fullPath, Err: err}
+}
+
+// removeTreeAt removes name under dirfd recursively. Directories are entered
+// with O_NOFOLLOW, so a symlink is removed as a link and never traversed.
+func removeTreeAt(dirfd int, name, fullPath string) error {
+	err := unix.Unlinkat(dirfd, name, 0)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, unix.EISDIR) && !errors.Is(err, unix.EPERM) {
+		return &os.PathError{Op: "unlinkat", Path: fullPath, Err: err}
+	}
+
+	fd, err := unix.Openat(dirfd, name, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return &os.PathError{Op: "openat", Path: fullPath, Err: err}
+	}
+	dir := os.NewFile(uintptr(fd), fullPath)
+	defer func() { _ = dir.Close() }()
+
+	// Read the whole listing before unlinking so removals don't disturb the directory offset
+	names, err := dir.Readdirnames(-1)
+	if err != nil {
+		return err
+	}
+
+	var firstErr error
+	for _, child := range names {
+		if err := removeTreeAt(fd, child, filepath.Join(fullPath, child)); err != nil && !os.IsNotExist(err) && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if err := unix.Unlinkat(dirfd, name, unix.AT_REMOVEDIR); err != nil {
+		if firstErr == nil {
+			firstErr = &os.PathError{Op: "unlinkat", Path: fullPath, Err: err}
+		}
+	}
+	return firstErr
+}
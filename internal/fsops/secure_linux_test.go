@@ -0,0 +1,280 @@
+//go:build linux
+
+package fsops
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"storage-sage/internal/safety"
+)
+
+func TestSecureDeleterRemove(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+
+	file := filepath.Join(root, "a", "b", "old.log")
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	victim := filepath.Join(outside, "old.log")
+	if err := os.WriteFile(victim, []byte("keep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := NewSecureDeleter([]string{root})
+	if err != nil {
+		t.Fatalf("NewSecureDeleter: %v", err)
+	}
+	defer func() { _ = d.Close() }()
+
+	if err := d.Remove(file); err != nil {
+		t.Fatalf("Remove(%s): %v", file, err)
+	}
+	if _, err := os.Lstat(file); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be removed", file)
+	}
+
+	// Swap a parent for a symlink pointing outside the root
+	if err := os.WriteFile(file, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(filepath.Join(root, "a", "b")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "a", "b")); err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.Remove(file)
+	if !errors.Is(err, safety.ErrSymlinkAncestor) {
+		t.Fatalf("Remove through symlinked parent = %v, want ErrSymlinkAncestor", err)
+	}
+	if _, err := os.Stat(victim); err != nil {
+		t.Fatalf("file outside root was removed: %v", err)
+	}
+
+	if err := d.Remove(victim); !errors.Is(err, safety.ErrOutsideAllowed) {
+		t.Fatalf("Remove outside root = %v, want ErrOutsideAllowed", err)
+	}
+	if err := d.Remove(filepath.Join(root, "missing")); !os.IsNotExist(err) {
+		t.Fatalf("Remove missing file = %v, want not-exist", err)
+	}
+}
+
+func TestSecureDeleterRemoveAll(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+
+	tree := filepath.Join(root, "tree")
+	for _, p := range []string{"x/y/z.txt", "x/w.txt", "top.txt"} {
+		full := filepath.Join(tree, p)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	victim := filepath.Join(outside, "keep.txt")
+	if err := os.WriteFile(victim, []byte("keep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// A symlink inside the tree must be removed as a link, not followed
+	if err := os.Symlink(outside, filepath.Join(tree, "x", "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := NewSecureDeleter([]string{root})
+	if err != nil {
+		t.Fatalf("NewSecureDeleter: %v", err)
+	}
+	defer func() { _ = d.Close() }()
+
+	if err := d.RemoveAll(tree); err != nil {
+		t.Fatalf("RemoveAll: %v", err)
+	}
+	if _, err := os.Lstat(tree); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be removed", tree)
+	}
+	if _, err := os.Stat(victim); err != nil {
+		t.Fatalf("symlink target outside root was removed: %v", err)
+	}
+	if err := d.RemoveAll(tree); err != nil {
+		t.Fatalf("RemoveAll on missing path should succeed, got %v", err)
+	}
+	if err := d.RemoveAll(root); !errors.Is(err, safety.ErrProtectedPath) {
+		t.Fatalf("RemoveAll(root) = %v, want ErrProtectedPath", err)
+	}
+}
+
+func TestSecureDeleterCompress(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+
+	file := filepath.Join(root, "a", "app.log")
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, []byte("GET /healthz 200\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	victim := filepath.Join(outside, "app.log")
+	if err := os.WriteFile(victim, []byte("keep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := NewSecureDeleter([]string{root})
+	if err != nil {
+		t.Fatalf("NewSecureDeleter: %v", err)
+	}
+	defer func() { _ = d.Close() }()
+
+	dst, _, err := d.Compress(file, FormatGzip, 6)
+	if err != nil || dst != file+GzipExt {
+		t.Fatalf("Compress = %s, %v; want %s", dst, err, file+GzipExt)
+	}
+	if err := os.Remove(dst); err != nil {
+		t.Fatal(err)
+	}
+
+	// Swap the parent for a symlink pointing outside the root
+	if err := os.RemoveAll(filepath.Join(root, "a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "a")); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := d.Compress(file, FormatGzip, 6); !errors.Is(err, safety.ErrSymlinkAncestor) {
+		t.Fatalf("Compress through symlinked parent = %v, want ErrSymlinkAncestor", err)
+	}
+	entries, err := os.ReadDir(outside)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("directory outside the root holds %d entries, want only the original", len(entries))
+	}
+	if _, _, err := d.Compress(victim, FormatGzip, 6); !errors.Is(err, safety.ErrOutsideAllowed) {
+		t.Fatalf("Compress outside root = %v, want ErrOutsideAllowed", err)
+	}
+}
+
+func TestSecureDeleterTruncate(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+
+	file := filepath.Join(root, "a", "app.log")
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, []byte("0123456789"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	victim := filepath.Join(outside, "app.log")
+	if err := os.WriteFile(victim, []byte("0123456789"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := NewSecureDeleter([]string{root})
+	if err != nil {
+		t.Fatalf("NewSecureDeleter: %v", err)
+	}
+	defer func() { _ = d.Close() }()
+
+	info, err := os.Lstat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := info.Sys().(*syscall.Stat_t)
+	id := FileID{Dev: uint64(st.Dev), Ino: st.Ino, Valid: true}
+	before, after, err := d.Truncate(file, id, 4)
+	if err != nil || before != 10 || after != 4 {
+		t.Fatalf("Truncate = %d, %d, %v; want 10, 4", before, after, err)
+	}
+	if data, _ := os.ReadFile(file); string(data) != "6789" {
+		t.Errorf("truncated file holds %q, want the tail %q", data, "6789")
+	}
+
+	// A regular file renamed over the scanned one is another inode, and left alone
+	other := filepath.Join(root, "a", "other.log")
+	if err := os.WriteFile(other, []byte("0123456789"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(other, file); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := d.Truncate(file, id, 4); !errors.Is(err, ErrChangedWhileTruncating) {
+		t.Fatalf("Truncate of a renamed-in file = %v, want ErrChangedWhileTruncating", err)
+	}
+	if data, _ := os.ReadFile(file); string(data) != "0123456789" {
+		t.Errorf("renamed-in file holds %q, want it untouched", data)
+	}
+
+	// A symlink in place of the file is reported as a change, not followed
+	link := filepath.Join(root, "a", "link.log")
+	if err := os.Symlink(victim, link); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := d.Truncate(link, FileID{}, 4); !errors.Is(err, ErrChangedWhileTruncating) {
+		t.Fatalf("Truncate of a symlink = %v, want ErrChangedWhileTruncating", err)
+	}
+
+	// Swap the parent for a symlink pointing outside the root
+	if err := os.RemoveAll(filepath.Join(root, "a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "a")); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := d.Truncate(file, FileID{}, 4); !errors.Is(err, safety.ErrSymlinkAncestor) {
+		t.Fatalf("Truncate through symlinked parent = %v, want ErrSymlinkAncestor", err)
+	}
+	if _, _, err := d.Truncate(victim, FileID{}, 4); !errors.Is(err, safety.ErrOutsideAllowed) {
+		t.Fatalf("Truncate outside root = %v, want ErrOutsideAllowed", err)
+	}
+	if data, _ := os.ReadFile(victim); string(data) != "0123456789" {
+		t.Errorf("file outside the root = %q, want it untouched", data)
+	}
+}
+
+// TestSecureDeleterUnavailableRoot verifies a root that cannot be opened
+// refuses the deletes below it without taking the other roots down
+func TestSecureDeleterUnavailableRoot(t *testing.T) {
+	root := t.TempDir()
+	notDir := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(notDir, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// The nested root resolves to a file, so opening it fails with ENOTDIR
+	nested := filepath.Join(root, "nested")
+	if err := os.Symlink(notDir, nested); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(root, "old.log")
+	if err := os.WriteFile(file, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := NewSecureDeleter([]string{root, nested})
+	if err != nil {
+		t.Fatalf("NewSecureDeleter: %v", err)
+	}
+	defer func() { _ = d.Close() }()
+
+	if errs := d.Unavailable(); len(errs) != 1 {
+		t.Fatalf("Unavailable = %v, want the nested root", errs)
+	}
+	if err := d.Remove(filepath.Join(nested, "x")); !errors.Is(err, safety.ErrRootUnavailable) {
+		t.Errorf("Remove below an unavailable root = %v, want ErrRootUnavailable", err)
+	}
+	if err := d.Remove(file); err != nil {
+		t.Errorf("Remove below an open root: %v", err)
+	}
+}
@@ -0,0 +1,27 @@
+//go:build !linux
+
+package fsops
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SecureDeleter requires openat/unlinkat semantics and is only available on Linux
+type SecureDeleter struct{}
+
+// NewSecureDeleter reports that fd-relative deletion is unsupported here
+func NewSecureDeleter(roots []string) (*SecureDeleter, error) {
+	return nil, fmt.Errorf("%w: fd-relative deletion is only supported on Linux", errors.ErrUnsupported)
+}
+
+func (d *SecureDeleter) Close() error                { return nil }
+func (d *SecureDeleter) Unavailable() []error        { return nil }
+func (d *SecureDeleter) Remove(path string) error    { return errors.ErrUnsupported }
+func (d *SecureDeleter) RemoveAll(path string) error { return errors.ErrUnsupported }
+func (d *SecureDeleter) Compress(path, format string, level int) (string, int64, error) {
+	return "", 0, errors.ErrUnsupported
+}
+func (d *SecureDeleter) Truncate(path string, id FileID, keepTail int64) (before, after int64, err error) {
+	return 0, 0, errors.ErrUnsupported
+}
@@ -0,0 +1,68 @@
+package fsops
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ErrChangedWhileTruncating is returned when the file was replaced between the check and the truncation
+var ErrChangedWhileTruncating = errors.New("file changed before it was truncated")
+
+// FileID is the device and inode a truncation expects to find at its path,
+// as recorded by the scan; the zero value expects nothing
+type FileID struct {
+	Dev   uint64
+	Ino   uint64
+	Valid bool
+}
+
+// truncateChunk is how much of the tail a truncation moves at a time
+const truncateChunk = 1 << 20
+
+// truncateOpened cuts f, opened read-write, down to its last keepTail bytes
+// and returns its size before and after
+func truncateOpened(f *os.File, keepTail int64) (before, after int64, err error) {
+	opened, err := f.Stat()
+	if err != nil {
+		return 0, 0, err
+	}
+	before = opened.Size()
+	if before <= keepTail {
+		return before, before, nil
+	}
+	if keepTail > 0 {
+		// The tail starts past offset 0, so copying it forward never reads what it overwrote
+		if err := moveTail(f, before-keepTail, keepTail); err != nil {
+			return before, before, err
+		}
+	}
+	if err := f.Truncate(keepTail); err != nil {
+		return before, before, err
+	}
+	if err := f.Sync(); err != nil {
+		return before, keepTail, err
+	}
+	return before, keepTail, nil
+}
+
+// moveTail copies the n bytes of f at offset from to the start of f
+func moveTail(f *os.File, from, n int64) error {
+	buf := make([]byte, truncateChunk)
+	for done := int64(0); done < n; {
+		chunk := buf
+		if rest := n - done; rest < int64(len(chunk)) {
+			chunk = chunk[:rest]
+		}
+		read, err := f.ReadAt(chunk, from+done)
+		if err != nil && !(errors.Is(err, io.EOF) && read == len(chunk)) {
+			return fmt.Errorf("read tail: %w", err)
+		}
+		if _, err := f.WriteAt(chunk, done); err != nil {
+			return fmt.Errorf("write tail: %w", err)
+		}
+		done += int64(read)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+//go:build linux
+
+package fsops
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/unix"
+)
+
+// TruncateFile cuts the regular file at path, which must be the inode id
+// names when id is valid, down to its last keepTail bytes in place and
+// returns its size before and after. The tail is copied to the
+// start of the file and the file then truncated, so processes holding it open
+// keep writing to the same file and the space is freed at once. Bytes
+// appended while the tail is copied are lost; a writer without O_APPEND keeps
+// its offset and leaves a sparse gap before its next write. The parent
+// directory is resolved by path; SecureDeleter.Truncate reaches it from a
+// rule root without following symlinks.
+func TruncateFile(path string, id FileID, keepTail int64) (before, after int64, err error) {
+	dirfd, err := unix.Open(filepath.Dir(path), unix.O_RDONLY|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return 0, 0, &os.PathError{Op: "open", Path: filepath.Dir(path), Err: err}
+	}
+	defer func() { _ = unix.Close(dirfd) }()
+	return truncateAt(dirfd, filepath.Base(path), path, id, keepTail)
+}
+
+// truncateAt truncates name under dirfd like TruncateFile, opening it
+// relative to dirfd without following a symlink and checking the opened fd
+// is the scanned inode
+func truncateAt(dirfd int, name, fullPath string, id FileID, keepTail int64) (before, after int64, err error) {
+	f, info, err := openRegularAt(dirfd, name, fullPath, unix.O_RDWR)
+	var pathErr *os.PathError
+	if err != nil && !errors.As(err, &pathErr) {
+		// The scan saw a regular file, so it was replaced since
+		return 0, 0, fmt.Errorf("%w: %s", ErrChangedWhileTruncating, fullPath)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() { _ = f.Close() }()
+	// A file renamed over the scanned one is as regular, but not what the rule selected
+	if !matchesFileID(info, id) {
+		return 0, 0, fmt.Errorf("%w: %s", ErrChangedWhileTruncating, fullPath)
+	}
+	return truncateOpened(f, keepTail)
+}
@@ -0,0 +1,36 @@
+//go:build !linux
+
+package fsops
+
+import (
+	"fmt"
+	"os"
+)
+
+// TruncateFile cuts the regular file at path, which must be the inode id
+// names when id is valid, down to its last keepTail bytes in place and
+// returns its size before and after. The tail is copied to the
+// start of the file and the file then truncated, so processes holding it open
+// keep writing to the same file and the space is freed at once. Bytes
+// appended while the tail is copied are lost; a writer without O_APPEND keeps
+// its offset and leaves a sparse gap before its next write. Without openat on
+// this platform the file is opened by path.
+func TruncateFile(path string, id FileID, keepTail int64) (before, after int64, err error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !info.Mode().IsRegular() {
+		return 0, 0, fmt.Errorf("%s is not a regular file", path)
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() { _ = f.Close() }()
+	if opened, err := f.Stat(); err != nil || !os.SameFile(info, opened) || !matchesFileID(opened, id) {
+		return 0, 0, fmt.Errorf("%w: %s", ErrChangedWhileTruncating, path)
+	}
+	return truncateOpened(f, keepTail)
+}
@@ -0,0 +1,80 @@
+package fsops
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTruncateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	content := []byte(strings.Repeat("0123456789", truncateChunk/5)) // two chunks and then some to move
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A writer holding the file open keeps appending to the same file
+	w, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	keep := int64(truncateChunk + 7)
+	before, after, err := TruncateFile(path, FileID{}, keep)
+	if err != nil {
+		t.Fatalf("TruncateFile: %v", err)
+	}
+	if before != int64(len(content)) || after != keep {
+		t.Errorf("sizes %d -> %d, want %d -> %d", before, after, len(content), keep)
+	}
+	if _, err := w.WriteString("next line\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append(append([]byte{}, content[int64(len(content))-keep:]...), "next line\n"...)
+	if !bytes.Equal(got, want) {
+		t.Errorf("file holds %d bytes, want the last %d followed by the new line", len(got), keep)
+	}
+
+	// A file within the tail is left alone
+	if before, after, err := TruncateFile(path, FileID{}, keep*2); err != nil || before != after {
+		t.Errorf("TruncateFile of a small file = %d -> %d, %v; want it unchanged", before, after, err)
+	}
+}
+
+func TestTruncateFileEmpties(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("log line\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, after, err := TruncateFile(path, FileID{}, 0); err != nil || after != 0 {
+		t.Fatalf("TruncateFile = %d, %v; want 0", after, err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() != 0 {
+		t.Errorf("file not emptied: %v", err)
+	}
+}
+
+func TestTruncateFileRejectsNonRegular(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	if err := os.WriteFile(target, []byte("keep me"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(target, filepath.Join(dir, "link")); err != nil {
+		t.Skip("symlinks unsupported")
+	}
+	if _, _, err := TruncateFile(filepath.Join(dir, "link"), FileID{}, 0); err == nil {
+		t.Error("TruncateFile followed a symlink")
+	}
+	if data, _ := os.ReadFile(target); string(data) != "keep me" {
+		t.Error("symlink target was truncated")
+	}
+}
@@ -0,0 +1,220 @@
+package index
+
+import (
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DirEntry summarizes the direct, non-directory children of one directory as
+// they looked the last time the directory listing was read.
+type DirEntry struct {
+	ModTime   int64    // Directory mtime (UnixNano) when the listing was read
+	Files     int64    // Number of non-directory children
+	Bytes     int64    // Total size of non-directory children
+	OldestMod int64    // Oldest child mtime (UnixNano), 0 when Files == 0
+	Subdirs   []string // Names of child directories
+}
+
+// CannotHoldOlderThan reports whether none of the directory's files were
+// modified at or before cutoff, i.e. no file here can meet an age threshold
+// that resolves to cutoff.
+func (e *DirEntry) CannotHoldOlderThan(cutoff time.Time) bool {
+	if e.Files == 0 {
+		return true
+	}
+	return e.OldestMod > cutoff.UnixNano()
+}
+
+// snapshot is the on-disk representation of an Index
+type snapshot struct {
+	Version  int
+	Dirs     map[string]*DirEntry
+	FullScan map[string]time.Time // Last full walk per rule root
+}
+
+const snapshotVersion = 1
+
+// Index is a persistent record of directory mtimes and per-directory file
+// summaries. The scanner uses it to avoid re-reading directories whose
+// listing has not changed and which cannot contain a candidate yet.
+//
+// Directory mtimes only change when entries are added, removed or renamed,
+// so in-place rewrites and backdated mtimes go unnoticed until the next full
+// rescan. Full rescans are therefore forced on a configurable cadence.
+type Index struct {
+	mu       sync.Mutex
+	path     string
+	dirs     map[string]*DirEntry
+	fullScan map[string]time.Time
+	dirty    bool
+}
+
+// Open loads the index stored at path. A missing file yields an empty index;
+// an unreadable or incompatible file is discarded so the next walk rebuilds it.
+func Open(path string) (*Index, error) {
+	if path == "" {
+		return nil, fmt.Errorf("index path is empty")
+	}
+
+	ix := &Index{
+		path:     path,
+		dirs:     make(map[string]*DirEntry),
+		fullScan: make(map[string]time.Time),
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ix, nil
+		}
+		return nil, fmt.Errorf("open index: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var snap snapshot
+	if err := gob.NewDecoder(f).Decode(&snap); err != nil || snap.Version != snapshotVersion {
+		// Corrupt or outdated index - start over rather than fail the cycle
+		ix.dirty = true
+		return ix, nil
+	}
+	if snap.Dirs != nil {
+		ix.dirs = snap.Dirs
+	}
+	if snap.FullScan != nil {
+		ix.fullScan = snap.FullScan
+	}
+	return ix, nil
+}
+
+// Path returns the file the index is persisted to
+func (ix *Index) Path() string {
+	return ix.path
+}
+
+// Lookup returns the recorded summary for dir
+func (ix *Index) Lookup(dir string) (*DirEntry, bool) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	e, ok := ix.dirs[dir]
+	return e, ok
+}
+
+// Put records a fresh summary for dir
+func (ix *Index) Put(dir string, e *DirEntry) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.dirs[dir] = e
+	ix.dirty = true
+}
+
+// Len returns the number of indexed directories
+func (ix *Index) Len() int {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	return len(ix.dirs)
+}
+
+// NeedsFullRescan reports whether root has not been walked in full within every.
+// A non-positive interval disables forced rescans.
+func (ix *Index) NeedsFullRescan(root string, every time.Duration, now time.Time) bool {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	last, ok := ix.fullScan[root]
+	if !ok {
+		return true
+	}
+	if every <= 0 {
+		return false
+	}
+	return now.Sub(last) >= every
+}
+
+// MarkFullRescan records that root was walked in full at now
+func (ix *Index) MarkFullRescan(root string, now time.Time) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.fullScan[root] = now
+	ix.dirty = true
+}
+
+// Retain drops entries below root (inclusive) that are not in seen, so
+// directories removed since the last walk do not linger in the index.
+func (ix *Index) Retain(root string, seen map[string]bool) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	prefix := root + string(os.PathSeparator)
+	for dir := range ix.dirs {
+		if dir != root && !strings.HasPrefix(dir, prefix) {
+			continue
+		}
+		if !seen[dir] {
+			delete(ix.dirs, dir)
+			ix.dirty = true
+		}
+	}
+}
+
+// Subtree returns the indexed directories at or below dir and the total
+// size of their files as of their last listing
+func (ix *Index) Subtree(dir string) (dirs []string, bytes int64) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	prefix := dir + string(os.PathSeparator)
+	for d, e := range ix.dirs {
+		if d == dir || strings.HasPrefix(d, prefix) {
+			dirs = append(dirs, d)
+			bytes += e.Bytes
+		}
+	}
+	return dirs, bytes
+}
+
+// Save writes the index atomically (temp file + rename). It is a no-op when
+// nothing changed since the last load or save.
+func (ix *Index) Save() error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	if !ix.dirty {
+		return nil
+	}
+
+	dir := filepath.Dir(ix.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create index directory %s: %w", dir, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(ix.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create index temp file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	snap := snapshot{
+		Version:  snapshotVersion,
+		Dirs:     ix.dirs,
+		FullScan: ix.fullScan,
+	}
+	if err := gob.NewEncoder(tmp).Encode(&snap); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("encode index: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("sync index: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close index: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), ix.path); err != nil {
+		return fmt.Errorf("replace index: %w", err)
+	}
+
+	ix.dirty = false
+	return nil
+}
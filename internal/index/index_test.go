@@ -0,0 +1,82 @@
+package index
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestSaveAndReopen verifies the index survives a round trip to disk
+func TestSaveAndReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "scan-index.gob")
+	now := time.Now()
+
+	ix, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	ix.Put("/data/a", &DirEntry{ModTime: 42, Files: 2, Bytes: 100, OldestMod: 7, Subdirs: []string{"b"}})
+	ix.MarkFullRescan("/data", now)
+	if err := ix.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	e, ok := reopened.Lookup("/data/a")
+	if !ok {
+		t.Fatal("entry missing after reopen")
+	}
+	if e.ModTime != 42 || e.Files != 2 || e.Bytes != 100 || e.OldestMod != 7 || len(e.Subdirs) != 1 {
+		t.Errorf("entry mismatch after reopen: %+v", e)
+	}
+	if reopened.NeedsFullRescan("/data", time.Hour, now.Add(time.Minute)) {
+		t.Error("full rescan should not be due one minute after the last one")
+	}
+	if !reopened.NeedsFullRescan("/data", time.Hour, now.Add(2*time.Hour)) {
+		t.Error("full rescan should be due after the interval elapsed")
+	}
+	if !reopened.NeedsFullRescan("/other", time.Hour, now) {
+		t.Error("a root that was never walked must need a full rescan")
+	}
+}
+
+// TestRetainDropsVanishedDirectories verifies stale entries under a root are pruned
+func TestRetainDropsVanishedDirectories(t *testing.T) {
+	ix, err := Open(filepath.Join(t.TempDir(), "idx.gob"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	ix.Put("/data", &DirEntry{})
+	ix.Put("/data/gone", &DirEntry{})
+	ix.Put("/data2/keep", &DirEntry{})
+
+	ix.Retain("/data", map[string]bool{"/data": true})
+
+	if _, ok := ix.Lookup("/data/gone"); ok {
+		t.Error("/data/gone should have been pruned")
+	}
+	if _, ok := ix.Lookup("/data"); !ok {
+		t.Error("/data should have been kept")
+	}
+	if _, ok := ix.Lookup("/data2/keep"); !ok {
+		t.Error("entries outside the root must not be touched")
+	}
+}
+
+// TestCannotHoldOlderThan verifies the skip predicate
+func TestCannotHoldOlderThan(t *testing.T) {
+	cutoff := time.Unix(1000, 0)
+
+	if !(&DirEntry{}).CannotHoldOlderThan(cutoff) {
+		t.Error("a directory without files can never hold an old file")
+	}
+	if !(&DirEntry{Files: 1, OldestMod: time.Unix(2000, 0).UnixNano()}).CannotHoldOlderThan(cutoff) {
+		t.Error("files newer than the cutoff cannot qualify")
+	}
+	if (&DirEntry{Files: 1, OldestMod: time.Unix(500, 0).UnixNano()}).CannotHoldOlderThan(cutoff) {
+		t.Error("a file older than the cutoff may qualify")
+	}
+}
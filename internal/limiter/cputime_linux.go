@@ -0,0 +1,45 @@
+//go:build linux
+
+package limiter
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// clockTicks is USER_HZ, the unit of utime/stime in /proc. The kernel
+// always exports 100 regardless of CONFIG_HZ.
+const clockTicks = 100
+
+// processCPUTime returns user+system CPU time consumed by this process
+func processCPUTime() (time.Duration, error) {
+	data, err := os.ReadFile("/proc/self/stat")
+	if err != nil {
+		return 0, err
+	}
+
+	// comm (field 2) may contain spaces and parentheses; fields after the
+	// last ')' are space separated starting with state (field 3)
+	end := bytes.LastIndexByte(data, ')')
+	if end < 0 {
+		return 0, fmt.Errorf("malformed /proc/self/stat")
+	}
+	fields := bytes.Fields(data[end+1:])
+	// utime and stime are fields 14 and 15, i.e. index 11 and 12 after state
+	if len(fields) < 13 {
+		return 0, fmt.Errorf("short /proc/self/stat")
+	}
+	utime, err := strconv.ParseInt(string(fields[11]), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse utime: %w", err)
+	}
+	stime, err := strconv.ParseInt(string(fields[12]), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse stime: %w", err)
+	}
+
+	return time.Duration(utime+stime) * time.Second / clockTicks, nil
+}
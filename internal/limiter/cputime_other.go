@@ -0,0 +1,17 @@
+//go:build !linux
+
+package limiter
+
+import (
+	"syscall"
+	"time"
+)
+
+// processCPUTime returns user+system CPU time consumed by this process
+func processCPUTime() (time.Duration, error) {
+	var ru syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &ru); err != nil {
+		return 0, err
+	}
+	return time.Duration(ru.Utime.Nano() + ru.Stime.Nano()), nil
+}
@@ -0,0 +1,80 @@
+package limiter
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// burstWindow is how much unused budget may accumulate, so a short idle
+// period allows a burst of at most one second's worth of work.
+const burstWindow = time.Second
+
+// IOLimiter enforces a deletions-per-second and a bytes-per-second budget.
+// Production hosts see latency spikes when hundreds of thousands of unlinks
+// hit the journal at once, so deletions are spread out instead.
+type IOLimiter struct {
+	mu        sync.Mutex
+	perSecond float64 // deletions per second (0 = unlimited)
+	bytesRate float64 // bytes per second (0 = unlimited)
+	nextOp    time.Time
+	nextByte  time.Time
+}
+
+// NewIOLimiter creates a limiter; zero disables the corresponding budget
+func NewIOLimiter(deletionsPerSecond float64, bytesPerSecond int64) *IOLimiter {
+	now := time.Now()
+	return &IOLimiter{
+		perSecond: deletionsPerSecond,
+		bytesRate: float64(bytesPerSecond),
+		nextOp:    now,
+		nextByte:  now,
+	}
+}
+
+// Wait blocks until one more deletion of the given size fits in the budget
+func (l *IOLimiter) Wait(ctx context.Context, bytes int64) error {
+	if l == nil {
+		return nil
+	}
+
+	delay := l.reserve(time.Now(), bytes)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve books the next deletion and returns how long the caller must wait
+func (l *IOLimiter) reserve(now time.Time, bytes int64) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var delay time.Duration
+	if l.perSecond > 0 {
+		l.nextOp = advance(l.nextOp, now, 1/l.perSecond)
+		delay = max(delay, l.nextOp.Sub(now))
+	}
+	if l.bytesRate > 0 && bytes > 0 {
+		l.nextByte = advance(l.nextByte, now, float64(bytes)/l.bytesRate)
+		delay = max(delay, l.nextByte.Sub(now))
+	}
+	return delay
+}
+
+// advance moves a virtual schedule forward by cost seconds, never letting it
+// fall further than burstWindow behind now
+func advance(next, now time.Time, costSeconds float64) time.Time {
+	if earliest := now.Add(-burstWindow); next.Before(earliest) {
+		next = earliest
+	}
+	return next.Add(time.Duration(costSeconds * float64(time.Second)))
+}
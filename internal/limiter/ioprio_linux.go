@@ -0,0 +1,41 @@
+//go:build linux
+
+package limiter
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	ioprioWhoProcess = 1
+	ioprioClassIdle  = 3
+	ioprioClassShift = 13
+)
+
+// SetIdleIOPriority moves every thread of the process into the idle I/O
+// scheduling class, so disk access only happens when no one else needs it.
+// ioprio is per thread, so each task is updated; threads created later
+// inherit the class from the thread that spawns them.
+func SetIdleIOPriority() error {
+	tasks, err := os.ReadDir("/proc/self/task")
+	if err != nil {
+		return fmt.Errorf("list threads: %w", err)
+	}
+
+	prio := uintptr(ioprioClassIdle << ioprioClassShift)
+	for _, task := range tasks {
+		tid, err := strconv.Atoi(task.Name())
+		if err != nil {
+			continue
+		}
+		_, _, errno := unix.Syscall(unix.SYS_IOPRIO_SET, ioprioWhoProcess, uintptr(tid), prio)
+		if errno != 0 && errno != unix.ESRCH {
+			return fmt.Errorf("ioprio_set(%d): %w", tid, errno)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package limiter
+
+import "errors"
+
+// SetIdleIOPriority is only supported on Linux
+func SetIdleIOPriority() error {
+	return errors.New("idle I/O priority is only supported on Linux")
+}
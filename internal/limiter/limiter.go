@@ -1,53 +1,143 @@
 package limiter
 
 import (
-	"runtime"
+	"context"
+	"sync"
 	"time"
 )
 
-// CPULimiter throttles CPU usage to a maximum percentage
+// checkInterval bounds how often the CPU limiter samples process CPU time.
+// Throttle is called per file, so sampling on every call would dominate the cost.
+const checkInterval = 50 * time.Millisecond
+
+// windowLength is how much history the CPU limiter averages over
+const windowLength = time.Second
+
+// CPULimiter throttles CPU usage to a maximum percentage of one core.
+// It measures the process's actual CPU time (utime+stime from /proc/self/stat)
+// and only sleeps when usage over the current window exceeds the budget.
 type CPULimiter struct {
-	maxPercent float64
-	lastSleep  time.Time
+	mu          sync.Mutex
+	maxPercent  float64
+	cpuTime     func() (time.Duration, error)
+	sleep       func(time.Duration)
+	windowStart time.Time
+	windowCPU   time.Duration
+	lastCheck   time.Time
 }
 
 // NewCPULimiter creates a new CPU limiter
 func NewCPULimiter(maxPercent float64) *CPULimiter {
-	return &CPULimiter{
+	l := &CPULimiter{
 		maxPercent: maxPercent,
-		lastSleep:  time.Now(),
+		cpuTime:    processCPUTime,
+		sleep:      time.Sleep,
 	}
+	l.resetWindow(time.Now())
+	return l
 }
 
-// Throttle sleeps to limit CPU usage to maxPercent
-// This is a simple implementation that sleeps periodically
-// For more accurate control, consider using cgroups or systemd limits
+// Throttle sleeps just long enough to bring CPU usage over the current window
+// back under maxPercent. It is cheap to call between individual operations.
 func (l *CPULimiter) Throttle() {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	if l.maxPercent <= 0 || l.maxPercent >= 100 {
 		return // No limit or invalid
 	}
 
-	// Simple throttling: sleep for a percentage of time
-	// If we want to use maxPercent CPU, we sleep for (100 - maxPercent) of the time
-	sleepPercent := 100.0 - l.maxPercent
+	now := time.Now()
+	if now.Sub(l.lastCheck) < checkInterval {
+		return
+	}
+	l.lastCheck = now
+
+	used, err := l.cpuTime()
+	if err != nil {
+		return
+	}
 
-	// Calculate sleep duration based on a work cycle
-	// This is a simplified approach - in practice, you'd want more sophisticated
-	// CPU measurement and throttling
-	workTime := 10 * time.Millisecond // Work for 10ms
-	sleepTime := time.Duration(float64(workTime) * (sleepPercent / l.maxPercent))
+	wall := now.Sub(l.windowStart)
+	cpu := used - l.windowCPU
+	if wall <= 0 || cpu <= 0 {
+		return
+	}
 
-	// Only sleep if enough time has passed since last sleep
-	if time.Since(l.lastSleep) > workTime {
-		time.Sleep(sleepTime)
-		l.lastSleep = time.Now()
+	// Wall time needed for cpu to represent exactly maxPercent of it
+	budgetWall := time.Duration(float64(cpu) * 100.0 / l.maxPercent)
+	if pause := budgetWall - wall; pause > 0 {
+		l.sleep(pause)
+		now = now.Add(pause)
 	}
 
-	// Yield to other goroutines
-	runtime.Gosched()
+	if now.Sub(l.windowStart) >= windowLength {
+		l.resetWindow(now)
+	}
 }
 
 // SetMaxPercent updates the maximum CPU percentage
 func (l *CPULimiter) SetMaxPercent(maxPercent float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	l.maxPercent = maxPercent
 }
+
+func (l *CPULimiter) resetWindow(now time.Time) {
+	l.windowStart = now
+	l.lastCheck = now
+	if used, err := l.cpuTime(); err == nil {
+		l.windowCPU = used
+	}
+}
+
+// Limiter combines CPU pacing with an I/O budget for deletions.
+// A nil *Limiter is valid and never throttles.
+type Limiter struct {
+	CPU *CPULimiter
+	IO  *IOLimiter
+}
+
+// Options configures a Limiter; zero values disable the corresponding limit
+type Options struct {
+	MaxCPUPercent      float64
+	DeletionsPerSecond float64
+	BytesPerSecond     int64
+}
+
+// New creates a Limiter from opts, or returns nil when no limit is configured
+func New(opts Options) *Limiter {
+	l := &Limiter{}
+	if opts.MaxCPUPercent > 0 && opts.MaxCPUPercent < 100 {
+		l.CPU = NewCPULimiter(opts.MaxCPUPercent)
+	}
+	if opts.DeletionsPerSecond > 0 || opts.BytesPerSecond > 0 {
+		l.IO = NewIOLimiter(opts.DeletionsPerSecond, opts.BytesPerSecond)
+	}
+	if l.CPU == nil && l.IO == nil {
+		return nil
+	}
+	return l
+}
+
+// Pace is called between scan operations and only applies the CPU budget
+func (l *Limiter) Pace() {
+	if l == nil {
+		return
+	}
+	l.CPU.Throttle()
+}
+
+// WaitDelete is called before each removal. It applies the CPU budget and
+// blocks until the I/O budget allows removing a file of the given size.
+func (l *Limiter) WaitDelete(ctx context.Context, bytes int64) error {
+	if l == nil {
+		return nil
+	}
+	l.CPU.Throttle()
+	return l.IO.Wait(ctx, bytes)
+}
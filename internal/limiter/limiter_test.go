@@ -0,0 +1,83 @@
+package limiter
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCPULimiterSleepsOnlyWhenOverBudget(t *testing.T) {
+	tests := []struct {
+		name      string
+		cpuUsed   time.Duration // CPU consumed since the window started
+		wantSleep bool
+	}{
+		{"idle", 0, false},
+		{"under budget", 5 * time.Millisecond, false},
+		{"over budget", 500 * time.Millisecond, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cpu time.Duration
+			var slept time.Duration
+			l := &CPULimiter{
+				maxPercent: 10,
+				cpuTime:    func() (time.Duration, error) { return cpu, nil },
+				sleep:      func(d time.Duration) { slept += d },
+			}
+			l.resetWindow(time.Now().Add(-100 * time.Millisecond))
+			l.lastCheck = time.Time{}
+
+			cpu = tt.cpuUsed
+			l.Throttle()
+
+			if (slept > 0) != tt.wantSleep {
+				t.Fatalf("slept %v, wantSleep %v", slept, tt.wantSleep)
+			}
+			if tt.wantSleep && slept < 4*time.Second {
+				// 500ms of CPU at 10% needs ~5s of wall time
+				t.Fatalf("slept %v, expected close to 5s", slept)
+			}
+		})
+	}
+}
+
+func TestIOLimiterReserve(t *testing.T) {
+	now := time.Now()
+
+	l := NewIOLimiter(10, 0)
+	l.nextOp = now.Add(-burstWindow)
+	// One second of burst is allowed after an idle period
+	for i := 0; i < 10; i++ {
+		if d := l.reserve(now, 0); d > 0 {
+			t.Fatalf("deletion %d delayed by %v within burst", i, d)
+		}
+	}
+	if d := l.reserve(now, 0); d <= 0 {
+		t.Fatalf("deletion beyond burst was not delayed")
+	}
+
+	b := NewIOLimiter(0, 1000)
+	b.nextByte = now.Add(-burstWindow)
+	if d := b.reserve(now, 3000); d != 2*time.Second {
+		t.Fatalf("3000 bytes at 1000 B/s after full burst: got delay %v, want 2s", d)
+	}
+}
+
+func TestNilLimiterNeverBlocks(t *testing.T) {
+	var l *Limiter
+	l.Pace()
+	if err := l.WaitDelete(context.Background(), 1<<30); err != nil {
+		t.Fatalf("nil limiter returned %v", err)
+	}
+	if New(Options{}) != nil {
+		t.Fatalf("expected nil limiter when no limits are configured")
+	}
+}
+
+func TestProcessCPUTime(t *testing.T) {
+	if _, err := processCPUTime(); err != nil {
+		t.Fatalf("processCPUTime: %v", err)
+	}
+}
@@ -16,6 +16,11 @@ const (
 	logFile = "cleanup.log"
 )
 
+// Dir returns the directory the daemon writes its logs to
+func Dir() string {
+	return logDir
+}
+
 // Logger wraps the standard logger with rotation support
 type Logger struct {
 	*log.Logger
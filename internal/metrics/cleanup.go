@@ -17,15 +17,63 @@ var (
 	// FilesDeletedTotal tracks total files deleted
 	FilesDeletedTotal prometheus.Counter
 
+	// FilesCompressedTotal tracks total files compressed in place by compress tiers
+	FilesCompressedTotal prometheus.Counter
+
+	// CompressBytesSavedTotal tracks bytes saved by compressing files
+	CompressBytesSavedTotal prometheus.Counter
+
+	// FilesTruncatedTotal tracks total files truncated in place
+	FilesTruncatedTotal prometheus.Counter
+
+	// TruncateBytesFreedTotal tracks bytes freed by truncating files
+	TruncateBytesFreedTotal prometheus.Counter
+
 	// CleanupLastRunTimestamp records Unix timestamp of last cleanup
 	CleanupLastRunTimestamp prometheus.Gauge
 
-	// CleanupLastMode tracks the last cleanup mode used (AGE, DISK-USAGE, STACK)
+	// CleanupLastMode tracks the last cleanup mode used (AGE, DISK, STACK, TIER-n)
 	CleanupLastMode *prometheus.GaugeVec
 
 	// PathBytesDeletedTotal tracks bytes deleted per monitored path
 	PathBytesDeletedTotal *prometheus.CounterVec
 
+	// BytesFreedByOwnerTotal tracks bytes deleted per owning user
+	BytesFreedByOwnerTotal *prometheus.CounterVec
+
+	// BytesFreedByGroupTotal tracks bytes deleted per owning group
+	BytesFreedByGroupTotal *prometheus.CounterVec
+
+	// CleanupPaused is 1 while the whole daemon is paused
+	CleanupPaused prometheus.Gauge
+
+	// RulePaused reports per rule whether it is paused (1) or active (0)
+	RulePaused *prometheus.GaugeVec
+
+	// CircuitBreakerTripped reports per rule whether the last cycle was aborted by its blast-radius limits
+	CircuitBreakerTripped *prometheus.GaugeVec
+
+	// CircuitBreakerTripsTotal counts cycles aborted by blast-radius limits
+	CircuitBreakerTripsTotal prometheus.Counter
+
+	// DeleteErrorsTotal counts failed deletes by errno and retry class
+	DeleteErrorsTotal *prometheus.CounterVec
+
+	// ScanErrors tracks entries the last scan of a rule could not read, by kind
+	ScanErrors *prometheus.GaugeVec
+
+	// ScanSkippedBytes estimates the bytes below directories the last scan of a rule could not read
+	ScanSkippedBytes *prometheus.GaugeVec
+
+	// RetentionHeldBytes tracks bytes a rule selected but its retention floor kept at the last scan
+	RetentionHeldBytes *prometheus.GaugeVec
+
+	// RetentionTargetMissed is 1 for rules whose retention floor kept them from their disk target
+	RetentionTargetMissed *prometheus.GaugeVec
+
+	// QuarantinedPaths tracks paths whose deletes are suppressed after a permanent failure
+	QuarantinedPaths prometheus.Gauge
+
 	// Worker pool metrics (beerus-inspired)
 	// WorkersActive tracks number of active cleanup workers per path
 	WorkersActive *prometheus.GaugeVec
@@ -57,6 +105,26 @@ func initCleanupMetrics() {
 		"Total number of files deleted by StorageSage.",
 	)
 
+	FilesCompressedTotal = NewCounter(
+		"storagesage_files_compressed_total",
+		"Total number of files compressed in place by compress tiers.",
+	)
+
+	CompressBytesSavedTotal = NewBytesCounter(
+		"storagesage_compress_bytes_saved_total",
+		"Total bytes saved by compressing files (original size minus compressed size).",
+	)
+
+	FilesTruncatedTotal = NewCounter(
+		"storagesage_files_truncated_total",
+		"Total number of oversized files truncated in place.",
+	)
+
+	TruncateBytesFreedTotal = NewBytesCounter(
+		"storagesage_truncate_bytes_freed_total",
+		"Total bytes freed by truncating files (size before minus the tail kept).",
+	)
+
 	CleanupLastRunTimestamp = NewSizeGauge(
 		"storagesage_cleanup_last_run_timestamp",
 		"Timestamp of the last cleanup run (Unix epoch seconds).",
@@ -64,7 +132,7 @@ func initCleanupMetrics() {
 
 	CleanupLastMode = NewGaugeVec(
 		"storagesage_cleanup_last_mode",
-		"Last cleanup mode used (1=AGE, 2=DISK-USAGE, 3=STACK).",
+		"Most critical cleanup mode of the last run (AGE, DISK, STACK or TIER-n for rules with tiers).",
 		[]string{"mode"},
 	)
 
@@ -74,6 +142,75 @@ func initCleanupMetrics() {
 		[]string{"path"},
 	)
 
+	BytesFreedByOwnerTotal = NewCounterVec(
+		"storagesage_bytes_freed_by_owner_total",
+		"Total bytes deleted per owning user.",
+		[]string{"owner"},
+	)
+
+	BytesFreedByGroupTotal = NewCounterVec(
+		"storagesage_bytes_freed_by_group_total",
+		"Total bytes deleted per owning group.",
+		[]string{"group"},
+	)
+
+	CleanupPaused = NewGauge(
+		"storagesage_cleanup_paused",
+		"Whether cleanup is paused for the whole daemon (1=paused).",
+	)
+
+	RulePaused = NewGaugeVec(
+		"storagesage_rule_paused",
+		"Whether cleanup is paused for a path rule (1=paused).",
+		[]string{"path"},
+	)
+
+	CircuitBreakerTripped = NewGaugeVec(
+		"storagesage_circuit_breaker_tripped",
+		"Whether the last cycle was aborted by the blast-radius limits of a rule (path=\"*\" for the global limits).",
+		[]string{"path"},
+	)
+
+	CircuitBreakerTripsTotal = NewCounter(
+		"storagesage_circuit_breaker_trips_total",
+		"Total number of cleanup cycles aborted by blast-radius limits.",
+	)
+
+	DeleteErrorsTotal = NewCounterVec(
+		"storagesage_delete_errors_total",
+		"Total number of failed deletes by errno and class (transient, permanent or other).",
+		[]string{"errno", "class"},
+	)
+
+	ScanErrors = NewGaugeVec(
+		"storagesage_scan_errors",
+		"Entries the last scan of a rule could not read, by kind (permission_denied, io_error, stale_handle, other).",
+		[]string{"path", "kind"},
+	)
+
+	RetentionHeldBytes = NewGaugeVec(
+		"storagesage_retention_floor_held_bytes",
+		"Bytes a rule selected at the last scan but kept because they are younger than min_retention_days.",
+		[]string{"path"},
+	)
+
+	RetentionTargetMissed = NewGaugeVec(
+		"storagesage_retention_floor_target_missed",
+		"1 when the retention floor kept a rule from freeing enough space to reach its target at the last scan.",
+		[]string{"path"},
+	)
+
+	ScanSkippedBytes = NewGaugeVec(
+		"storagesage_scan_skipped_bytes",
+		"Estimated bytes below directories the last scan of a rule could not read (from the scan index; 0 when unknown).",
+		[]string{"path"},
+	)
+
+	QuarantinedPaths = NewGauge(
+		"storagesage_quarantined_paths",
+		"Number of paths whose deletes are suppressed after a permanent failure.",
+	)
+
 	// Initialize worker pool metrics
 	WorkersActive = NewGaugeVec(
 		"storagesage_cleanup_workers_active",
@@ -104,9 +241,25 @@ func registerCleanupMetrics() {
 	prometheus.MustRegister(CleanupDuration)
 	prometheus.MustRegister(BytesFreedTotal)
 	prometheus.MustRegister(FilesDeletedTotal)
+	prometheus.MustRegister(FilesCompressedTotal)
+	prometheus.MustRegister(CompressBytesSavedTotal)
+	prometheus.MustRegister(FilesTruncatedTotal)
+	prometheus.MustRegister(TruncateBytesFreedTotal)
 	prometheus.MustRegister(CleanupLastRunTimestamp)
 	prometheus.MustRegister(CleanupLastMode)
 	prometheus.MustRegister(PathBytesDeletedTotal)
+	prometheus.MustRegister(BytesFreedByOwnerTotal)
+	prometheus.MustRegister(BytesFreedByGroupTotal)
+	prometheus.MustRegister(CleanupPaused)
+	prometheus.MustRegister(RulePaused)
+	prometheus.MustRegister(CircuitBreakerTripped)
+	prometheus.MustRegister(CircuitBreakerTripsTotal)
+	prometheus.MustRegister(DeleteErrorsTotal)
+	prometheus.MustRegister(QuarantinedPaths)
+	prometheus.MustRegister(ScanErrors)
+	prometheus.MustRegister(ScanSkippedBytes)
+	prometheus.MustRegister(RetentionHeldBytes)
+	prometheus.MustRegister(RetentionTargetMissed)
 	prometheus.MustRegister(WorkersActive)
 	prometheus.MustRegister(BatchesTotal)
 	prometheus.MustRegister(BatchDuration)
@@ -138,6 +291,119 @@ func RecordPathDeletion(path string, bytes int64) {
 	}
 }
 
+// RecordCompression records one compressed file and the bytes it saved;
+// a file that did not shrink saves nothing
+func RecordCompression(saved int64) {
+	if FilesCompressedTotal == nil || CompressBytesSavedTotal == nil {
+		return
+	}
+	FilesCompressedTotal.Inc()
+	if saved > 0 {
+		CompressBytesSavedTotal.Add(float64(saved))
+	}
+}
+
+// RecordTruncation records one truncated file and the bytes it freed
+func RecordTruncation(freed int64) {
+	if FilesTruncatedTotal == nil || TruncateBytesFreedTotal == nil {
+		return
+	}
+	FilesTruncatedTotal.Inc()
+	if freed > 0 {
+		TruncateBytesFreedTotal.Add(float64(freed))
+	}
+}
+
+// RecordOwnerDeletion records bytes deleted for a specific owning user
+func RecordOwnerDeletion(owner string, bytes int64) {
+	if BytesFreedByOwnerTotal != nil {
+		BytesFreedByOwnerTotal.WithLabelValues(owner).Add(float64(bytes))
+	}
+}
+
+// RecordGroupDeletion records bytes deleted for a specific owning group
+func RecordGroupDeletion(group string, bytes int64) {
+	if BytesFreedByGroupTotal != nil {
+		BytesFreedByGroupTotal.WithLabelValues(group).Add(float64(bytes))
+	}
+}
+
+// SetPauseState publishes the global pause flag and the pause flag of every configured rule.
+// Rules no longer in the config are dropped from the gauge.
+func SetPauseState(global bool, rules map[string]bool) {
+	if CleanupPaused == nil || RulePaused == nil {
+		return
+	}
+	CleanupPaused.Set(boolToFloat(global))
+	RulePaused.Reset()
+	for path, paused := range rules {
+		RulePaused.WithLabelValues(path).Set(boolToFloat(paused))
+	}
+}
+
+// SetCircuitBreaker publishes which rules tripped the circuit breaker in the
+// last cycle; an empty list clears the alert.
+func SetCircuitBreaker(tripped []string) {
+	if CircuitBreakerTripped == nil || CircuitBreakerTripsTotal == nil {
+		return
+	}
+	CircuitBreakerTripped.Reset()
+	for _, path := range tripped {
+		CircuitBreakerTripped.WithLabelValues(path).Set(1)
+	}
+	if len(tripped) > 0 {
+		CircuitBreakerTripsTotal.Inc()
+	}
+}
+
+// RecordDeleteError counts one failed delete
+func RecordDeleteError(errno, class string) {
+	if DeleteErrorsTotal == nil {
+		return
+	}
+	DeleteErrorsTotal.WithLabelValues(errno, class).Inc()
+}
+
+// SetScanErrors publishes what the last scan of one rule could not read.
+// Every kind is set so a kind that cleared drops back to zero.
+func SetScanErrors(path string, kinds []string, counts map[string]int, skippedBytes int64) {
+	if ScanErrors == nil || ScanSkippedBytes == nil {
+		return
+	}
+	for _, kind := range kinds {
+		ScanErrors.WithLabelValues(path, kind).Set(float64(counts[kind]))
+	}
+	ScanSkippedBytes.WithLabelValues(path).Set(float64(skippedBytes))
+}
+
+// SetRetentionFloor publishes what one rule's retention floor kept at the last scan
+func SetRetentionFloor(path string, heldBytes int64, targetMissed bool) {
+	if RetentionHeldBytes == nil || RetentionTargetMissed == nil {
+		return
+	}
+	RetentionHeldBytes.WithLabelValues(path).Set(float64(heldBytes))
+	missed := 0.0
+	if targetMissed {
+		missed = 1
+	}
+	RetentionTargetMissed.WithLabelValues(path).Set(missed)
+}
+
+// SetQuarantinedPaths publishes the number of paths in quarantine
+func SetQuarantinedPaths(n int) {
+	if QuarantinedPaths == nil {
+		return
+	}
+	QuarantinedPaths.Set(float64(n))
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
 // Worker pool metric helpers (beerus-inspired)
 
 // SetActiveWorkers sets the number of active workers for a path
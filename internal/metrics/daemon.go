@@ -20,6 +20,15 @@ var (
 	// PathFilesTotal tracks total number of files within a monitored path
 	PathFilesTotal *prometheus.GaugeVec
 
+	// PathAllocatedBytes tracks bytes allocated on disk (st_blocks) within a monitored path
+	PathAllocatedBytes *prometheus.GaugeVec
+
+	// PathDirsTotal tracks total number of directories within a monitored path
+	PathDirsTotal *prometheus.GaugeVec
+
+	// PathSymlinksTotal tracks total number of symbolic links within a monitored path
+	PathSymlinksTotal *prometheus.GaugeVec
+
 	// PathFreeBytes tracks free space available on the filesystem containing the path
 	PathFreeBytes *prometheus.GaugeVec
 
@@ -52,6 +61,24 @@ func initDaemonMetrics() {
 		[]string{"path"},
 	)
 
+	PathAllocatedBytes = NewSizeGaugeVec(
+		"storagesage_path_allocated_bytes",
+		"Total bytes allocated on disk for files within the monitored path.",
+		[]string{"path"},
+	)
+
+	PathDirsTotal = NewSizeGaugeVec(
+		"storagesage_path_dirs_total",
+		"Total number of directories within the monitored path.",
+		[]string{"path"},
+	)
+
+	PathSymlinksTotal = NewSizeGaugeVec(
+		"storagesage_path_symlinks_total",
+		"Total number of symbolic links within the monitored path.",
+		[]string{"path"},
+	)
+
 	PathFreeBytes = NewSizeGaugeVec(
 		"storagesage_path_free_bytes",
 		"Free space available on the filesystem containing this path.",
@@ -71,6 +98,9 @@ func registerDaemonMetrics() {
 	prometheus.MustRegister(FreeSpacePercent)
 	prometheus.MustRegister(PathUsedBytes)
 	prometheus.MustRegister(PathFilesTotal)
+	prometheus.MustRegister(PathAllocatedBytes)
+	prometheus.MustRegister(PathDirsTotal)
+	prometheus.MustRegister(PathSymlinksTotal)
 	prometheus.MustRegister(PathFreeBytes)
 	prometheus.MustRegister(PathTotalBytes)
 }
@@ -98,4 +128,7 @@ func UpdateAllDiskMetrics(path string, stats *disk.PathStats) {
 	// Path-level metrics (scanned usage)
 	PathUsedBytes.WithLabelValues(path).Set(float64(stats.UsedBytes))
 	PathFilesTotal.WithLabelValues(path).Set(float64(stats.FileCount))
+	PathAllocatedBytes.WithLabelValues(path).Set(float64(stats.AllocatedBytes))
+	PathDirsTotal.WithLabelValues(path).Set(float64(stats.DirCount))
+	PathSymlinksTotal.WithLabelValues(path).Set(float64(stats.SymlinkCount))
 }
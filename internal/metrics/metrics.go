@@ -2,11 +2,10 @@ package metrics
 
 import (
 	"context"
+	"encoding/json"
 	"log"
 	"net/http"
-	"os"
 	"sync"
-	"syscall"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -14,16 +13,17 @@ import (
 
 var (
 	// Core synchronization primitives
-	initOnce       sync.Once
-	serverMutex    sync.Mutex
-	modeMutex      sync.RWMutex
-	currentSrv     *http.Server
-	triggerChannel chan os.Signal
-	reloadChannel  chan os.Signal
+	initOnce    sync.Once
+	serverMutex sync.Mutex
+	modeMutex   sync.RWMutex
+	currentSrv  *http.Server
 
 	// Global health checker instance
 	globalHealthChecker *HealthChecker
 	healthMutex         sync.RWMutex
+
+	// Extra sections reported by /health (e.g. sandbox status)
+	healthInfo = make(map[string]interface{})
 )
 
 // Init initializes all metrics subsystems and registers them with Prometheus
@@ -46,24 +46,12 @@ func Init() {
 		// Even before first cleanup run (required for test compliance)
 		CleanupLastRunTimestamp.Set(0)
 		CleanupLastMode.WithLabelValues("NONE").Set(0)
-
-		// Initialize trigger channel for cleanup signals
-		triggerChannel = make(chan os.Signal, 1)
 	})
 }
 
-// SetTriggerChannel sets the channel for triggering cleanup cycles
-func SetTriggerChannel(ch chan os.Signal) {
-	triggerChannel = ch
-}
-
-// SetReloadChannel sets the channel for triggering config reloads
-func SetReloadChannel(ch chan os.Signal) {
-	reloadChannel = ch
-}
-
 // StartServer starts the metrics HTTP server on the specified address
-// Exposes /metrics (Prometheus), /health, and /trigger endpoints
+// Exposes /metrics (Prometheus) and /health; control actions are served by
+// the authenticated control API instead (see internal/control)
 func StartServer(addr string, logger *log.Logger) {
 	serverMutex.Lock()
 	defer serverMutex.Unlock()
@@ -84,60 +72,29 @@ func StartServer(addr string, logger *log.Logger) {
 		hc := globalHealthChecker
 		healthMutex.RUnlock()
 
-		if hc != nil && hc.IsHealthy() {
-			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write([]byte(`{"status":"ok","healthy":true}`))
-		} else if hc != nil {
+		response := map[string]interface{}{"status": "ok", "healthy": true}
+		status := http.StatusOK
+		if hc != nil && !hc.IsHealthy() {
 			// Report unhealthy state with component details
-			w.WriteHeader(http.StatusServiceUnavailable)
-			_, _ = w.Write([]byte(`{"status":"degraded","healthy":false}`))
-		} else {
-			// No health checker configured, default to ok
-			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write([]byte(`{"status":"ok","healthy":true}`))
-		}
-	})
-
-	// Add trigger endpoint
-	mux.HandleFunc("/trigger", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
+			response["status"] = "degraded"
+			response["healthy"] = false
+			status = http.StatusServiceUnavailable
 		}
+		// No health checker configured defaults to ok
 
-		// Send USR1 signal to trigger cleanup
-		if triggerChannel != nil {
-			select {
-			case triggerChannel <- syscall.SIGUSR1:
-				w.WriteHeader(http.StatusOK)
-				_, _ = w.Write([]byte("Cleanup triggered"))
-			default:
-				http.Error(w, "Trigger channel full", http.StatusServiceUnavailable)
-			}
-		} else {
-			http.Error(w, "Trigger channel not initialized", http.StatusServiceUnavailable)
+		healthMutex.RLock()
+		for key, value := range healthInfo {
+			response[key] = value
 		}
-	})
+		healthMutex.RUnlock()
 
-	// Add reload endpoint for config reload
-	mux.HandleFunc("/reload", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		body, err := json.Marshal(response)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-
-		// Send HUP signal to trigger config reload
-		if reloadChannel != nil {
-			select {
-			case reloadChannel <- syscall.SIGHUP:
-				w.WriteHeader(http.StatusOK)
-				_, _ = w.Write([]byte("Config reload triggered"))
-			default:
-				http.Error(w, "Reload channel full", http.StatusServiceUnavailable)
-			}
-		} else {
-			http.Error(w, "Reload channel not initialized", http.StatusServiceUnavailable)
-		}
+		w.WriteHeader(status)
+		_, _ = w.Write(body)
 	})
 
 	srv := &http.Server{
@@ -189,6 +146,13 @@ func SetHealthChecker(hc *HealthChecker) {
 	globalHealthChecker = hc
 }
 
+// SetHealthInfo adds a named section to the /health response
+func SetHealthInfo(key string, value interface{}) {
+	healthMutex.Lock()
+	defer healthMutex.Unlock()
+	healthInfo[key] = value
+}
+
 // GetHealthChecker returns the global health checker instance
 func GetHealthChecker() *HealthChecker {
 	healthMutex.RLock()
@@ -206,6 +206,18 @@ func TestCleanupMetricHelpers(t *testing.T) {
 		RecordPathDeletion("/test/path", 1024)
 		RecordPathDeletion("/another/path", 2048)
 	})
+
+	t.Run("RecordOwnerDeletion", func(t *testing.T) {
+		// Should not panic
+		RecordOwnerDeletion("alice", 1024)
+		RecordOwnerDeletion("1001", 2048)
+	})
+
+	t.Run("RecordGroupDeletion", func(t *testing.T) {
+		// Should not panic
+		RecordGroupDeletion("staff", 1024)
+		RecordGroupDeletion("1001", 2048)
+	})
 }
 
 // TestDaemonMetricHelpers tests daemon subsystem helper functions
@@ -0,0 +1,117 @@
+package safety
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrLegalHold is wrapped by every *HoldError
+var ErrLegalHold = errors.New("under legal hold")
+
+// LegalHold preserves everything matching Pattern: an absolute path, which
+// holds the file or the whole subtree, or an absolute glob
+type LegalHold struct {
+	ID      int64
+	Pattern string
+	Owner   string
+	Reason  string
+}
+
+// HoldError reports a delete denied by a legal hold.
+// errors.Is(err, ErrLegalHold) is true for every HoldError.
+type HoldError struct {
+	Path   string
+	Hold   LegalHold
+	Detail string // How the target relates to the held pattern
+}
+
+func (e *HoldError) Error() string {
+	return fmt.Sprintf("%s #%d (owner %s, %s): %s", ErrLegalHold, e.Hold.ID, e.Hold.Owner, e.Hold.Reason, e.Detail)
+}
+
+func (e *HoldError) Unwrap() error {
+	return ErrLegalHold
+}
+
+// Reason returns the short form used in SKIP records
+func (e *HoldError) Reason() string {
+	return "legal_hold"
+}
+
+// IsHoldGlob reports whether a hold pattern is a glob rather than a path
+func IsHoldGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+// ValidateHoldPattern rejects hold patterns that are relative or malformed
+func ValidateHoldPattern(pattern string) error {
+	if !filepath.IsAbs(pattern) {
+		return fmt.Errorf("hold pattern must be absolute: %q", pattern)
+	}
+	if filepath.Clean(pattern) == string(os.PathSeparator) {
+		return errors.New("hold pattern cannot be /")
+	}
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid hold pattern %q: %w", pattern, err)
+	}
+	return nil
+}
+
+// SetHolds sets the legal holds in force; they replace any set before
+func (v *Validator) SetHolds(holds []LegalHold) {
+	v.Holds = make([]LegalHold, 0, len(holds))
+	for _, h := range holds {
+		if !IsHoldGlob(h.Pattern) {
+			h.Pattern = filepath.Clean(h.Pattern)
+		}
+		v.Holds = append(v.Holds, h)
+	}
+}
+
+// checkHolds returns a *HoldError if a legal hold covers path
+func (v *Validator) checkHolds(path string) error {
+	for _, h := range v.Holds {
+		if detail, ok := h.covers(path); ok {
+			return &HoldError{Path: path, Hold: h, Detail: detail}
+		}
+	}
+	return nil
+}
+
+// covers reports whether deleting path would remove something h holds: path
+// matches it, lies below something that matches it, or is a directory that
+// may contain matches
+func (h LegalHold) covers(path string) (string, bool) {
+	if !IsHoldGlob(h.Pattern) {
+		switch {
+		case hasPathPrefix(path, h.Pattern):
+			return fmt.Sprintf("%s is at or below held path %s", path, h.Pattern), true
+		case hasPathPrefix(h.Pattern, path):
+			return fmt.Sprintf("%s contains held path %s", path, h.Pattern), true
+		}
+		return "", false
+	}
+
+	for dir := path; ; dir = filepath.Dir(dir) {
+		if ok, _ := filepath.Match(h.Pattern, dir); ok {
+			return fmt.Sprintf("%s matches %q", dir, h.Pattern), true
+		}
+		if dir == filepath.Dir(dir) {
+			break
+		}
+	}
+	// Matches may only lie below the pattern's fixed leading directories
+	if static := globBase(h.Pattern); hasPathPrefix(static, path) {
+		return fmt.Sprintf("%s may contain matches of %q", path, h.Pattern), true
+	}
+	return "", false
+}
+
+// globBase returns the directories of pattern before its first wildcard
+func globBase(pattern string) string {
+	i := strings.IndexAny(pattern, "*?[")
+	return filepath.Dir(pattern[:i+1])
+}
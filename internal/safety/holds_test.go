@@ -0,0 +1,65 @@
+package safety
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLegalHoldCovers(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"/data/case", "/data/case", true},
+		{"/data/case", "/data/case/mail/1.eml", true},
+		{"/data/case", "/data", true}, // removing /data would remove the held subtree
+		{"/data/case", "/data/cases", false},
+		{"/data/*/mail", "/data/acme/mail/1.eml", true},
+		{"/data/*/mail", "/data/acme/notes.txt", false},
+		{"/data/*.pst", "/data/box.pst", true},
+		{"/data/*.pst", "/data/box.ost", false},
+		{"/data/acme/*.pst", "/data", true}, // may contain matches
+		{"/data/acme/*.pst", "/data/other", false},
+	}
+	for _, tt := range tests {
+		h := LegalHold{ID: 7, Pattern: tt.pattern, Owner: "legal", Reason: "case 114"}
+		if _, got := h.covers(tt.path); got != tt.want {
+			t.Errorf("hold %q covers %s = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestValidatorHonorsLegalHolds(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"case", "case/mail.eml", "other.eml"} {
+		var err error
+		if filepath.Ext(name) == "" {
+			err = os.Mkdir(filepath.Join(root, name), 0755)
+		} else {
+			err = os.WriteFile(filepath.Join(root, name), nil, 0644)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	v := NewValidator([]string{root}, nil)
+	v.SetHolds([]LegalHold{{ID: 3, Pattern: root + "/case/", Owner: "legal", Reason: "case 114"}})
+
+	err := v.ValidateDeleteTarget(root + "/case/mail.eml")
+	var holdErr *HoldError
+	if !errors.Is(err, ErrLegalHold) || !errors.As(err, &holdErr) || holdErr.Hold.ID != 3 || holdErr.Reason() != "legal_hold" {
+		t.Fatalf("ValidateDeleteTarget = %v, want legal hold #3", err)
+	}
+	if err := v.ValidateDeleteTarget(root + "/other.eml"); err != nil {
+		t.Errorf("unheld path denied: %v", err)
+	}
+
+	for _, bad := range []string{"relative/path", "/", "/data/[x"} {
+		if ValidateHoldPattern(bad) == nil {
+			t.Errorf("ValidateHoldPattern(%q) accepted", bad)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package safety
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PolicyKind identifies which protection rule denied a delete
+type PolicyKind string
+
+const (
+	PolicyGlob      PolicyKind = "glob"
+	PolicyMarker    PolicyKind = "marker_file"
+	PolicyXattr     PolicyKind = "xattr"
+	PolicyImmutable PolicyKind = "immutable"
+	PolicyOwner     PolicyKind = "owner"
+)
+
+// PolicyError reports a delete denied by the protection policy.
+// errors.Is(err, ErrProtectedPath) is true for every PolicyError.
+type PolicyError struct {
+	Kind   PolicyKind
+	Path   string // The target whose deletion was denied
+	Detail string // Which file or rule triggered the denial
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("%s (%s): %s", ErrProtectedPath, e.Kind, e.Detail)
+}
+
+func (e *PolicyError) Unwrap() error {
+	return ErrProtectedPath
+}
+
+// Reason returns the short form used in SKIP records, e.g. "protected:marker_file"
+func (e *PolicyError) Reason() string {
+	return "protected:" + string(e.Kind)
+}
+
+// Policy is a declarative set of protection rules enforced by ValidateDeleteTarget.
+// Marker files and xattrs on a directory protect its whole subtree.
+type Policy struct {
+	Globs         []string // Matched against the full path if the pattern contains '/', else against each path element
+	MarkerFile    string   // File name that protects the directory it is in
+	Xattr         string   // Extended attribute name that protects a file or directory
+	SkipImmutable bool     // Deny files with the immutable or append-only attribute
+	AllowUIDs     []uint32 // If non-empty, only these owners may be deleted
+	DenyUIDs      []uint32 // Owners that are never deleted
+}
+
+// SetPolicy attaches a protection policy to the validator
+func (v *Validator) SetPolicy(p *Policy) {
+	v.Policy = p
+}
+
+// Check returns a *PolicyError if path or one of its ancestors up to root is
+// protected by the policy. Markers and xattrs above root are not read, so
+// nothing outside the configured roots affects the decision; an empty root
+// checks every ancestor. Globs are matched without touching the disk and
+// still see the whole path. Descendants are not inspected: recursive deletes
+// validate every entry on their way down.
+func (p *Policy) Check(path, root string) error {
+	if p == nil {
+		return nil
+	}
+
+	// Globs and ancestor markers protect everything below them
+	for dir := path; ; dir = filepath.Dir(dir) {
+		if err := p.checkGlobs(path, dir); err != nil {
+			return err
+		}
+		if dir != path && (root == "" || hasPathPrefix(dir, root)) {
+			if err := p.checkDirMarkers(path, dir); err != nil {
+				return err
+			}
+		}
+		if dir == filepath.Dir(dir) {
+			break
+		}
+	}
+
+	info, err := os.Lstat(path)
+	if err != nil {
+		// Nothing on disk to protect; the delete itself will fail
+		return nil
+	}
+	if err := p.checkEntry(path, path, info); err != nil {
+		return err
+	}
+
+	// The parent being immutable makes the unlink fail; skip it cleanly instead
+	if p.SkipImmutable {
+		if immutable, _ := isImmutable(filepath.Dir(path)); immutable {
+			return &PolicyError{Kind: PolicyImmutable, Path: path, Detail: "parent directory " + filepath.Dir(path) + " is immutable"}
+		}
+	}
+
+	return nil
+}
+
+// checkEntry applies the per-file rules to one existing entry
+func (p *Policy) checkEntry(target, entry string, info os.FileInfo) error {
+	if p.MarkerFile != "" && info.Name() == p.MarkerFile {
+		return &PolicyError{Kind: PolicyMarker, Path: target, Detail: "marker file " + entry}
+	}
+	if info.IsDir() {
+		if err := p.checkDirMarkers(target, entry); err != nil {
+			return err
+		}
+	} else if p.Xattr != "" {
+		if ok, _ := hasXattr(entry, p.Xattr); ok {
+			return &PolicyError{Kind: PolicyXattr, Path: target, Detail: fmt.Sprintf("%s has %s", entry, p.Xattr)}
+		}
+	}
+	if p.SkipImmutable && (info.Mode().IsRegular() || info.IsDir()) {
+		if immutable, _ := isImmutable(entry); immutable {
+			return &PolicyError{Kind: PolicyImmutable, Path: target, Detail: entry + " is immutable or append-only"}
+		}
+	}
+	if uid, ok := fileUID(info); ok {
+		if err := p.checkOwner(target, entry, uid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkDirMarkers reports whether dir carries a marker file or protective xattr
+func (p *Policy) checkDirMarkers(target, dir string) error {
+	if p.MarkerFile != "" {
+		marker := filepath.Join(dir, p.MarkerFile)
+		if _, err := os.Lstat(marker); err == nil {
+			return &PolicyError{Kind: PolicyMarker, Path: target, Detail: "marker file " + marker}
+		}
+	}
+	if p.Xattr != "" {
+		if ok, _ := hasXattr(dir, p.Xattr); ok {
+			return &PolicyError{Kind: PolicyXattr, Path: target, Detail: fmt.Sprintf("%s has %s", dir, p.Xattr)}
+		}
+	}
+	return nil
+}
+
+func (p *Policy) checkGlobs(target, path string) error {
+	for _, pattern := range p.Globs {
+		subject := filepath.Base(path)
+		if strings.ContainsRune(pattern, '/') {
+			subject = path
+		}
+		if ok, _ := filepath.Match(pattern, subject); ok {
+			return &PolicyError{Kind: PolicyGlob, Path: target, Detail: fmt.Sprintf("%s matches %q", path, pattern)}
+		}
+	}
+	return nil
+}
+
+func (p *Policy) checkOwner(target, entry string, uid uint32) error {
+	for _, denied := range p.DenyUIDs {
+		if uid == denied {
+			return &PolicyError{Kind: PolicyOwner, Path: target, Detail: fmt.Sprintf("%s is owned by denied uid %d", entry, uid)}
+		}
+	}
+	if len(p.AllowUIDs) == 0 {
+		return nil
+	}
+	for _, allowed := range p.AllowUIDs {
+		if uid == allowed {
+			return nil
+		}
+	}
+	return &PolicyError{Kind: PolicyOwner, Path: target, Detail: fmt.Sprintf("%s is owned by uid %d, not in allow list", entry, uid)}
+}
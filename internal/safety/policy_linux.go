@@ -0,0 +1,66 @@
+//go:build linux
+
+package safety
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// Inode flags from linux/fs.h
+const (
+	fsImmutableFL = 0x00000010
+	fsAppendFL    = 0x00000020
+)
+
+// hasXattr reports whether path (not following symlinks) carries the named xattr
+func hasXattr(path, name string) (bool, error) {
+	_, err := unix.Lgetxattr(path, name, nil)
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, unix.ENODATA) || errors.Is(err, unix.ENOTSUP) {
+		return false, nil
+	}
+	return false, err
+}
+
+// isImmutable reports whether path has the immutable or append-only inode flag
+func isImmutable(path string) (bool, error) {
+	fd, err := unix.Open(path, unix.O_RDONLY|unix.O_NOFOLLOW|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = unix.Close(fd) }()
+
+	flags, err := unix.IoctlGetUint32(fd, unix.FS_IOC_GETFLAGS)
+	if err != nil {
+		if errors.Is(err, unix.ENOTTY) || errors.Is(err, unix.ENOTSUP) {
+			return false, nil // filesystem has no inode flags
+		}
+		return false, err
+	}
+	return flags&(fsImmutableFL|fsAppendFL) != 0, nil
+}
+
+// fileUID returns the owner recorded in info
+func fileUID(info os.FileInfo) (uint32, bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return st.Uid, true
+}
+
+// changeTime returns the inode change time recorded in info
+func changeTime(info os.FileInfo) (time.Time, bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(st.Ctim.Sec), int64(st.Ctim.Nsec)), true
+}
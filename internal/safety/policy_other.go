@@ -0,0 +1,28 @@
+//go:build !linux
+
+package safety
+
+import (
+	"os"
+	"time"
+)
+
+// hasXattr is only implemented on Linux
+func hasXattr(path, name string) (bool, error) {
+	return false, nil
+}
+
+// isImmutable is only implemented on Linux
+func isImmutable(path string) (bool, error) {
+	return false, nil
+}
+
+// fileUID is only implemented on Linux
+func fileUID(info os.FileInfo) (uint32, bool) {
+	return 0, false
+}
+
+// changeTime is only implemented on Linux
+func changeTime(info os.FileInfo) (time.Time, bool) {
+	return time.Time{}, false
+}
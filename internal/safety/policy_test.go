@@ -0,0 +1,133 @@
+package safety
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestPolicyCheck verifies each policy rule and that denials are typed
+func TestPolicyCheck(t *testing.T) {
+	root := t.TempDir()
+
+	mustWrite := func(path string) string {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	plain := mustWrite(filepath.Join(root, "tmp", "old.log"))
+	kept := mustWrite(filepath.Join(root, "keep", "deep", "old.log"))
+	mustWrite(filepath.Join(root, "keep", ".storage-sage-keep"))
+	nested := filepath.Join(root, "mixed")
+	mustWrite(filepath.Join(nested, "a.log"))
+	mustWrite(filepath.Join(nested, "sub", ".storage-sage-keep"))
+	globbed := mustWrite(filepath.Join(root, "tmp", "snapshot.db"))
+	globbedDir := mustWrite(filepath.Join(root, "golden", "data.bin"))
+
+	uid := uint32(os.Getuid())
+
+	tests := []struct {
+		name     string
+		policy   *Policy
+		path     string
+		wantKind PolicyKind // empty = allowed
+	}{
+		{"nil policy", nil, kept, ""},
+		{"plain file", &Policy{MarkerFile: ".storage-sage-keep"}, plain, ""},
+		{"marker in ancestor", &Policy{MarkerFile: ".storage-sage-keep"}, kept, PolicyMarker},
+		{"marker directory itself", &Policy{MarkerFile: ".storage-sage-keep"}, filepath.Join(root, "keep"), PolicyMarker},
+		{"marker below directory target is checked per entry", &Policy{MarkerFile: ".storage-sage-keep"}, nested, ""},
+		{"basename glob", &Policy{Globs: []string{"*.db"}}, globbed, PolicyGlob},
+		{"path glob protects subtree", &Policy{Globs: []string{filepath.Join(root, "gold*")}}, globbedDir, PolicyGlob},
+		{"deny uid", &Policy{DenyUIDs: []uint32{uid}}, plain, PolicyOwner},
+		{"allow list excludes owner", &Policy{AllowUIDs: []uint32{uid + 1}}, plain, PolicyOwner},
+		{"allow list includes owner", &Policy{AllowUIDs: []uint32{uid}}, plain, ""},
+		{"missing path", &Policy{MarkerFile: ".storage-sage-keep"}, filepath.Join(root, "tmp", "gone"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Check(tt.path, root)
+			if tt.wantKind == "" {
+				if err != nil {
+					t.Fatalf("Check(%s) unexpected error: %v", tt.path, err)
+				}
+				return
+			}
+
+			var policyErr *PolicyError
+			if !errors.As(err, &policyErr) {
+				t.Fatalf("Check(%s) = %v, want *PolicyError", tt.path, err)
+			}
+			if policyErr.Kind != tt.wantKind {
+				t.Errorf("Check(%s) kind = %s, want %s", tt.path, policyErr.Kind, tt.wantKind)
+			}
+			if !errors.Is(err, ErrProtectedPath) {
+				t.Errorf("PolicyError should match ErrProtectedPath")
+			}
+		})
+	}
+}
+
+// TestPolicyStopsAtRoot verifies markers above the enclosing root are not read
+func TestPolicyStopsAtRoot(t *testing.T) {
+	outer := t.TempDir()
+	root := filepath.Join(outer, "scratch")
+	target := filepath.Join(root, "old.log")
+	if err := os.MkdirAll(root, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{target, filepath.Join(outer, ".keep")} {
+		if err := os.WriteFile(p, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	policy := &Policy{MarkerFile: ".keep"}
+	if err := policy.Check(target, root); err != nil {
+		t.Errorf("marker above the root protected %s: %v", target, err)
+	}
+	if err := policy.Check(target, ""); !errors.Is(err, ErrProtectedPath) {
+		t.Errorf("Check without a root = %v, want the marker above to protect", err)
+	}
+
+	// A marker in the root itself still protects everything below it
+	if err := os.WriteFile(filepath.Join(root, ".keep"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := policy.Check(target, root); !errors.Is(err, ErrProtectedPath) {
+		t.Errorf("marker in the root = %v, want it to protect", err)
+	}
+}
+
+// TestValidatorAppliesPolicy verifies ValidateDeleteTarget consults the policy
+func TestValidatorAppliesPolicy(t *testing.T) {
+	root := t.TempDir()
+	target := filepath.Join(root, "data", "file.txt")
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	validator := NewValidator([]string{root}, nil)
+	if err := validator.ValidateDeleteTarget(target); err != nil {
+		t.Fatalf("unexpected error without policy: %v", err)
+	}
+
+	validator.SetPolicy(&Policy{MarkerFile: ".keep"})
+	if err := os.WriteFile(filepath.Join(root, "data", ".keep"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := validator.ValidateDeleteTarget(target); !errors.Is(err, ErrProtectedPath) {
+		t.Fatalf("expected protected path error, got %v", err)
+	}
+}
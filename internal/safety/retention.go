@@ -0,0 +1,98 @@
+package safety
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrRetentionFloor is wrapped by every *RetentionError
+var ErrRetentionFloor = errors.New("within retention floor")
+
+// RetentionFloor forbids deleting anything below Root younger than MinAge
+type RetentionFloor struct {
+	Root       string
+	MinAge     time.Duration
+	Compliance bool // Age counts from the later of mtime and ctime; unknown ages are denied
+}
+
+// RetentionError reports a delete denied by a retention floor.
+// errors.Is(err, ErrRetentionFloor) is true for every RetentionError.
+type RetentionError struct {
+	Path   string
+	Root   string        // Rule root whose floor applies
+	Age    time.Duration // Age of the target; 0 when it could not be determined
+	MinAge time.Duration
+	Detail string // Set when the age could not be determined
+}
+
+func (e *RetentionError) Error() string {
+	if e.Detail != "" {
+		return fmt.Sprintf("%s of %s: %s", ErrRetentionFloor, e.Root, e.Detail)
+	}
+	return fmt.Sprintf("%s of %s: age %s is below %s", ErrRetentionFloor, e.Root, e.Age.Round(time.Minute), e.MinAge)
+}
+
+func (e *RetentionError) Unwrap() error {
+	return ErrRetentionFloor
+}
+
+// Reason returns the short form used in SKIP records
+func (e *RetentionError) Reason() string {
+	return "retention_floor"
+}
+
+// SetRetention sets the retention floor of every rule root. The floor of the
+// deepest root containing a target applies, so a root without a floor must
+// be listed with a zero MinAge to lift the floor of a root above it.
+func (v *Validator) SetRetention(floors []RetentionFloor) {
+	v.Retention = make([]RetentionFloor, 0, len(floors))
+	for _, f := range floors {
+		f.Root = filepath.Clean(f.Root)
+		v.Retention = append(v.Retention, f)
+	}
+}
+
+// RetentionAge returns how long ago the entry described by info last
+// changed, as measured for a retention floor. In compliance mode a backdated
+// mtime does not make a file older: the later of mtime and ctime counts.
+func RetentionAge(info os.FileInfo, compliance bool, now time.Time) time.Duration {
+	changed := info.ModTime()
+	if compliance {
+		if ctime, ok := changeTime(info); ok && ctime.After(changed) {
+			changed = ctime
+		}
+	}
+	return now.Sub(changed)
+}
+
+// checkRetention returns a *RetentionError if path is younger than the floor that covers it
+func (v *Validator) checkRetention(path string) error {
+	var floor *RetentionFloor
+	for i := range v.Retention {
+		f := &v.Retention[i]
+		if hasPathPrefix(path, f.Root) && (floor == nil || len(f.Root) > len(floor.Root)) {
+			floor = f
+		}
+	}
+	if floor == nil || floor.MinAge <= 0 {
+		return nil
+	}
+
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		if floor.Compliance {
+			return &RetentionError{Path: path, Root: floor.Root, MinAge: floor.MinAge, Detail: "cannot determine age: " + err.Error()}
+		}
+		return nil
+	}
+	if age := RetentionAge(info, floor.Compliance, time.Now()); age < floor.MinAge {
+		return &RetentionError{Path: path, Root: floor.Root, Age: age, MinAge: floor.MinAge}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package safety
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// TestRetentionFloor verifies the deepest root's floor decides and that
+// compliance mode does not trust a backdated mtime
+func TestRetentionFloor(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "scratch")
+	if err := os.Mkdir(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-60 * 24 * time.Hour)
+	write := func(path string, mtime time.Time) string {
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	young := write(filepath.Join(root, "young.log"), time.Now().Add(-time.Hour))
+	aged := write(filepath.Join(root, "aged.log"), old)
+	scratch := write(filepath.Join(nested, "young.tmp"), time.Now())
+
+	tests := []struct {
+		name       string
+		compliance bool
+		path       string
+		wantDenied bool
+	}{
+		{"young file below the floor", false, young, true},
+		{"old file", false, aged, false},
+		{"nested root without a floor", false, scratch, false},
+		{"missing file", true, filepath.Join(root, "gone.log"), false},
+		// aged.log was just written and backdated, so its ctime is recent
+		{"backdated mtime in compliance mode", true, aged, runtime.GOOS == "linux"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator([]string{root}, nil)
+			v.SetRetention([]RetentionFloor{
+				{Root: root, MinAge: 30 * 24 * time.Hour, Compliance: tt.compliance},
+				{Root: nested},
+			})
+			err := v.ValidateDeleteTarget(tt.path)
+			if denied := errors.Is(err, ErrRetentionFloor); denied != tt.wantDenied {
+				t.Fatalf("ValidateDeleteTarget(%s) = %v, want denied=%v", filepath.Base(tt.path), err, tt.wantDenied)
+			}
+			var floorErr *RetentionError
+			if tt.wantDenied && (!errors.As(err, &floorErr) || floorErr.Reason() != "retention_floor" || floorErr.Root != root) {
+				t.Errorf("error = %#v, want a retention_floor RetentionError for %s", err, root)
+			}
+		})
+	}
+}
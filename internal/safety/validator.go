@@ -13,12 +13,21 @@ var (
 	ErrOutsideAllowed = errors.New("outside allowed roots")
 	ErrTraversal      = errors.New("path traversal detected")
 	ErrSymlinkEscape  = errors.New("symlink escape detected")
+	// ErrSymlinkAncestor means a directory between the rule root and the target
+	// was replaced by a symlink after validation
+	ErrSymlinkAncestor = errors.New("symlink in ancestor path")
+	// ErrRootUnavailable means the rule root enclosing the target could not be
+	// opened, so the deleter refuses to touch anything below it
+	ErrRootUnavailable = errors.New("rule root unavailable")
 )
 
 // Validator enforces the safety contract for all delete operations
 type Validator struct {
 	AllowedRoots   []string
 	ProtectedPaths []string
+	Policy         *Policy          // Optional declarative protection rules (nil = built-in paths only)
+	Retention      []RetentionFloor // Minimum ages per rule root (nil = no floors)
+	Holds          []LegalHold      // Legal holds in force (nil = none)
 }
 
 // NewValidator creates a validator with allowed roots and optional additional protected paths
@@ -67,7 +76,29 @@ func (v *Validator) ValidateDeleteTarget(path string) error {
 		return ErrSymlinkEscape
 	}
 
-	return nil
+	// 6. Honor legal holds
+	if err := v.checkHolds(p); err != nil {
+		return err
+	}
+
+	// 7. Enforce retention floors, which no cleanup mode may cross
+	if err := v.checkRetention(p); err != nil {
+		return err
+	}
+
+	// 8. Apply declarative protection policy (markers, xattrs, owners, ...)
+	return v.Policy.Check(p, v.enclosingRoot(p))
+}
+
+// enclosingRoot returns the deepest allowed root containing path
+func (v *Validator) enclosingRoot(path string) string {
+	var root string
+	for _, r := range v.AllowedRoots {
+		if hasPathPrefix(path, r) && len(r) > len(root) {
+			root = r
+		}
+	}
+	return root
 }
 
 // NormalizePath converts path to absolute, cleaned form
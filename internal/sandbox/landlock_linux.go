@@ -0,0 +1,176 @@
+//go:build linux
+
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	accessRead = unix.LANDLOCK_ACCESS_FS_READ_FILE | unix.LANDLOCK_ACCESS_FS_READ_DIR
+
+	accessWrite = accessRead |
+		unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_MAKE_REG |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_FILE |
+		unix.LANDLOCK_ACCESS_FS_TRUNCATE
+
+	accessRemove = accessWrite | unix.LANDLOCK_ACCESS_FS_REMOVE_DIR
+)
+
+// handledAccess returns every filesystem right the given ABI version knows
+// about; anything handled but not granted by a rule is denied.
+func handledAccess(abi int) uint64 {
+	access := uint64(unix.LANDLOCK_ACCESS_FS_EXECUTE |
+		unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_READ_FILE |
+		unix.LANDLOCK_ACCESS_FS_READ_DIR |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_DIR |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_FILE |
+		unix.LANDLOCK_ACCESS_FS_MAKE_CHAR |
+		unix.LANDLOCK_ACCESS_FS_MAKE_DIR |
+		unix.LANDLOCK_ACCESS_FS_MAKE_REG |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SOCK |
+		unix.LANDLOCK_ACCESS_FS_MAKE_FIFO |
+		unix.LANDLOCK_ACCESS_FS_MAKE_BLOCK |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SYM)
+	if abi >= 2 {
+		access |= unix.LANDLOCK_ACCESS_FS_REFER
+	}
+	if abi >= 3 {
+		access |= unix.LANDLOCK_ACCESS_FS_TRUNCATE
+	}
+	if abi >= 5 {
+		access |= unix.LANDLOCK_ACCESS_FS_IOCTL_DEV
+	}
+	return access
+}
+
+// abiVersion asks the kernel which Landlock ABI it supports
+func abiVersion() (int, error) {
+	v, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	if errno != 0 {
+		return 0, errno
+	}
+	return int(v), nil
+}
+
+// startWorker locks a goroutine to a fresh OS thread and restricts that
+// thread with the ruleset. It returns a nil worker if Landlock cannot be used.
+func startWorker(rules Rules) (*worker, Status) {
+	abi, err := abiVersion()
+	if err != nil {
+		msg := fmt.Sprintf("landlock unavailable: %v", err)
+		if errors.Is(err, unix.ENOSYS) || errors.Is(err, unix.EOPNOTSUPP) {
+			msg = "landlock not supported or disabled by this kernel"
+		}
+		return nil, Status{Error: msg}
+	}
+
+	w := &worker{reqs: make(chan func())}
+	ready := make(chan Status, 1)
+
+	go func() {
+		// Never unlocked: the restricted thread exits with this goroutine
+		runtime.LockOSThread()
+
+		status, err := restrictThread(abi, rules)
+		if err != nil {
+			status.Error = err.Error()
+			ready <- status
+			return
+		}
+		ready <- status
+
+		for fn := range w.reqs {
+			fn()
+		}
+	}()
+
+	status := <-ready
+	if !status.Enforced {
+		return nil, status
+	}
+	return w, status
+}
+
+// restrictThread builds the ruleset and applies it to the calling thread
+func restrictThread(abi int, rules Rules) (Status, error) {
+	status := Status{ABI: abi, Scope: "deletion worker thread", Roots: rules.RemoveRoots}
+
+	handled := handledAccess(abi)
+	attr := unix.LandlockRulesetAttr{Access_fs: handled}
+	// Only handled_access_fs is used, so pass its size for compatibility with ABI < 4
+	fd, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET,
+		uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr.Access_fs), 0)
+	if errno != 0 {
+		return status, fmt.Errorf("landlock_create_ruleset: %w", errno)
+	}
+	rulesetFd := int(fd)
+	defer func() { _ = unix.Close(rulesetFd) }()
+
+	add := func(paths []string, access uint64) error {
+		for _, path := range paths {
+			if err := addPathRule(rulesetFd, path, access&handled); err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					status.Skipped = append(status.Skipped, path)
+					continue
+				}
+				return err
+			}
+		}
+		return nil
+	}
+	if err := add(rules.RemoveRoots, accessRemove); err != nil {
+		return status, err
+	}
+	if err := add(rules.ReadPaths, accessRead); err != nil {
+		return status, err
+	}
+	if err := add(rules.WritePaths, accessWrite); err != nil {
+		return status, err
+	}
+
+	// Required for unprivileged use and harmless as root; per-thread like the ruleset
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return status, fmt.Errorf("prctl(PR_SET_NO_NEW_PRIVS): %w", err)
+	}
+	if _, _, errno := unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, uintptr(rulesetFd), 0, 0); errno != 0 {
+		return status, fmt.Errorf("landlock_restrict_self: %w", errno)
+	}
+
+	status.Enforced = true
+	return status, nil
+}
+
+// addPathRule grants access beneath path
+func addPathRule(rulesetFd int, path string, access uint64) error {
+	fd, err := unix.Open(path, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return &os.PathError{Op: "open", Path: path, Err: err}
+	}
+	defer func() { _ = unix.Close(fd) }()
+
+	var st unix.Stat_t
+	if err := unix.Fstat(fd, &st); err != nil {
+		return &os.PathError{Op: "fstat", Path: path, Err: err}
+	}
+	// Directory-only rights cannot be granted on a file
+	if st.Mode&unix.S_IFMT != unix.S_IFDIR {
+		access &^= unix.LANDLOCK_ACCESS_FS_READ_DIR | unix.LANDLOCK_ACCESS_FS_REMOVE_DIR |
+			unix.LANDLOCK_ACCESS_FS_REMOVE_FILE | unix.LANDLOCK_ACCESS_FS_MAKE_REG
+	}
+
+	attr := unix.LandlockPathBeneathAttr{Allowed_access: access, Parent_fd: int32(fd)}
+	if _, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, uintptr(rulesetFd),
+		unix.LANDLOCK_RULE_PATH_BENEATH, uintptr(unsafe.Pointer(&attr)), 0, 0, 0); errno != 0 {
+		return fmt.Errorf("landlock_add_rule(%s): %w", path, errno)
+	}
+	return nil
+}
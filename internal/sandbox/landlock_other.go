@@ -0,0 +1,8 @@
+//go:build !linux
+
+package sandbox
+
+// startWorker reports that Landlock is unavailable on this platform
+func startWorker(rules Rules) (*worker, Status) {
+	return nil, Status{Error: "landlock is only available on Linux"}
+}
@@ -0,0 +1,195 @@
+// Package sandbox confines the daemon's filesystem changes with a Linux
+// Landlock ruleset, so a path-handling bug fails closed in the kernel
+// instead of deleting outside the configured roots.
+//
+// Landlock domains apply per thread, and the daemon links cgo (SQLite), so
+// the Go runtime cannot restrict every thread at once. Instead the ruleset
+// is applied to a dedicated, locked OS thread and every delete, in-place
+// compression and truncation issued by the cleaner is executed on that thread.
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"storage-sage/internal/config"
+	"storage-sage/internal/fsops"
+)
+
+// Rules lists the paths the sandboxed thread may touch
+type Rules struct {
+	RemoveRoots []string // read, write and remove beneath these (cleanup roots)
+	ReadPaths   []string // read-only (config file, /proc)
+	WritePaths  []string // read and write, including creating and removing files (database, logs, index)
+}
+
+// Status describes the ruleset currently in force
+type Status struct {
+	Enabled  bool     `json:"enabled"`
+	Enforced bool     `json:"enforced"`
+	ABI      int      `json:"abi,omitempty"`   // Landlock ABI version reported by the kernel
+	Scope    string   `json:"scope,omitempty"` // What the ruleset confines
+	Roots    []string `json:"roots,omitempty"`
+	Skipped  []string `json:"skipped,omitempty"` // Rule paths that did not exist when applied
+	Error    string   `json:"error,omitempty"`
+}
+
+// Sandbox owns the restricted worker thread. A nil *Sandbox is valid and
+// leaves deleters unwrapped.
+type Sandbox struct {
+	mu     sync.RWMutex
+	worker *worker
+	status Status
+}
+
+// New creates a sandbox with no ruleset applied yet
+func New() *Sandbox {
+	return &Sandbox{}
+}
+
+// RulesFromConfig grants remove rights beneath every configured root, read
+// rights for the config file and /proc, and write rights for the database,
+// index and log directories.
+func RulesFromConfig(cfg *config.Config, configPath, logDir string) Rules {
+	rules := Rules{
+		ReadPaths: []string{configPath, "/proc"},
+	}
+	rules.RemoveRoots = append(rules.RemoveRoots, cfg.ScanPaths...)
+	for _, rule := range cfg.Paths {
+		rules.RemoveRoots = append(rules.RemoveRoots, rule.Path)
+	}
+	if cfg.DatabasePath != "" {
+		rules.WritePaths = append(rules.WritePaths, filepath.Dir(cfg.DatabasePath))
+	}
+	if cfg.ScanOptimizations.IndexPath != "" {
+		rules.WritePaths = append(rules.WritePaths, filepath.Dir(cfg.ScanOptimizations.IndexPath))
+	}
+	if logDir != "" {
+		rules.WritePaths = append(rules.WritePaths, logDir)
+	}
+	if cfg.Protection.PolicyFile != "" {
+		rules.ReadPaths = append(rules.ReadPaths, cfg.Protection.PolicyFile)
+	}
+	return rules
+}
+
+// Apply starts a new restricted worker for rules and retires the previous one.
+// Landlock can only tighten an existing domain, so a reload that adds roots
+// needs a fresh thread. When Landlock is unavailable the sandbox degrades to
+// running unconfined and reports why in the returned status.
+func (s *Sandbox) Apply(rules Rules) Status {
+	w, status := startWorker(rules)
+	status.Enabled = true
+
+	s.mu.Lock()
+	old := s.worker
+	s.worker = w
+	s.status = status
+	s.mu.Unlock()
+
+	if old != nil {
+		old.stop()
+	}
+	return status
+}
+
+// Status returns the ruleset currently in force
+func (s *Sandbox) Status() Status {
+	if s == nil {
+		return Status{}
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.status
+}
+
+// Close stops the worker thread
+func (s *Sandbox) Close() {
+	s.mu.Lock()
+	old := s.worker
+	s.worker = nil
+	s.mu.Unlock()
+	if old != nil {
+		old.stop()
+	}
+}
+
+// Wrap returns a deleter whose operations, compression and truncation included, run on the restricted thread
+func (s *Sandbox) Wrap(d fsops.Deleter) fsops.Deleter {
+	if s == nil {
+		return d
+	}
+	return &sandboxedDeleter{sandbox: s, inner: d}
+}
+
+// run executes fn on the current worker, or directly when none is enforced
+func (s *Sandbox) run(fn func() error) error {
+	s.mu.RLock()
+	w := s.worker
+	s.mu.RUnlock()
+	if w == nil {
+		return fn()
+	}
+	return w.run(fn)
+}
+
+type sandboxedDeleter struct {
+	sandbox *Sandbox
+	inner   fsops.Deleter
+}
+
+func (d *sandboxedDeleter) Remove(path string) error {
+	return d.sandbox.run(func() error { return d.inner.Remove(path) })
+}
+
+func (d *sandboxedDeleter) RemoveAll(path string) error {
+	return d.sandbox.run(func() error { return d.inner.RemoveAll(path) })
+}
+
+// Compress compresses through the inner deleter, failing closed when it cannot
+func (d *sandboxedDeleter) Compress(path, format string, level int) (dst string, size int64, err error) {
+	z, ok := d.inner.(fsops.Compressor)
+	if !ok {
+		return "", 0, fmt.Errorf("%w: %T cannot compress", errors.ErrUnsupported, d.inner)
+	}
+	err = d.sandbox.run(func() error {
+		var compressErr error
+		dst, size, compressErr = z.Compress(path, format, level)
+		return compressErr
+	})
+	return dst, size, err
+}
+
+// Truncate truncates through the inner deleter, failing closed when it cannot
+func (d *sandboxedDeleter) Truncate(path string, id fsops.FileID, keepTail int64) (before, after int64, err error) {
+	t, ok := d.inner.(fsops.Truncator)
+	if !ok {
+		return 0, 0, fmt.Errorf("%w: %T cannot truncate", errors.ErrUnsupported, d.inner)
+	}
+	err = d.sandbox.run(func() error {
+		var truncErr error
+		before, after, truncErr = t.Truncate(path, id, keepTail)
+		return truncErr
+	})
+	return before, after, err
+}
+
+// worker serialises calls onto one locked OS thread
+type worker struct {
+	reqs chan func()
+	once sync.Once
+}
+
+func (w *worker) run(fn func() error) error {
+	done := make(chan error, 1)
+	w.reqs <- func() { done <- fn() }
+	return <-done
+}
+
+// stop lets queued calls finish, then ends the goroutine. The thread is never
+// unlocked, so the runtime discards it instead of reusing a restricted thread.
+func (w *worker) stop() {
+	w.once.Do(func() { close(w.reqs) })
+}
@@ -0,0 +1,143 @@
+package sandbox
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"storage-sage/internal/fsops"
+)
+
+// TestSandboxConfinesDeletes verifies the restricted thread can delete inside
+// a root and is refused outside it. Skipped when the kernel lacks Landlock.
+func TestSandboxConfinesDeletes(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+
+	inside := filepath.Join(root, "old.log")
+	victim := filepath.Join(outside, "keep.txt")
+	for _, p := range []string{inside, victim} {
+		if err := os.WriteFile(p, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sb := New()
+	defer sb.Close()
+	status := sb.Apply(Rules{RemoveRoots: []string{root, filepath.Join(root, "missing")}})
+	if !status.Enforced {
+		t.Skipf("landlock not enforced: %s", status.Error)
+	}
+	if len(status.Skipped) != 1 {
+		t.Errorf("expected the missing root to be reported as skipped, got %v", status.Skipped)
+	}
+
+	deleter := sb.Wrap(fsops.OSDeleter{})
+	if err := deleter.Remove(inside); err != nil {
+		t.Fatalf("delete inside root: %v", err)
+	}
+	if err := deleter.Remove(victim); !errors.Is(err, os.ErrPermission) {
+		t.Fatalf("delete outside root = %v, want permission denied", err)
+	}
+	if _, err := os.Stat(victim); err != nil {
+		t.Fatalf("file outside root was removed: %v", err)
+	}
+
+	// A reload with a wider ruleset takes effect on a fresh thread
+	if status := sb.Apply(Rules{RemoveRoots: []string{root, outside}}); !status.Enforced {
+		t.Fatalf("re-apply failed: %s", status.Error)
+	}
+	if err := deleter.Remove(victim); err != nil {
+		t.Fatalf("delete after widening ruleset: %v", err)
+	}
+}
+
+// TestSandboxConfinesCompression verifies in-place compression runs on the
+// restricted thread, and fails closed for a deleter that cannot compress
+func TestSandboxConfinesCompression(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	inside := filepath.Join(root, "old.log")
+	victim := filepath.Join(outside, "keep.log")
+	for _, p := range []string{inside, victim} {
+		if err := os.WriteFile(p, []byte("log line\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sb := New()
+	defer sb.Close()
+	if status := sb.Apply(Rules{RemoveRoots: []string{root}}); !status.Enforced {
+		t.Skipf("landlock not enforced: %s", status.Error)
+	}
+
+	z, ok := sb.Wrap(fsops.OSDeleter{}).(fsops.Compressor)
+	if !ok {
+		t.Fatal("sandboxed deleter cannot compress")
+	}
+	if _, _, err := z.Compress(inside, fsops.FormatGzip, 6); err != nil {
+		t.Fatalf("compress inside root: %v", err)
+	}
+	if _, _, err := z.Compress(victim, fsops.FormatGzip, 6); !errors.Is(err, os.ErrPermission) {
+		t.Fatalf("compress outside root = %v, want permission denied", err)
+	}
+	if _, err := os.Lstat(victim + fsops.GzipExt); !os.IsNotExist(err) {
+		t.Fatalf("compressed copy written outside root: %v", err)
+	}
+
+	fake := sb.Wrap(&fsops.FakeDeleter{}).(fsops.Compressor)
+	if _, _, err := fake.Compress(inside, fsops.FormatGzip, 6); !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("compress through a deleter without Compress = %v, want ErrUnsupported", err)
+	}
+}
+
+// TestSandboxConfinesTruncation verifies in-place truncation runs on the
+// restricted thread, and fails closed for a deleter that cannot truncate
+func TestSandboxConfinesTruncation(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	inside := filepath.Join(root, "big.log")
+	victim := filepath.Join(outside, "keep.log")
+	for _, p := range []string{inside, victim} {
+		if err := os.WriteFile(p, []byte("0123456789"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sb := New()
+	defer sb.Close()
+	if status := sb.Apply(Rules{RemoveRoots: []string{root}}); !status.Enforced {
+		t.Skipf("landlock not enforced: %s", status.Error)
+	}
+
+	tr, ok := sb.Wrap(fsops.OSDeleter{}).(fsops.Truncator)
+	if !ok {
+		t.Fatal("sandboxed deleter cannot truncate")
+	}
+	if _, after, err := tr.Truncate(inside, fsops.FileID{}, 4); err != nil || after != 4 {
+		t.Fatalf("truncate inside root = %d, %v; want 4", after, err)
+	}
+	if _, _, err := tr.Truncate(victim, fsops.FileID{}, 4); !errors.Is(err, os.ErrPermission) {
+		t.Fatalf("truncate outside root = %v, want permission denied", err)
+	}
+	if data, err := os.ReadFile(victim); err != nil || string(data) != "0123456789" {
+		t.Fatalf("file outside root = %q, %v; want it untouched", data, err)
+	}
+
+	fake := sb.Wrap(&fsops.FakeDeleter{}).(fsops.Truncator)
+	if _, _, err := fake.Truncate(inside, fsops.FileID{}, 4); !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("truncate through a deleter without Truncate = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestNilSandboxLeavesDeleterUnwrapped(t *testing.T) {
+	var sb *Sandbox
+	d := fsops.OSDeleter{}
+	if got := sb.Wrap(d); got != fsops.Deleter(d) {
+		t.Fatalf("nil sandbox wrapped the deleter")
+	}
+	if sb.Status().Enabled {
+		t.Fatalf("nil sandbox reports enabled")
+	}
+}
@@ -0,0 +1,32 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"storage-sage/internal/config"
+)
+
+// fileAge returns the time rule counts the age of an entry from and the
+// source it came from; an entry its age_source cannot date counts from mtime
+func fileAge(rule *config.PathRule, path string, info os.FileInfo) (time.Time, string) {
+	switch rule.AgeSource {
+	case config.AgeSourceCtime, config.AgeSourceAtime:
+		if t, ok := statTime(info, rule.AgeSource); ok {
+			return t, rule.AgeSource
+		}
+	case config.AgeSourceFilename:
+		if t, ok := rule.AgeFromName(filepath.Base(path)); ok {
+			return t, config.AgeSourceFilename
+		}
+	}
+	return info.ModTime(), config.AgeSourceMtime
+}
+
+// recordAgeSource notes on the age reason which timestamp its age was measured from
+func recordAgeSource(reason *DeletionReason, source string) {
+	if reason.AgeThreshold != nil {
+		reason.AgeThreshold.Source = source
+	}
+}
@@ -0,0 +1,23 @@
+//go:build linux
+
+package scan
+
+import (
+	"os"
+	"syscall"
+	"time"
+
+	"storage-sage/internal/config"
+)
+
+// statTime returns the access or inode change time recorded in info
+func statTime(info os.FileInfo, source string) (time.Time, bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, false
+	}
+	if source == config.AgeSourceAtime {
+		return time.Unix(int64(st.Atim.Sec), int64(st.Atim.Nsec)), true
+	}
+	return time.Unix(int64(st.Ctim.Sec), int64(st.Ctim.Nsec)), true
+}
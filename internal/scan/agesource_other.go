@@ -0,0 +1,13 @@
+//go:build !linux
+
+package scan
+
+import (
+	"os"
+	"time"
+)
+
+// statTime is only implemented on Linux
+func statTime(info os.FileInfo, source string) (time.Time, bool) {
+	return time.Time{}, false
+}
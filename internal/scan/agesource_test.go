@@ -0,0 +1,80 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"storage-sage/internal/config"
+)
+
+// TestScanAgeFromFilename verifies a restored file whose name carries an old
+// date is selected by that date, and a name without one falls back to mtime
+func TestScanAgeFromFilename(t *testing.T) {
+	root := t.TempDir()
+	old := time.Now().AddDate(0, 0, -90)
+	dated := filepath.Join(root, "app-"+old.Format("2006-01-02")+".log")
+	undated := filepath.Join(root, "app.log")
+	writeFiles(t, map[string]string{dated: "restored", undated: "current"})
+
+	rule := &config.PathRule{Path: root, AgeOffDays: 30, MaxFreePercent: 100, StackThreshold: 100,
+		AgeSource: config.AgeSourceFilename, AgeLayout: "2006-01-02"}
+	s := NewScanner(nil)
+	candidates, err := s.scanPath(rule, 10, time.Now())
+	if err != nil {
+		t.Fatalf("scanPath: %v", err)
+	}
+	if len(candidates) != 1 || candidates[0].Path != dated {
+		t.Fatalf("candidates %+v, want only %s", candidates, dated)
+	}
+	c := candidates[0]
+	if c.AgeSource != config.AgeSourceFilename || c.DeletionReason.AgeThreshold.Source != config.AgeSourceFilename {
+		t.Errorf("age source %q / %q, want filename", c.AgeSource, c.DeletionReason.AgeThreshold.Source)
+	}
+	if got := c.DeletionReason.AgeThreshold.ActualAgeDays; got < 89 || got > 90 {
+		t.Errorf("ActualAgeDays = %d, want 90", got)
+	}
+
+	// The recheck before delete ages the file the same way
+	if r := Reevaluate(c, rule, 10, time.Now()); r.AgeThreshold == nil || r.AgeThreshold.Source != config.AgeSourceFilename {
+		t.Errorf("Reevaluate = %s, want age_threshold from filename", r.ToLogString())
+	}
+}
+
+// TestScanAgeFromAtime verifies an atime-aged file is selected by its last
+// access, and that reading it after the scan counts as a change
+func TestScanAgeFromAtime(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("atime is only read on Linux")
+	}
+	root := t.TempDir()
+	path := filepath.Join(root, "cache.bin")
+	writeFiles(t, map[string]string{path: "data"})
+	accessed := time.Now().AddDate(0, 0, -60)
+	if err := os.Chtimes(path, accessed, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	rule := &config.PathRule{Path: root, AgeOffDays: 30, MaxFreePercent: 100, StackThreshold: 100, AgeSource: config.AgeSourceAtime}
+	s := NewScanner(nil)
+	candidates, err := s.scanPath(rule, 10, time.Now())
+	if err != nil {
+		t.Fatalf("scanPath: %v", err)
+	}
+	if len(candidates) != 1 || candidates[0].AgeSource != config.AgeSourceAtime {
+		t.Fatalf("candidates %+v, want %s aged by atime", candidates, path)
+	}
+
+	if err := os.Chtimes(path, time.Now(), candidates[0].ModTime); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Lstat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if change := Changed(candidates[0], info); change == "" {
+		t.Error("Changed reported nothing after the file was accessed")
+	}
+}
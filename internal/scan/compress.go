@@ -0,0 +1,62 @@
+package scan
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"storage-sage/internal/config"
+)
+
+// CompressReason indicates file was selected for compression by the rule's
+// engaged compress tier. It is set alone: an entry any other reason selects is
+// deleted instead.
+type CompressReason struct {
+	Level         int           // 1-based position of the compress tier in the rule's ladder
+	UsagePercent  float64       // usage_percent of the tier
+	MinAgeDays    int           // min age of the tier, in whole days
+	ActualPercent float64       // actual disk usage at scan time
+	ActualAgeDays int           // actual file age at scan time, in whole days
+	MinAge        time.Duration // min age of the tier
+	Age           time.Duration // actual file age at scan time
+	Format        string        // config.FormatGzip
+	Held          bool          // usage is below the tier's threshold; hysteresis or cooldown keeps it engaged
+}
+
+// compressReason returns the reason the highest compress tier engaged at level
+// selects an entry of the given age, or nil
+func compressReason(rule *config.PathRule, level int, age time.Duration, diskUsage float64) *CompressReason {
+	i := rule.EngagedTier(level, config.ActionCompress)
+	if i == 0 {
+		return nil
+	}
+	t := rule.Tiers[i-1]
+	if age < t.MinAgeLimit() {
+		return nil
+	}
+	return &CompressReason{
+		Level:         i,
+		UsagePercent:  t.UsagePercent,
+		MinAgeDays:    wholeDays(t.MinAgeLimit()),
+		ActualPercent: diskUsage,
+		ActualAgeDays: wholeDays(age),
+		MinAge:        t.MinAgeLimit(),
+		Age:           age,
+		Format:        rule.CompressOptions().Format,
+		Held:          diskUsage < t.UsagePercent,
+	}
+}
+
+// compressible reports whether rule can compress an entry named name of the
+// given mode: only regular files without an already compressed extension
+func compressible(rule *config.PathRule, name string, mode os.FileMode) bool {
+	return mode.IsRegular() && !rule.CompressOptions().Skips(name)
+}
+
+// logString formats the reason for ToLogString.
+// Example: "compress_1: disk_usage=40.0% (threshold=0.0%), age=3d (min=1d), format=gzip"
+func (r *CompressReason) logString() string {
+	age, minAge := ageStrings(r.Age, r.MinAge, r.ActualAgeDays, r.MinAgeDays)
+	return fmt.Sprintf("compress_%d: disk_usage=%.1f%% (threshold=%.1f%%), age=%s (min=%s), format=%s",
+		r.Level, r.ActualPercent, r.UsagePercent, age, minAge, r.Format)
+}
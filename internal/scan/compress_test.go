@@ -0,0 +1,79 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"storage-sage/internal/config"
+)
+
+// TestScanCompressTier verifies a compress tier selects the regular files no
+// other reason deletes, and leaves compressed files and directories alone
+func TestScanCompressTier(t *testing.T) {
+	root := t.TempDir()
+	ages := map[string]time.Duration{
+		"app.log":          3 * config.Day,
+		"app.1.log.gz":     3 * config.Day,
+		"fresh.log":        time.Hour,
+		"ancient.log":      40 * config.Day,
+		"rotated/db.log":   3 * config.Day,
+		"rotated/dump.zst": 3 * config.Day,
+	}
+	files := make(map[string]string, len(ages))
+	for name := range ages {
+		files[filepath.Join(root, name)] = "log line\n"
+	}
+	writeFiles(t, files)
+	for name, age := range ages {
+		at := time.Now().Add(-age)
+		if err := os.Chtimes(filepath.Join(root, name), at, at); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dirAt := time.Now().Add(-3 * config.Day)
+	if err := os.Chtimes(filepath.Join(root, "rotated"), dirAt, dirAt); err != nil {
+		t.Fatal(err)
+	}
+
+	rule := &config.PathRule{
+		Path:   root,
+		MaxAge: config.Duration(30 * config.Day),
+		Tiers:  []config.Tier{{UsagePercent: 0, MinAge: config.Duration(config.Day), Action: config.ActionCompress}},
+	}
+	s := NewScanner(nil)
+	s.SetTierStates(map[string]TierState{root: {Level: 1, Since: time.Now()}})
+	candidates, err := s.scanPath(rule, 40, time.Now())
+	if err != nil {
+		t.Fatalf("scanPath: %v", err)
+	}
+
+	got := make(map[string]DeletionReason)
+	for _, c := range candidates {
+		rel, _ := filepath.Rel(root, c.Path)
+		got[rel] = c.DeletionReason
+	}
+	if len(got) != 3 {
+		t.Fatalf("selected %v, want app.log, rotated/db.log and ancient.log", got)
+	}
+	for _, name := range []string{"app.log", "rotated/db.log"} {
+		r := got[name]
+		if r.Compress == nil || r.Compress.Level != 1 || r.Compress.Format != config.FormatGzip || r.GetPrimaryReason() != "compress" {
+			t.Errorf("%s: reason %s, want compress_1 with gzip", name, r.ToLogString())
+		}
+	}
+	if r := got["ancient.log"]; r.AgeThreshold == nil || r.Compress != nil {
+		t.Errorf("ancient.log: reason %s, want age_threshold alone", r.ToLogString())
+	}
+
+	// The reason still holds right before compression, whatever the disk usage
+	for _, c := range candidates {
+		if c.DeletionReason.Compress == nil {
+			continue
+		}
+		if r := Reevaluate(c, rule, 10, time.Now()); r.Compress == nil {
+			t.Errorf("Reevaluate(%s) = %s, want compress", c.Path, r.ToLogString())
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package scan
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"syscall"
+)
+
+// Kinds of scan errors, used in metrics and the run record
+const (
+	ScanErrPermission = "permission_denied"
+	ScanErrIO         = "io_error"
+	ScanErrStale      = "stale_handle"
+	ScanErrOther      = "other"
+)
+
+// ScanErrorKinds lists every kind so metrics can report zeros
+var ScanErrorKinds = []string{ScanErrPermission, ScanErrIO, ScanErrStale, ScanErrOther}
+
+// maxScanErrors caps the entries kept per rule; counts and bytes cover all of them
+const maxScanErrors = 1000
+
+// ScanError is an entry below a rule root that the scanner could not read
+type ScanError struct {
+	PathRule       string `json:"path_rule"`
+	Path           string `json:"path"`
+	Kind           string `json:"kind"`
+	Error          string `json:"error"`
+	IsDir          bool   `json:"is_dir"`
+	EstimatedBytes int64  `json:"estimated_bytes"` // Bytes last indexed below an unreadable directory, 0 when unknown
+}
+
+// ScanErrorReport summarizes what the scanner could not read under one rule root
+type ScanErrorReport struct {
+	PathRule     string
+	Counts       map[string]int // Errors by kind
+	SkippedBytes int64          // Estimated bytes below unreadable directories
+	Errors       []ScanError    // The first maxScanErrors entries
+}
+
+// Total returns the number of entries that could not be read
+func (r *ScanErrorReport) Total() int {
+	n := 0
+	for _, c := range r.Counts {
+		n += c
+	}
+	return n
+}
+
+// scanErrorKind classifies a walk error
+func scanErrorKind(err error) string {
+	switch {
+	case os.IsPermission(err):
+		return ScanErrPermission
+	case errors.Is(err, syscall.ESTALE):
+		return ScanErrStale
+	case errors.Is(err, syscall.EIO):
+		return ScanErrIO
+	}
+	return ScanErrOther
+}
+
+// add records one unreadable entry
+func (r *ScanErrorReport) add(e ScanError) {
+	r.Counts[e.Kind]++
+	r.SkippedBytes += e.EstimatedBytes
+	if len(r.Errors) < maxScanErrors {
+		r.Errors = append(r.Errors, e)
+	}
+}
+
+// ScanErrors returns what the last Scan could not read, one report per rule
+// root that was walked, ordered by root
+func (s *Scanner) ScanErrors() []*ScanErrorReport {
+	reports := make([]*ScanErrorReport, 0, len(s.scanErrors))
+	for _, r := range s.scanErrors {
+		reports = append(reports, r)
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].PathRule < reports[j].PathRule })
+	return reports
+}
+
+// Targets returns the bytes each rule root had to free to reach its target
+// disk usage at the last Scan; roots below their threshold are absent
+func (s *Scanner) Targets() map[string]int64 {
+	return s.targets
+}
@@ -0,0 +1,107 @@
+package scan
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"storage-sage/internal/config"
+	"storage-sage/internal/index"
+)
+
+func TestScanErrorKind(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{&fs.PathError{Op: "open", Path: "/x", Err: syscall.EACCES}, ScanErrPermission},
+		{&fs.PathError{Op: "open", Path: "/x", Err: syscall.EPERM}, ScanErrPermission},
+		{&fs.PathError{Op: "lstat", Path: "/x", Err: syscall.ESTALE}, ScanErrStale},
+		{fmt.Errorf("readdirent: %w", syscall.EIO), ScanErrIO},
+		{&fs.PathError{Op: "lstat", Path: "/x", Err: syscall.ENAMETOOLONG}, ScanErrOther},
+	}
+	for _, tt := range tests {
+		if got := scanErrorKind(tt.err); got != tt.want {
+			t.Errorf("scanErrorKind(%v) = %s, want %s", tt.err, got, tt.want)
+		}
+	}
+}
+
+// TestScanReportsUnreadableDirectories verifies an unreadable subtree is
+// reported with the bytes last indexed under it, and the walk carries on
+func TestScanReportsUnreadableDirectories(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("directory permissions do not apply to root")
+	}
+	root := t.TempDir()
+	locked := filepath.Join(root, "locked")
+	writeFiles(t, map[string]string{
+		filepath.Join(locked, "a.dat"):         "12345",
+		filepath.Join(locked, "deep", "b.dat"): "1234567890",
+		filepath.Join(root, "open.dat"):        "x",
+	})
+
+	idx, err := index.Open(filepath.Join(t.TempDir(), "idx.gob"))
+	if err != nil {
+		t.Fatalf("index.Open: %v", err)
+	}
+	// Disk pressure selects every file and disables index skipping, so every directory is read
+	rule := &config.PathRule{Path: root, MaxFreePercent: 90, StackThreshold: 100}
+	s := NewScanner(nil)
+	s.SetIndex(idx, 24*time.Hour)
+	s.scanErrors = make(map[string]*ScanErrorReport)
+
+	if _, err := s.scanPath(rule, 95, time.Now()); err != nil {
+		t.Fatalf("first scan: %v", err)
+	}
+	if n := s.scanErrors[root].Total(); n != 0 {
+		t.Fatalf("readable tree reported %d errors", n)
+	}
+
+	if err := os.Chmod(locked, 0); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chmod(locked, 0o755) }()
+
+	candidates, err := s.scanPath(rule, 95, time.Now())
+	if err != nil {
+		t.Fatalf("second scan: %v", err)
+	}
+	report := s.scanErrors[root]
+	if report.Counts[ScanErrPermission] != 1 || report.Total() != 1 {
+		t.Errorf("counts = %v, want one %s", report.Counts, ScanErrPermission)
+	}
+	if report.SkippedBytes != 15 {
+		t.Errorf("skipped bytes estimate = %d, want 15", report.SkippedBytes)
+	}
+	if len(report.Errors) != 1 || report.Errors[0].Path != locked || !report.Errors[0].IsDir {
+		t.Errorf("errors = %+v, want %s", report.Errors, locked)
+	}
+	if _, ok := idx.Lookup(filepath.Join(locked, "deep")); !ok {
+		t.Error("index entries below the unreadable directory were dropped")
+	}
+
+	found := false
+	for _, c := range candidates {
+		found = found || c.Path == filepath.Join(root, "open.dat")
+	}
+	if !found {
+		t.Error("walk stopped at the unreadable directory")
+	}
+}
+
+func writeFiles(t *testing.T, files map[string]string) {
+	t.Helper()
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package scan
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"storage-sage/internal/config"
+)
+
+// GFS retention periods, finest first
+const (
+	PeriodDaily   = "daily"
+	PeriodWeekly  = "weekly"
+	PeriodMonthly = "monthly"
+	PeriodYearly  = "yearly"
+)
+
+// GFSReason indicates file was displaced by GFS retention: a newer file is
+// kept for its bucket, or all of its buckets are older than the ones kept
+type GFSReason struct {
+	Period   string    // Finest period whose bucket of the file keeps a newer file; "" when no bucket of it is kept
+	Bucket   string    // That bucket, e.g. "2026-10-15", "2026-W42", "2026-10" or "2026"
+	KeptPath string    // The newer file kept for the bucket
+	Series   string    // Value of the pattern's series group, if any
+	FileDate time.Time // Date the file was bucketed by
+
+	Daily, Weekly, Monthly, Yearly int // Counts kept per period, from config
+}
+
+// gfsFile is a file a GFS rule manages, collected during the walk
+type gfsFile struct {
+	path   string
+	info   os.FileInfo
+	date   time.Time
+	series string
+}
+
+// gfsPeriods pairs each period with the number of buckets g keeps of it
+func gfsPeriods(g *config.GFS) []struct {
+	name  string
+	count int
+} {
+	return []struct {
+		name  string
+		count int
+	}{{PeriodDaily, g.Daily}, {PeriodWeekly, g.Weekly}, {PeriodMonthly, g.Monthly}, {PeriodYearly, g.Yearly}}
+}
+
+// gfsBucket returns the bucket of period that t falls in
+func gfsBucket(period string, t time.Time) string {
+	switch period {
+	case PeriodDaily:
+		return t.Format("2006-01-02")
+	case PeriodWeekly:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case PeriodMonthly:
+		return t.Format("2006-01")
+	default:
+		return t.Format("2006")
+	}
+}
+
+// gfsFileFor returns the managed file for an entry of rule's walk, or false
+// if GFS leaves the entry alone; directories are never managed
+func gfsFileFor(g *config.GFS, path string, info os.FileInfo) (gfsFile, bool) {
+	if !info.Mode().IsRegular() {
+		return gfsFile{}, false
+	}
+	series, date, dated, ok := g.Match(filepath.Base(path))
+	if !ok {
+		return gfsFile{}, false
+	}
+	if !dated {
+		date = info.ModTime()
+	}
+	return gfsFile{path: path, info: info, date: date.Local(), series: series}, true
+}
+
+// gfsSelect returns why each managed file GFS does not keep is displaced,
+// keyed by path. Each series is kept independently: walking from the newest
+// file, the first file seen in a bucket is kept until a period has kept its count.
+func gfsSelect(g *config.GFS, files []gfsFile) map[string]*GFSReason {
+	bySeries := make(map[string][]gfsFile)
+	for _, f := range files {
+		bySeries[f.series] = append(bySeries[f.series], f)
+	}
+
+	displaced := make(map[string]*GFSReason)
+	for series, group := range bySeries {
+		sort.Slice(group, func(i, j int) bool {
+			if !group[i].date.Equal(group[j].date) {
+				return group[i].date.After(group[j].date)
+			}
+			return group[i].path > group[j].path
+		})
+
+		kept := make(map[string]bool)
+		keepers := make(map[string]map[string]string) // period -> bucket -> kept path
+		for _, p := range gfsPeriods(g) {
+			keepers[p.name] = make(map[string]string)
+			last := ""
+			for _, f := range group {
+				if len(keepers[p.name]) >= p.count {
+					break
+				}
+				if b := gfsBucket(p.name, f.date); b != last {
+					last = b
+					keepers[p.name][b] = f.path
+					kept[f.path] = true
+				}
+			}
+		}
+
+		for _, f := range group {
+			if kept[f.path] {
+				continue
+			}
+			reason := &GFSReason{
+				Series: series, FileDate: f.date,
+				Daily: g.Daily, Weekly: g.Weekly, Monthly: g.Monthly, Yearly: g.Yearly,
+			}
+			for _, p := range gfsPeriods(g) {
+				b := gfsBucket(p.name, f.date)
+				if keeper, ok := keepers[p.name][b]; ok {
+					reason.Period, reason.Bucket, reason.KeptPath = p.name, b, keeper
+					break
+				}
+			}
+			displaced[f.path] = reason
+		}
+	}
+	return displaced
+}
+
+// logString formats the reason for ToLogString.
+// Example: "gfs: daily bucket 2026-10-15 kept /backups/db-20261015.tar (keep daily=7 weekly=4 monthly=12 yearly=0)"
+func (r *GFSReason) logString() string {
+	keep := fmt.Sprintf("(keep daily=%d weekly=%d monthly=%d yearly=%d)", r.Daily, r.Weekly, r.Monthly, r.Yearly)
+	series := ""
+	if r.Series != "" {
+		series = " series=" + r.Series
+	}
+	if r.Period == "" {
+		return fmt.Sprintf("gfs:%s dated %s, older than every kept bucket %s", series, r.FileDate.Format("2006-01-02"), keep)
+	}
+	return fmt.Sprintf("gfs:%s %s bucket %s kept %s %s", series, r.Period, r.Bucket, r.KeptPath, keep)
+}
@@ -0,0 +1,111 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"storage-sage/internal/config"
+)
+
+// TestGFSSelect verifies the newest file of each kept bucket survives and the
+// rest are displaced by the finest bucket that keeps a newer file
+func TestGFSSelect(t *testing.T) {
+	day := func(s string) time.Time {
+		d, err := time.ParseInLocation("2006-01-02 15:04", s, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	var files []gfsFile
+	for _, d := range []string{
+		"2026-10-15 23:00", "2026-10-15 11:00", // two backups on the newest day
+		"2026-10-14 23:00", "2026-10-13 23:00", "2026-10-12 23:00", // 10-12 is a Monday, 10-11 a Sunday
+		"2026-10-11 23:00", "2026-10-04 23:00", "2026-09-30 23:00", "2026-09-02 23:00", "2026-08-31 23:00",
+	} {
+		files = append(files, gfsFile{path: d, date: day(d)})
+	}
+
+	g := &config.GFS{Daily: 3, Weekly: 2, Monthly: 2}
+	displaced := gfsSelect(g, files)
+
+	var kept []string
+	for _, f := range files {
+		if displaced[f.path] == nil {
+			kept = append(kept, f.path)
+		}
+	}
+	sort.Strings(kept)
+	// daily: 10-15 23:00, 10-14, 10-13; weekly: 10-15 23:00 (W42), 10-11 (W41); monthly: 10-15 23:00, 09-30
+	want := []string{"2026-09-30 23:00", "2026-10-11 23:00", "2026-10-13 23:00", "2026-10-14 23:00", "2026-10-15 23:00"}
+	if len(kept) != len(want) {
+		t.Fatalf("kept %v, want %v", kept, want)
+	}
+	for i := range want {
+		if kept[i] != want[i] {
+			t.Fatalf("kept %v, want %v", kept, want)
+		}
+	}
+
+	tests := []struct {
+		path       string
+		wantPeriod string
+		wantKept   string
+	}{
+		{"2026-10-15 11:00", PeriodDaily, "2026-10-15 23:00"},
+		{"2026-10-12 23:00", PeriodWeekly, "2026-10-15 23:00"},
+		{"2026-10-04 23:00", PeriodMonthly, "2026-10-15 23:00"},
+		{"2026-09-02 23:00", PeriodMonthly, "2026-09-30 23:00"},
+		{"2026-08-31 23:00", "", ""}, // no bucket of August is kept
+	}
+	for _, tt := range tests {
+		r := displaced[tt.path]
+		if r == nil || r.Period != tt.wantPeriod || r.KeptPath != tt.wantKept {
+			t.Errorf("%s: reason %+v, want %q kept by %q", tt.path, r, tt.wantPeriod, tt.wantKept)
+		}
+	}
+}
+
+// TestScanGFSRule verifies a GFS rule dates files by name, keeps a separate
+// set per series and leaves unmanaged entries alone however old they are
+func TestScanGFSRule(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		filepath.Join(root, "notes.txt"): "x", // not managed
+	}
+	for _, name := range []string{
+		"pg-20261015.tar", "pg-20261014.tar", "pg-20261013.tar",
+		"mysql-20261015.tar", "mysql-20261001.tar",
+	} {
+		files[filepath.Join(root, name)] = "backup"
+	}
+	writeFiles(t, files)
+	old := time.Now().Add(-400 * 24 * time.Hour)
+	for path := range files {
+		if err := os.Chtimes(path, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	g := &config.GFS{Daily: 2, Pattern: `^(?P<series>\w+)-(?P<date>\d{8})\.tar$`, DateLayout: "20060102"}
+	rule := &config.PathRule{Path: root, AgeOffDays: 1, MaxFreePercent: 1, StackThreshold: 1, GFS: g}
+	s := NewScanner(nil)
+	candidates, err := s.scanPath(rule, 99, time.Now())
+	if err != nil {
+		t.Fatalf("scanPath: %v", err)
+	}
+
+	got := make(map[string]*GFSReason)
+	for _, c := range candidates {
+		got[filepath.Base(c.Path)] = c.DeletionReason.GFS
+		if c.DeletionReason.GFS == nil || c.DeletionReason.AgeThreshold != nil || c.DeletionReason.DiskThreshold != nil {
+			t.Errorf("%s selected by %s, want gfs alone", c.Path, c.DeletionReason.ToLogString())
+		}
+	}
+	if len(got) != 1 || got["pg-20261013.tar"] == nil || got["pg-20261013.tar"].Series != "pg" {
+		t.Errorf("selected %v, want only pg-20261013.tar", got)
+	}
+}
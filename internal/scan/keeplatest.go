@@ -0,0 +1,202 @@
+package scan
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"storage-sage/internal/config"
+)
+
+// KeepLatestReason indicates file was ranked past the newest files keep_latest keeps in its group
+type KeepLatestReason struct {
+	Name       string // Value of the pattern's name group, if any; the group is also per directory
+	Rank       int    // Position in the group, newest first (1 = newest)
+	Total      int    // Files in the group
+	Count      int    // Files kept per group, from config
+	Order      string // config.OrderMtime or config.OrderSemver
+	Version    string // Version parsed from the name, for semver order
+	NewestKept string // The newest file kept for the group
+}
+
+// versionFile is a file a keep_latest rule manages, collected during the walk
+type versionFile struct {
+	path    string
+	info    os.FileInfo
+	group   string
+	name    string
+	version string
+	semver  semver
+}
+
+// versionFileFor returns the managed file for an entry of rule's walk, or false
+// if keep_latest leaves the entry alone; directories are never managed
+func versionFileFor(k *config.KeepLatest, path string, info os.FileInfo) (versionFile, bool) {
+	if !info.Mode().IsRegular() {
+		return versionFile{}, false
+	}
+	name, version, ok := k.Match(filepath.Base(path))
+	if !ok {
+		return versionFile{}, false
+	}
+	f := versionFile{path: path, info: info, group: filepath.Dir(path) + "\x00" + name, name: name, version: version}
+	if k.Order == config.OrderSemver {
+		// A name that does not carry a valid version is not one of the managed files
+		if f.semver, ok = parseSemver(version); !ok {
+			return versionFile{}, false
+		}
+	}
+	return f, true
+}
+
+// keepLatestSelect returns why each managed file past the newest k.Count of its
+// group is selected, keyed by path
+func keepLatestSelect(k *config.KeepLatest, files []versionFile) map[string]*KeepLatestReason {
+	groups := make(map[string][]versionFile)
+	for _, f := range files {
+		groups[f.group] = append(groups[f.group], f)
+	}
+
+	selected := make(map[string]*KeepLatestReason)
+	for _, group := range groups {
+		if len(group) <= k.Count {
+			continue
+		}
+		sort.Slice(group, func(i, j int) bool {
+			if k.Order == config.OrderSemver {
+				if c := group[i].semver.compare(group[j].semver); c != 0 {
+					return c > 0
+				}
+			}
+			if mi, mj := group[i].info.ModTime(), group[j].info.ModTime(); !mi.Equal(mj) {
+				return mi.After(mj)
+			}
+			return group[i].path > group[j].path
+		})
+		for i, f := range group[k.Count:] {
+			reason := &KeepLatestReason{
+				Name: f.name, Rank: k.Count + i + 1, Total: len(group),
+				Count: k.Count, Order: k.Order, NewestKept: group[0].path,
+			}
+			if k.Order == config.OrderSemver {
+				reason.Version = f.version
+			}
+			selected[f.path] = reason
+		}
+	}
+	return selected
+}
+
+// logString formats the reason for ToLogString.
+// Example: "keep_latest: name=service rank 4 of 6 by semver, version 1.2.1 (keep 3, newest kept /ci/service-1.2.4.tar.gz)"
+func (r *KeepLatestReason) logString() string {
+	name := ""
+	if r.Name != "" {
+		name = " name=" + r.Name
+	}
+	version := ""
+	if r.Version != "" {
+		version = ", version " + r.Version
+	}
+	return fmt.Sprintf("keep_latest:%s rank %d of %d by %s%s (keep %d, newest kept %s)",
+		name, r.Rank, r.Total, r.Order, version, r.Count, r.NewestKept)
+}
+
+// semver is a semantic version: up to three numeric components, missing ones
+// zero, and dot-separated prerelease identifiers; build metadata is dropped
+type semver struct {
+	core [3]uint64
+	pre  []string
+}
+
+// parseSemver parses versions like "1.2.3", "v2.0.0-rc.1" or "1.4+build.7"
+func parseSemver(s string) (semver, bool) {
+	var v semver
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "v"), "V")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		v.pre = strings.Split(s[i+1:], ".")
+		s = s[:i]
+		for _, id := range v.pre {
+			if id == "" {
+				return semver{}, false
+			}
+		}
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return semver{}, false
+	}
+	for i, p := range parts {
+		n, err := strconv.ParseUint(p, 10, 64)
+		if err != nil {
+			return semver{}, false
+		}
+		v.core[i] = n
+	}
+	return v, true
+}
+
+// compare returns -1, 0 or 1 as v is older than, equal to or newer than o,
+// with semantic versioning precedence: a prerelease precedes its release
+func (v semver) compare(o semver) int {
+	for i := range v.core {
+		if v.core[i] != o.core[i] {
+			return cmpOrder(v.core[i] < o.core[i])
+		}
+	}
+	switch {
+	case len(v.pre) == 0 && len(o.pre) == 0:
+		return 0
+	case len(v.pre) == 0:
+		return 1
+	case len(o.pre) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.pre) && i < len(o.pre); i++ {
+		a, b := v.pre[i], o.pre[i]
+		if a == b {
+			continue
+		}
+		na, errA := strconv.ParseUint(a, 10, 64)
+		nb, errB := strconv.ParseUint(b, 10, 64)
+		switch {
+		case errA == nil && errB == nil:
+			return cmpOrder(na < nb)
+		case errA == nil:
+			return -1 // Numeric identifiers precede alphanumeric ones
+		case errB == nil:
+			return 1
+		default:
+			return cmpOrder(a < b)
+		}
+	}
+	if len(v.pre) != len(o.pre) {
+		return cmpOrder(len(v.pre) < len(o.pre))
+	}
+	return 0
+}
+
+func cmpOrder(less bool) int {
+	if less {
+		return -1
+	}
+	return 1
+}
+
+// keepLatestSubject names what the group keeps for ToHumanReadable, e.g. "versions of service"
+func keepLatestSubject(r *KeepLatestReason) string {
+	what := "files"
+	if r.Order == config.OrderSemver {
+		what = "versions"
+	}
+	if r.Name != "" {
+		return what + " of " + r.Name
+	}
+	return what + " in the directory"
+}
@@ -0,0 +1,85 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"storage-sage/internal/config"
+)
+
+func TestSemverCompare(t *testing.T) {
+	// Each version precedes the next
+	ordered := []string{"0.9", "1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "v1.0.0", "1.2.9", "1.10.0+build.7"}
+	for i := 0; i+1 < len(ordered); i++ {
+		a, okA := parseSemver(ordered[i])
+		b, okB := parseSemver(ordered[i+1])
+		if !okA || !okB {
+			t.Fatalf("parseSemver(%q, %q) failed", ordered[i], ordered[i+1])
+		}
+		if a.compare(b) != -1 || b.compare(a) != 1 {
+			t.Errorf("%s should precede %s", ordered[i], ordered[i+1])
+		}
+	}
+	for _, bad := range []string{"", "1.2.3.4", "1.x", "1.0.0-", "1.0.0-a..b"} {
+		if _, ok := parseSemver(bad); ok {
+			t.Errorf("parseSemver(%q) succeeded, want failure", bad)
+		}
+	}
+}
+
+// TestScanKeepLatestRule verifies a keep_latest rule keeps the newest versions
+// of each artifact name however old they are, ranks by version rather than
+// mtime, and leaves files it does not manage alone
+func TestScanKeepLatestRule(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		filepath.Join(root, "README"):                        "x", // not managed
+		filepath.Join(root, "service-1.2.10.tar.gz"):         "a",
+		filepath.Join(root, "service-1.2.9.tar.gz"):          "a",
+		filepath.Join(root, "service-1.2.3.tar.gz"):          "a",
+		filepath.Join(root, "service-1.3.0-rc.1.tar.gz"):     "a",
+		filepath.Join(root, "worker-0.1.0.tar.gz"):           "a",
+		filepath.Join(root, "worker-0.2.0.tar.gz"):           "a",
+		filepath.Join(root, "other", "service-0.0.1.tar.gz"): "a", // a group of its own
+	}
+	writeFiles(t, files)
+	// The oldest version has the newest mtime, so only version order selects it
+	old := time.Now().Add(-400 * 24 * time.Hour)
+	for path := range files {
+		if err := os.Chtimes(path, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chtimes(filepath.Join(root, "service-1.2.3.tar.gz"), time.Now(), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	k := &config.KeepLatest{Count: 2, Pattern: `^(?P<name>.+)-(?P<version>\d[^-]*(?:-[\w.]+)?)\.tar\.gz$`, Order: config.OrderSemver}
+	rule := &config.PathRule{Path: root, AgeOffDays: 1, MaxFreePercent: 1, StackThreshold: 1, KeepLatest: k}
+	s := NewScanner(nil)
+	candidates, err := s.scanPath(rule, 99, time.Now())
+	if err != nil {
+		t.Fatalf("scanPath: %v", err)
+	}
+
+	got := make(map[string]*KeepLatestReason)
+	for _, c := range candidates {
+		got[filepath.Base(c.Path)] = c.DeletionReason.KeepLatest
+		if c.DeletionReason.KeepLatest == nil || c.DeletionReason.AgeThreshold != nil || c.DeletionReason.DiskThreshold != nil {
+			t.Errorf("%s selected by %s, want keep_latest alone", c.Path, c.DeletionReason.ToLogString())
+		}
+	}
+	want := map[string]int{"service-1.2.9.tar.gz": 3, "service-1.2.3.tar.gz": 4}
+	if len(got) != len(want) {
+		t.Fatalf("selected %v, want %v", got, want)
+	}
+	for name, rank := range want {
+		r := got[name]
+		if r == nil || r.Rank != rank || r.Total != 4 || r.Name != "service" ||
+			r.NewestKept != filepath.Join(root, "service-1.3.0-rc.1.tar.gz") {
+			t.Errorf("%s: reason %+v, want rank %d of 4 behind service-1.3.0-rc.1", name, r, rank)
+		}
+	}
+}
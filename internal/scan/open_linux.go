@@ -0,0 +1,39 @@
+//go:build linux
+
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// openFiles returns the regular files some process holds open, read from
+// /proc/*/fd; processes the daemon may not inspect are missed
+func openFiles() openSet {
+	set := make(openSet)
+	procs, err := os.ReadDir("/proc")
+	if err != nil {
+		return set
+	}
+	for _, p := range procs {
+		if _, err := strconv.Atoi(p.Name()); err != nil {
+			continue
+		}
+		dir := filepath.Join("/proc", p.Name(), "fd")
+		fds, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			info, err := os.Stat(filepath.Join(dir, fd.Name()))
+			if err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			if dev, ino, ok := fileID(info); ok {
+				set[fileKey{dev, ino}] = struct{}{}
+			}
+		}
+	}
+	return set
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package scan
+
+// openFiles is not supported on this platform; no file counts as open
+func openFiles() openSet {
+	return nil
+}
@@ -0,0 +1,85 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"storage-sage/internal/config"
+	"storage-sage/internal/index"
+)
+
+// TestNestedRulesOwnEachFileOnce verifies a file below a nested root is only
+// selected by the deepest rule, and the enclosing walk leaves its subtree alone
+func TestNestedRulesOwnEachFileOnce(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "lib", "app")
+	writeFiles(t, map[string]string{
+		filepath.Join(root, "top.log"):           "x",
+		filepath.Join(root, "other", "old.log"):  "x",
+		filepath.Join(nested, "app.log"):         "x",
+		filepath.Join(nested, "deep", "app.log"): "x",
+	})
+	old := time.Now().Add(-40 * 24 * time.Hour)
+	for _, p := range []string{
+		filepath.Join(root, "top.log"), filepath.Join(root, "other", "old.log"), filepath.Join(root, "other"),
+		filepath.Join(nested, "app.log"), filepath.Join(nested, "deep", "app.log"), filepath.Join(nested, "deep"),
+		nested, filepath.Join(root, "lib"),
+	} {
+		if err := os.Chtimes(p, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	idx, err := index.Open(filepath.Join(t.TempDir(), "idx.gob"))
+	if err != nil {
+		t.Fatalf("index.Open: %v", err)
+	}
+	cfg := &config.Config{
+		// The nested root is listed first and again in scan_paths; it is still walked once
+		Paths: []config.PathRule{
+			{Path: nested, AgeOffDays: 30, MaxFreePercent: 100, StackThreshold: 100},
+			{Path: root, AgeOffDays: 30, MaxFreePercent: 100, StackThreshold: 100},
+		},
+		ScanPaths: []string{nested},
+	}
+	s := NewScanner(nil)
+	s.SetIndex(idx, 24*time.Hour)
+
+	candidates, err := s.Scan(cfg, time.Now())
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	want := map[string]string{
+		filepath.Join(root, "top.log"):           root,
+		filepath.Join(root, "other", "old.log"):  root,
+		filepath.Join(root, "other"):             root,
+		filepath.Join(nested, "app.log"):         nested,
+		filepath.Join(nested, "deep", "app.log"): nested,
+		filepath.Join(nested, "deep"):            nested,
+	}
+	got := make(map[string]string)
+	for _, c := range candidates {
+		if prev, dup := got[c.Path]; dup {
+			t.Errorf("%s selected twice, by %s and %s", c.Path, prev, c.DeletionReason.PathRule)
+		}
+		got[c.Path] = c.DeletionReason.PathRule
+	}
+	for path, rule := range want {
+		if got[path] != rule {
+			t.Errorf("%s owned by %q, want %q", path, got[path], rule)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("candidates = %v, want %v", got, want)
+	}
+	if counts := s.FileCounts(); counts[root] != 2 || counts[nested] != 2 {
+		t.Errorf("file counts = %v, want 2 under each root", counts)
+	}
+
+	// The enclosing walk must not drop the index entries the nested walk recorded
+	if _, ok := idx.Lookup(filepath.Join(nested, "deep")); !ok {
+		t.Error("index entry below the nested root was dropped by the enclosing walk")
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package scan
+
+import "os"
+
+// fileOwner is not supported on this platform
+func fileOwner(info os.FileInfo) (uid, gid uint32, ok bool) {
+	return 0, 0, false
+}
+
+// fileID is not supported on this platform
+func fileID(info os.FileInfo) (dev, ino uint64, ok bool) {
+	return 0, 0, false
+}
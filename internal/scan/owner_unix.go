@@ -0,0 +1,26 @@
+//go:build unix
+
+package scan
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileOwner returns the uid and gid recorded in info, if available
+func fileOwner(info os.FileInfo) (uid, gid uint32, ok bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return st.Uid, st.Gid, true
+}
+
+// fileID returns the device and inode recorded in info, if available
+func fileID(info os.FileInfo) (dev, ino uint64, ok bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return uint64(st.Dev), uint64(st.Ino), true
+}
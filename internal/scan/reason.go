@@ -2,8 +2,11 @@ package scan
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
+
+	"storage-sage/internal/config"
 )
 
 // DeletionReason captures why a file was selected for deletion.
@@ -13,6 +16,14 @@ type DeletionReason struct {
 	AgeThreshold   *AgeReason
 	DiskThreshold  *DiskReason
 	StackedCleanup *StackedReason
+	Tier           *TierReason       // Set instead of DiskThreshold/StackedCleanup for rules with tiers
+	GFS            *GFSReason        // Set alone for rules with gfs retention
+	KeepLatest     *KeepLatestReason // Set alone for rules with keep_latest retention
+	Compress       *CompressReason   // Set alone when only a compress tier selects the file
+	Truncate       *TruncateReason   // Set alone for oversized files truncated in place
+
+	// Set alone when a reason applied but the retention floor keeps the entry
+	RetentionFloor *RetentionReason
 
 	// Metadata
 	PathRule    string    // Which PathRule triggered this (e.g., "/var/log")
@@ -21,8 +32,11 @@ type DeletionReason struct {
 
 // AgeReason indicates file was selected due to age threshold.
 type AgeReason struct {
-	ConfiguredDays int // age_off_days from config
-	ActualAgeDays  int // actual file age at scan time
+	ConfiguredDays int           // max age from config, in whole days
+	ActualAgeDays  int           // actual file age at scan time, in whole days
+	MaxAge         time.Duration // max age from config (max_age or age_off_days)
+	Age            time.Duration // actual file age at scan time
+	Source         string        // Timestamp the age counts from (config.AgeSource*); "" or mtime is not logged
 }
 
 // DiskReason indicates file was selected due to disk usage threshold.
@@ -33,15 +47,42 @@ type DiskReason struct {
 
 // StackedReason indicates file was selected due to stacked cleanup (emergency mode).
 type StackedReason struct {
-	StackThreshold float64 // stack_threshold from config
-	StackAgeDays   int     // stack_age_days from config
-	ActualPercent  float64 // actual disk usage at scan time
-	ActualAgeDays  int     // actual file age at scan time
+	StackThreshold float64       // stack_threshold from config
+	StackAgeDays   int           // stacked cleanup age from config, in whole days
+	ActualPercent  float64       // actual disk usage at scan time
+	ActualAgeDays  int           // actual file age at scan time, in whole days
+	StackAge       time.Duration // stacked cleanup age from config (stack_max_age or stack_age_days)
+	Age            time.Duration // actual file age at scan time
+}
+
+// ageStrings formats an age and the limit it reached for ToLogString: in whole
+// days, as always logged, when the limit is whole days, otherwise as
+// durations such as "7h12m" and "6h"
+func ageStrings(age, limit time.Duration, ageDays, limitDays int) (string, string) {
+	if limit%config.Day == 0 {
+		return fmt.Sprintf("%dd", ageDays), fmt.Sprintf("%dd", limitDays)
+	}
+	return config.FormatDuration(age.Truncate(time.Minute)), config.FormatDuration(limit)
+}
+
+// humanAge rewrites an age from ageStrings for ToHumanReadable: "20d" reads "20 days"
+func humanAge(s string) string {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if _, err := strconv.Atoi(days); err == nil {
+			return days + " days"
+		}
+	}
+	return s
 }
 
 // HasReason returns true if any deletion reason applies.
 func (dr DeletionReason) HasReason() bool {
-	return dr.AgeThreshold != nil || dr.DiskThreshold != nil || dr.StackedCleanup != nil
+	return dr.AgeThreshold != nil || dr.DiskThreshold != nil || dr.StackedCleanup != nil || dr.Tier != nil || dr.GFS != nil || dr.KeepLatest != nil || dr.Compress != nil || dr.Truncate != nil
+}
+
+// deletes reports whether any reason removes the entry rather than compressing or truncating it
+func (dr DeletionReason) deletes() bool {
+	return dr.AgeThreshold != nil || dr.DiskThreshold != nil || dr.StackedCleanup != nil || dr.Tier != nil || dr.GFS != nil || dr.KeepLatest != nil
 }
 
 // ToLogString formats the reason for structured logging.
@@ -53,14 +94,41 @@ func (dr DeletionReason) ToLogString() string {
 
 	var parts []string
 
-	// Show in priority order: stacked > disk > age
+	if dr.GFS != nil {
+		parts = append(parts, dr.GFS.logString())
+	}
+	if dr.KeepLatest != nil {
+		parts = append(parts, dr.KeepLatest.logString())
+	}
+	if dr.Compress != nil {
+		parts = append(parts, dr.Compress.logString())
+	}
+	if dr.Truncate != nil {
+		parts = append(parts, dr.Truncate.logString())
+	}
+
+	// Show in priority order: tier > stacked > disk > age
+	if dr.Tier != nil {
+		age, minAge := ageStrings(dr.Tier.Age, dr.Tier.MinAge, dr.Tier.ActualAgeDays, dr.Tier.MinAgeDays)
+		parts = append(parts, fmt.Sprintf(
+			"tier_%d: disk_usage=%.1f%% (threshold=%.1f%%), age=%s (min=%s), strategy=%s",
+			dr.Tier.Level,
+			dr.Tier.ActualPercent,
+			dr.Tier.UsagePercent,
+			age,
+			minAge,
+			dr.Tier.Strategy,
+		))
+	}
+
 	if dr.StackedCleanup != nil {
+		age, minAge := ageStrings(dr.StackedCleanup.Age, dr.StackedCleanup.StackAge, dr.StackedCleanup.ActualAgeDays, dr.StackedCleanup.StackAgeDays)
 		parts = append(parts, fmt.Sprintf(
-			"stacked_cleanup: disk_usage=%.1f%% (threshold=%.1f%%), age=%dd (min=%dd)",
+			"stacked_cleanup: disk_usage=%.1f%% (threshold=%.1f%%), age=%s (min=%s)",
 			dr.StackedCleanup.ActualPercent,
 			dr.StackedCleanup.StackThreshold,
-			dr.StackedCleanup.ActualAgeDays,
-			dr.StackedCleanup.StackAgeDays,
+			age,
+			minAge,
 		))
 	}
 
@@ -73,11 +141,12 @@ func (dr DeletionReason) ToLogString() string {
 	}
 
 	if dr.AgeThreshold != nil {
-		parts = append(parts, fmt.Sprintf(
-			"age_threshold: %dd (max=%dd)",
-			dr.AgeThreshold.ActualAgeDays,
-			dr.AgeThreshold.ConfiguredDays,
-		))
+		age, maxAge := ageStrings(dr.AgeThreshold.Age, dr.AgeThreshold.MaxAge, dr.AgeThreshold.ActualAgeDays, dr.AgeThreshold.ConfiguredDays)
+		part := fmt.Sprintf("age_threshold: %s (max=%s)", age, maxAge)
+		if src := dr.AgeThreshold.Source; src != "" && src != config.AgeSourceMtime {
+			part += " from " + src
+		}
+		parts = append(parts, part)
 	}
 
 	return strings.Join(parts, " + ")
@@ -92,12 +161,40 @@ func (dr DeletionReason) ToHumanReadable() string {
 
 	var parts []string
 
-	// If stacked cleanup is active, prioritize that message
-	if dr.StackedCleanup != nil {
+	// If truncation, compression, GFS, keep_latest, a tier or stacked cleanup is active, prioritize that message
+	if dr.Truncate != nil {
+		part := fmt.Sprintf("Truncated to its last %d bytes (%d bytes, limit %d)", dr.Truncate.KeepTail, dr.Truncate.Size, dr.Truncate.MaxSize)
+		if dr.Truncate.Open {
+			part += ", held open by a process"
+		}
+		parts = append(parts, part)
+	} else if dr.Compress != nil {
+		age, _ := ageStrings(dr.Compress.Age, dr.Compress.MinAge, dr.Compress.ActualAgeDays, dr.Compress.MinAgeDays)
+		parts = append(parts, fmt.Sprintf("Compressed with %s by tier %d, file %s old", dr.Compress.Format, dr.Compress.Level, humanAge(age)))
+	} else if dr.KeepLatest != nil {
+		parts = append(parts, fmt.Sprintf("Not among the newest %d %s (ranked %d of %d by %s)",
+			dr.KeepLatest.Count, keepLatestSubject(dr.KeepLatest), dr.KeepLatest.Rank, dr.KeepLatest.Total, dr.KeepLatest.Order))
+	} else if dr.GFS != nil {
+		if dr.GFS.Period != "" {
+			parts = append(parts, fmt.Sprintf("Newer file kept for %s backup %s", dr.GFS.Period, dr.GFS.Bucket))
+		} else {
+			parts = append(parts, "Older than every backup period kept")
+		}
+	} else if dr.Tier != nil {
+		age, _ := ageStrings(dr.Tier.Age, dr.Tier.MinAge, dr.Tier.ActualAgeDays, dr.Tier.MinAgeDays)
 		parts = append(parts, fmt.Sprintf(
-			"Critical disk usage (%.1f%%), file %d days old",
+			"Disk usage tier %d (%.1f%%, engages at %.1f%%), file %s old",
+			dr.Tier.Level,
+			dr.Tier.ActualPercent,
+			dr.Tier.UsagePercent,
+			humanAge(age),
+		))
+	} else if dr.StackedCleanup != nil {
+		age, _ := ageStrings(dr.StackedCleanup.Age, dr.StackedCleanup.StackAge, dr.StackedCleanup.ActualAgeDays, dr.StackedCleanup.StackAgeDays)
+		parts = append(parts, fmt.Sprintf(
+			"Critical disk usage (%.1f%%), file %s old",
 			dr.StackedCleanup.ActualPercent,
-			dr.StackedCleanup.ActualAgeDays,
+			humanAge(age),
 		))
 	} else {
 		// Show individual reasons only if not in stacked mode
@@ -109,10 +206,12 @@ func (dr DeletionReason) ToHumanReadable() string {
 		}
 
 		if dr.AgeThreshold != nil {
-			parts = append(parts, fmt.Sprintf(
-				"File older than %d days",
-				dr.AgeThreshold.ConfiguredDays,
-			))
+			_, maxAge := ageStrings(dr.AgeThreshold.Age, dr.AgeThreshold.MaxAge, dr.AgeThreshold.ActualAgeDays, dr.AgeThreshold.ConfiguredDays)
+			part := fmt.Sprintf("File older than %s", humanAge(maxAge))
+			if src := dr.AgeThreshold.Source; src != "" && src != config.AgeSourceMtime {
+				part += " by " + src
+			}
+			parts = append(parts, part)
 		}
 	}
 
@@ -122,6 +221,21 @@ func (dr DeletionReason) ToHumanReadable() string {
 // GetPrimaryReason returns a short label for the most critical reason.
 // Used for filtering/grouping in the UI.
 func (dr DeletionReason) GetPrimaryReason() string {
+	if dr.GFS != nil {
+		return "gfs"
+	}
+	if dr.KeepLatest != nil {
+		return "keep_latest"
+	}
+	if dr.Compress != nil {
+		return "compress"
+	}
+	if dr.Truncate != nil {
+		return "truncate"
+	}
+	if dr.Tier != nil {
+		return "tier"
+	}
 	if dr.StackedCleanup != nil {
 		return "stacked_cleanup"
 	}
@@ -2,6 +2,7 @@ package scan
 
 import (
 	"testing"
+	"time"
 
 	"storage-sage/internal/config"
 )
@@ -324,7 +325,7 @@ func TestEvaluateDeletionReason(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			reason := scanner.evaluateDeletionReason(&tt.rule, tt.ageInDays, tt.diskUsage, nil)
+			reason := scanner.evaluateDeletionReason(&tt.rule, 0, time.Duration(tt.ageInDays)*config.Day, tt.diskUsage, nil, false)
 
 			if tt.expectStacked && reason.StackedCleanup == nil {
 				t.Error("Expected StackedCleanup to be set")
@@ -357,3 +358,28 @@ func TestEvaluateDeletionReason(t *testing.T) {
 		})
 	}
 }
+
+// TestSubDayMaxAge verifies a max_age below a day selects by the hour and
+// logs precise ages, while whole-day limits keep the day format
+func TestSubDayMaxAge(t *testing.T) {
+	rule := &config.PathRule{Path: "/tmp/uploads", MaxAge: config.Duration(6 * time.Hour), MaxFreePercent: 100, StackThreshold: 100}
+
+	if r := evaluateReason(rule, 0, 5*time.Hour, 10); r.HasReason() {
+		t.Errorf("5h old upload selected: %s", r.ToLogString())
+	}
+	r := evaluateReason(rule, 0, 7*time.Hour+12*time.Minute+30*time.Second, 10)
+	if r.AgeThreshold == nil || r.AgeThreshold.MaxAge != 6*time.Hour || r.AgeThreshold.ConfiguredDays != 0 {
+		t.Fatalf("7h old upload: %+v, want age_threshold with max 6h", r.AgeThreshold)
+	}
+	if got, want := r.ToLogString(), "age_threshold: 7h12m (max=6h)"; got != want {
+		t.Errorf("ToLogString() = %q, want %q", got, want)
+	}
+	if got, want := r.ToHumanReadable(), "File older than 6h"; got != want {
+		t.Errorf("ToHumanReadable() = %q, want %q", got, want)
+	}
+
+	days := &config.PathRule{Path: "/var/log", AgeOffDays: 7, MaxFreePercent: 100, StackThreshold: 100}
+	if got, want := evaluateReason(days, 0, 10*config.Day+5*time.Hour, 10).ToLogString(), "age_threshold: 10d (max=7d)"; got != want {
+		t.Errorf("ToLogString() = %q, want %q", got, want)
+	}
+}
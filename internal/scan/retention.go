@@ -0,0 +1,41 @@
+package scan
+
+import (
+	"os"
+	"sort"
+)
+
+// RetentionReason records that a rule selected an entry but its retention
+// floor kept it. It is set instead of every other reason, so HasReason is false.
+type RetentionReason struct {
+	MinRetentionDays int  // min_retention_days from config
+	ActualAgeDays    int  // age of the entry as measured for the floor
+	Compliance       bool // age counted from the later of mtime and ctime
+}
+
+// RetentionHold totals what one rule's retention floor kept during a scan
+type RetentionHold struct {
+	PathRule         string
+	MinRetentionDays int
+	Files            int   // Entries that qualified otherwise
+	Bytes            int64 // Their size; directories count as 0
+}
+
+// add counts one entry kept by the floor
+func (h *RetentionHold) add(info os.FileInfo) {
+	h.Files++
+	if !info.IsDir() {
+		h.Bytes += info.Size()
+	}
+}
+
+// RetentionHolds returns what the last Scan kept under each rule root with a
+// retention floor, ordered by root
+func (s *Scanner) RetentionHolds() []*RetentionHold {
+	holds := make([]*RetentionHold, 0, len(s.retention))
+	for _, h := range s.retention {
+		holds = append(holds, h)
+	}
+	sort.Slice(holds, func(i, j int) bool { return holds[i].PathRule < holds[j].PathRule })
+	return holds
+}
@@ -0,0 +1,43 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"storage-sage/internal/config"
+)
+
+// TestRetentionFloorOverridesDiskMode verifies a file younger than the floor is
+// kept and counted even when disk usage would select every file
+func TestRetentionFloorOverridesDiskMode(t *testing.T) {
+	root := t.TempDir()
+	young, aged := filepath.Join(root, "young.log"), filepath.Join(root, "aged.log")
+	writeFiles(t, map[string]string{young: "12345", aged: "x"})
+	old := time.Now().Add(-20 * 24 * time.Hour)
+	if err := os.Chtimes(aged, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	rule := &config.PathRule{Path: root, MaxFreePercent: 90, StackThreshold: 100, MinRetentionDays: 10}
+	s := NewScanner(nil)
+	s.retention = make(map[string]*RetentionHold)
+
+	candidates, err := s.scanPath(rule, 99, time.Now())
+	if err != nil {
+		t.Fatalf("scanPath: %v", err)
+	}
+	for _, c := range candidates {
+		if c.Path == young {
+			t.Errorf("young file selected: %s", c.DeletionReason.ToLogString())
+		}
+	}
+	if len(candidates) != 1 || candidates[0].Path != aged {
+		t.Errorf("candidates = %v, want only %s", candidates, aged)
+	}
+	hold := s.retention[root]
+	if hold == nil || hold.Files != 1 || hold.Bytes != 5 || hold.MinRetentionDays != 10 {
+		t.Errorf("hold = %+v, want the young file's 5 bytes under a 10-day floor", hold)
+	}
+}
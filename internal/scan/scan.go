@@ -11,6 +11,9 @@ import (
 
 	"storage-sage/internal/config"
 	"storage-sage/internal/disk"
+	"storage-sage/internal/index"
+	"storage-sage/internal/limiter"
+	"storage-sage/internal/safety"
 )
 
 // Logger interface for structured logging
@@ -47,7 +50,17 @@ func (l *stdLogger) logWithLevel(level, msg string, args ...interface{}) {
 
 // Scanner performs file system scans with deletion reason tracking
 type Scanner struct {
-	logger Logger
+	logger     Logger
+	index      *index.Index                // Optional persistent directory index for incremental scans
+	fullRescan time.Duration               // Force a full walk of a root after this long (0 = only when unindexed)
+	limiter    *limiter.Limiter            // Optional CPU pacing between filesystem operations
+	fileCounts map[string]int64            // Non-directory entries seen per rule root during the last Scan
+	scanErrors map[string]*ScanErrorReport // Unreadable entries per rule root during the last Scan
+	targets    map[string]int64            // Bytes to free per rule root at the last Scan
+	nested     map[string][]string         // Roots of other rules below each rule root, skipped by its walk
+	tiers      map[string]TierState        // Tier each tiered rule root is held at, carried between cycles
+	retention  map[string]*RetentionHold   // Entries kept by each rule root's retention floor during the last Scan
+	mode       string                      // Most critical cleanup mode of the last Scan
 }
 
 // NewScanner creates a new Scanner with the given logger
@@ -64,9 +77,35 @@ type Candidate struct {
 	Path           string
 	Size           int64
 	ModTime        time.Time
+	AgeTime        time.Time // When the entry's age counts from, per the rule's age_source
+	AgeSource      string    // Timestamp AgeTime came from (config.AgeSource*)
 	IsDir          bool
 	IsEmptyDir     bool
 	DeletionReason DeletionReason // NEW: Why this file was selected
+	UID            uint32         // Owning user (valid when HasOwner)
+	GID            uint32         // Owning group (valid when HasOwner)
+	HasOwner       bool           // False when the platform does not expose ownership
+	Type           os.FileMode    // File type bits from lstat (info.Mode().Type())
+	Dev            uint64         // Device of the scanned inode (valid when HasFileID)
+	Inode          uint64         // Inode number at scan time (valid when HasFileID)
+	HasFileID      bool           // False when the candidate was not built from lstat or the platform lacks inode numbers
+}
+
+// CandidateFromInfo builds a candidate from an lstat result, including ownership
+func CandidateFromInfo(path string, info os.FileInfo, reason DeletionReason) Candidate {
+	c := Candidate{
+		Path:           path,
+		Size:           info.Size(),
+		ModTime:        info.ModTime(),
+		AgeTime:        info.ModTime(),
+		AgeSource:      config.AgeSourceMtime,
+		IsDir:          info.IsDir(),
+		DeletionReason: reason,
+		Type:           info.Mode().Type(),
+	}
+	c.UID, c.GID, c.HasOwner = fileOwner(info)
+	c.Dev, c.Inode, c.HasFileID = fileID(info)
+	return c
 }
 
 type PathScanResult struct {
@@ -86,13 +125,49 @@ func Scan(cfg *config.Config, now time.Time) ([]Candidate, error) {
 	return ScanWithLogger(cfg, now, nil)
 }
 
+// SetIndex attaches a persistent directory index. Directories whose listing is
+// unchanged and whose files are all too young to qualify are not re-read.
+// Each root is still walked in full once every fullRescan.
+func (s *Scanner) SetIndex(idx *index.Index, fullRescan time.Duration) {
+	s.index = idx
+	s.fullRescan = fullRescan
+}
+
+// SetLimiter paces the walk so the scan stays within the configured CPU budget
+func (s *Scanner) SetLimiter(l *limiter.Limiter) {
+	s.limiter = l
+}
+
+// FileCounts returns the number of files seen under each rule root during the
+// last Scan, keyed by rule path. Roots that were not walked are absent.
+func (s *Scanner) FileCounts() map[string]int64 {
+	return s.fileCounts
+}
+
 // ScanWithLogger performs a comprehensive scan with a custom logger
 func ScanWithLogger(cfg *config.Config, now time.Time, logger *log.Logger) ([]Candidate, error) {
+	return ScanWithIndex(cfg, now, logger, nil)
+}
+
+// ScanWithIndex performs a comprehensive scan, using idx (if non-nil) to skip
+// unchanged subtrees. The caller owns idx and is responsible for saving it.
+func ScanWithIndex(cfg *config.Config, now time.Time, logger *log.Logger, idx *index.Index) ([]Candidate, error) {
 	if cfg == nil {
 		return nil, errNoPaths
 	}
 
 	scanner := NewScanner(logger)
+	if idx != nil {
+		scanner.SetIndex(idx, time.Duration(cfg.ScanOptimizations.FullRescanHours)*time.Hour)
+	}
+	return scanner.Scan(cfg, now)
+}
+
+// Scan performs a comprehensive scan with this scanner's index and limiter
+func (s *Scanner) Scan(cfg *config.Config, now time.Time) ([]Candidate, error) {
+	if cfg == nil {
+		return nil, errNoPaths
+	}
 
 	// Get all paths with their rules and priorities
 	pathResults := getPathResults(cfg, now)
@@ -103,6 +178,19 @@ func ScanWithLogger(cfg *config.Config, now time.Time, logger *log.Logger) ([]Ca
 	})
 
 	allCandidates := make([]Candidate, 0)
+	s.fileCounts = make(map[string]int64, len(pathResults))
+	s.scanErrors = make(map[string]*ScanErrorReport, len(pathResults))
+	s.targets = make(map[string]int64, len(pathResults))
+	s.nested = make(map[string][]string, len(pathResults))
+	s.retention = make(map[string]*RetentionHold, len(pathResults))
+	if s.tiers == nil {
+		s.tiers = make(map[string]TierState)
+	}
+	s.mode = ModeAge
+	severity := 0.0
+	for _, pathResult := range pathResults {
+		s.nested[pathResult.Path] = cfg.NestedRoots(pathResult.Path)
+	}
 
 	// Process each path in priority order
 	for _, pathResult := range pathResults {
@@ -116,20 +204,37 @@ func ScanWithLogger(cfg *config.Config, now time.Time, logger *log.Logger) ([]Ca
 
 		// Calculate disk usage percentage (used, not free)
 		diskUsage := 100.0 - pathResult.FreePercent
+		if pathResult.TargetBytes > 0 {
+			s.targets[pathResult.Path] = pathResult.TargetBytes
+		}
 
-		candidates, err := scanner.scanPath(pathResult.Rule, diskUsage)
+		// Tiers escalate at once but only step down past hysteresis and cooldown
+		level := 0
+		if pathResult.Rule.Tiered() {
+			state := nextTier(pathResult.Rule, diskUsage, s.tiers[pathResult.Path], now)
+			s.tiers[pathResult.Path] = state
+			level = state.Level
+		} else {
+			delete(s.tiers, pathResult.Path)
+		}
+		if mode, sev := ruleMode(pathResult.Rule, level, diskUsage); sev > severity {
+			s.mode, severity = mode, sev
+		}
+
+		candidates, err := s.scanPath(pathResult.Rule, diskUsage, now)
 		if err != nil {
 			// Log error but continue with other paths
-			scanner.logger.Warn("Failed to scan path", "path", pathResult.Path, "error", err)
+			s.logger.Warn("Failed to scan path", "path", pathResult.Path, "error", err)
 			continue
 		}
 		allCandidates = append(allCandidates, candidates...)
 	}
 
-	// Sort candidates by modification time (oldest first) for efficient cleanup
+	// Sort candidates by age (oldest first) for efficient cleanup
 	sort.Slice(allCandidates, func(i, j int) bool {
-		return allCandidates[i].ModTime.Before(allCandidates[j].ModTime)
+		return allCandidates[i].AgeTime.Before(allCandidates[j].AgeTime)
 	})
+	orderByStrategy(allCandidates)
 
 	return allCandidates, nil
 }
@@ -145,6 +250,9 @@ func getPathResults(cfg *config.Config, now time.Time) []PathScanResult {
 	// Process paths with rules first (specific configs take precedence)
 	for i := range cfg.Paths {
 		path := cfg.Paths[i].Path
+		if pathMap[path] {
+			continue
+		}
 		pathMap[path] = true
 		results = append(results, analyzePath(&cfg.Paths[i], cfg, now))
 	}
@@ -156,23 +264,51 @@ func getPathResults(cfg *config.Config, now time.Time) []PathScanResult {
 		if pathMap[path] {
 			continue
 		}
+		pathMap[path] = true
 
-		rule := &config.PathRule{
-			Path:              path,
-			AgeOffDays:        cfg.AgeOffDays,
-			MinFreePercent:    cfg.MinFreePercent,
-			MaxFreePercent:    90,  // Default
-			TargetFreePercent: 80,  // Default
-			Priority:          100, // Default lower priority
-			StackThreshold:    98,
-			StackAgeDays:      14,
-		}
-		results = append(results, analyzePath(rule, cfg, now))
+		results = append(results, analyzePath(scanPathRule(cfg, path), cfg, now))
 	}
 
 	return results
 }
 
+// scanPathRule builds the rule applied to a legacy scan_paths entry from the global settings
+func scanPathRule(cfg *config.Config, path string) *config.PathRule {
+	return &config.PathRule{
+		Path:              path,
+		AgeOffDays:        cfg.AgeOffDays,
+		MaxAge:            cfg.MaxAge,
+		MinFreePercent:    cfg.MinFreePercent,
+		MaxFreePercent:    90,  // Default
+		TargetFreePercent: 80,  // Default
+		Priority:          100, // Default lower priority
+		StackThreshold:    98,
+		StackAgeDays:      14,
+		MinRetentionDays:  cfg.ComplianceFloor(path), // Compliance floors of enclosing rules still apply
+		ComplianceMode:    cfg.ComplianceFloor(path) > 0,
+	}
+}
+
+// RuleFor returns the rule the scanner applies to root: the paths entry if
+// there is one, otherwise the scan_paths defaults. It returns nil for a root
+// that is not configured.
+func RuleFor(cfg *config.Config, root string) *config.PathRule {
+	if cfg == nil {
+		return nil
+	}
+	for i := range cfg.Paths {
+		if cfg.Paths[i].Path == root {
+			return &cfg.Paths[i]
+		}
+	}
+	for _, p := range cfg.ScanPaths {
+		if p == root {
+			return scanPathRule(cfg, p)
+		}
+	}
+	return nil
+}
+
 // getExcludePatterns returns exclude patterns for a rule (empty slice if not configured)
 func getExcludePatterns(rule *config.PathRule) []string {
 	// ExcludePatterns is not currently in PathRule, return empty slice
@@ -183,23 +319,75 @@ func getExcludePatterns(rule *config.PathRule) []string {
 // evaluateDeletionReason determines why a file was selected for deletion
 func (s *Scanner) evaluateDeletionReason(
 	rule *config.PathRule,
-	ageInDays int,
+	level int,
+	age time.Duration,
 	diskUsage float64,
 	fileInfo os.FileInfo,
+	open bool,
 ) DeletionReason {
+	reason := evaluateReason(rule, level, age, diskUsage)
+	if fileInfo != nil {
+		settleAction(rule, &reason, fileInfo.Name(), fileInfo.Mode(), fileInfo.Size(), open)
+	}
+	return applyRetentionFloor(rule, reason, fileInfo)
+}
+
+// applyRetentionFloor replaces a reason to delete an entry younger than the
+// rule's retention floor with the floor that keeps it
+func applyRetentionFloor(rule *config.PathRule, reason DeletionReason, fileInfo os.FileInfo) DeletionReason {
+	if !reason.HasReason() || rule.MinRetentionDays <= 0 || fileInfo == nil {
+		return reason
+	}
+
+	// The retention floor overrides every mode, emergency ones included
+	age := safety.RetentionAge(fileInfo, rule.ComplianceMode, time.Now())
+	if age < time.Duration(rule.MinRetentionDays)*24*time.Hour {
+		return DeletionReason{
+			PathRule:    rule.Path,
+			EvaluatedAt: reason.EvaluatedAt,
+			RetentionFloor: &RetentionReason{
+				MinRetentionDays: rule.MinRetentionDays,
+				ActualAgeDays:    int(age.Hours() / 24),
+				Compliance:       rule.ComplianceMode,
+			},
+		}
+	}
+	return reason
+}
+
+// evaluateReason applies rule to an entry of the given age on a filesystem at
+// diskUsage percent used, with the rule's tier at level (0 = none engaged)
+func evaluateReason(rule *config.PathRule, level int, age time.Duration, diskUsage float64) DeletionReason {
 	reason := DeletionReason{
 		PathRule:    rule.Path,
 		EvaluatedAt: time.Now(),
 	}
 
+	// GFS and keep_latest rules select only after the walk, once every managed file is known
+	if rule.KeepsByCount() {
+		return reason
+	}
+
+	// Tiers replace the disk and stacked thresholds below
+	if rule.Tiered() {
+		reason.Tier = tierReason(rule, level, age, diskUsage)
+		reason.AgeThreshold = ageReason(rule, age)
+		// settleAction keeps one of these
+		reason.Compress = compressReason(rule, level, age, diskUsage)
+		reason.Truncate = truncateReason(rule, level, age, diskUsage)
+		return reason
+	}
+
 	// Priority 1: Stacked cleanup (emergency mode - disk critically full + old files)
 	// This is the most urgent condition
-	if diskUsage >= float64(rule.StackThreshold) && ageInDays >= rule.StackAgeDays {
+	if stackAge := rule.StackAgeLimit(); diskUsage >= float64(rule.StackThreshold) && age >= stackAge {
 		reason.StackedCleanup = &StackedReason{
 			StackThreshold: float64(rule.StackThreshold),
-			StackAgeDays:   rule.StackAgeDays,
+			StackAgeDays:   wholeDays(stackAge),
 			ActualPercent:  diskUsage,
-			ActualAgeDays:  ageInDays,
+			ActualAgeDays:  wholeDays(age),
+			StackAge:       stackAge,
+			Age:            age,
 		}
 	}
 
@@ -214,16 +402,33 @@ func (s *Scanner) evaluateDeletionReason(
 
 	// Priority 3: Age threshold (baseline cleanup)
 	// Files are candidates because they're too old
-	if rule.AgeOffDays > 0 && ageInDays >= rule.AgeOffDays {
-		reason.AgeThreshold = &AgeReason{
-			ConfiguredDays: rule.AgeOffDays,
-			ActualAgeDays:  ageInDays,
-		}
-	}
+	reason.AgeThreshold = ageReason(rule, age)
+
+	// Truncation applies only to oversized files; settleAction decides once the size is known
+	reason.Truncate = truncateReason(rule, level, age, diskUsage)
 
 	return reason
 }
 
+// ageReason returns the reason rule's max age selects an entry of the given age, or nil
+func ageReason(rule *config.PathRule, age time.Duration) *AgeReason {
+	maxAge := rule.AgeLimit()
+	if maxAge <= 0 || age < maxAge {
+		return nil
+	}
+	return &AgeReason{
+		ConfiguredDays: wholeDays(maxAge),
+		ActualAgeDays:  wholeDays(age),
+		MaxAge:         maxAge,
+		Age:            age,
+	}
+}
+
+// wholeDays returns d in whole days, rounded down
+func wholeDays(d time.Duration) int {
+	return int(d / config.Day)
+}
+
 // markEmptyDirectories marks directories as empty if they contain no files
 func (s *Scanner) markEmptyDirectories(candidates []Candidate) []Candidate {
 	// Create a map to track which directories are candidates
@@ -259,27 +464,31 @@ func analyzePath(rule *config.PathRule, cfg *config.Config, now time.Time) PathS
 	}
 
 	// Get current disk usage
-	freePercent, _, totalBytes, err := disk.GetDiskUsage(rule.Path)
+	usedPercent, _, totalBytes, err := disk.GetDiskUsage(rule.Path)
 	if err != nil {
 		// If we can't get disk usage, skip this path
 		return result
 	}
-	result.FreePercent = freePercent
-	usedPercent := 100.0 - freePercent
+	result.FreePercent = 100.0 - usedPercent
+
+	// What GFS and keep_latest keep does not depend on disk usage
+	if rule.KeepsByCount() {
+		return result
+	}
 
 	// Check if we need cleanup based on disk usage
-	if usedPercent >= float64(rule.MaxFreePercent) {
+	if usedPercent >= rule.TriggerPercent() {
 		result.NeedsCleanup = true
 		result.CleanupReason = "disk_usage_threshold"
 		// Calculate target bytes to free
 		targetUsedPercent := float64(rule.TargetFreePercent)
 		targetUsedBytes := (targetUsedPercent / 100.0) * float64(totalBytes)
-		currentUsedBytes := ((100.0 - freePercent) / 100.0) * float64(totalBytes)
+		currentUsedBytes := (usedPercent / 100.0) * float64(totalBytes)
 		result.TargetBytes = int64(currentUsedBytes - targetUsedBytes)
 	}
 
 	// Check for stacked cleanup (high usage + age threshold)
-	if usedPercent >= float64(rule.StackThreshold) {
+	if !rule.Tiered() && usedPercent >= float64(rule.StackThreshold) {
 		result.NeedsCleanup = true
 		if result.CleanupReason == "" {
 			result.CleanupReason = "stacked_cleanup"
@@ -291,17 +500,59 @@ func analyzePath(rule *config.PathRule, cfg *config.Config, now time.Time) PathS
 	return result
 }
 
+// indexCutoff returns the mtime after which no file under rule can qualify in
+// this cycle, or the zero time when every file may qualify (disk threshold
+// mode) and no subtree can be skipped.
+func indexCutoff(rule *config.PathRule, level int, needsDiskScan, isStackedActive bool, now time.Time) time.Time {
+	if needsDiskScan || rule.KeepsByCount() {
+		return time.Time{}
+	}
+	// The index bounds mtimes, and a file's ctime is never before its mtime;
+	// atimes and names can date a file older than any mtime under it
+	if rule.AgeSource == config.AgeSourceAtime || rule.AgeSource == config.AgeSourceFilename {
+		return time.Time{}
+	}
+
+	minAge := time.Duration(-1)
+	if rule.AgeLimit() > 0 {
+		minAge = rule.AgeLimit()
+	}
+	if isStackedActive && (minAge < 0 || rule.StackAgeLimit() < minAge) {
+		minAge = rule.StackAgeLimit()
+	}
+	for _, action := range []string{config.ActionDelete, config.ActionCompress, config.ActionTruncate} {
+		if i := rule.EngagedTier(level, action); i > 0 && (minAge < 0 || rule.Tiers[i-1].MinAgeLimit() < minAge) {
+			minAge = rule.Tiers[i-1].MinAgeLimit()
+		}
+	}
+	if minAge <= 0 {
+		return time.Time{}
+	}
+	return now.Add(-minAge)
+}
+
 // scanPath scans a single path for candidates based on rules
-func (s *Scanner) scanPath(rule *config.PathRule, diskUsage float64) ([]Candidate, error) {
+func (s *Scanner) scanPath(rule *config.PathRule, diskUsage float64, now time.Time) ([]Candidate, error) {
 	var candidates []Candidate
 
-	// Determine which scans are active based on config and disk state
-	needsAgeScan := rule.AgeOffDays > 0
-	needsDiskScan := diskUsage >= float64(rule.MaxFreePercent)
-	isStackedActive := diskUsage >= float64(rule.StackThreshold)
+	// Determine which scans are active based on config and disk state;
+	// a tiered rule escalates through its engaged tier instead
+	needsAgeScan := rule.AgeLimit() > 0
+	needsDiskScan := false
+	isStackedActive := false
+	level := 0
+	if rule.Tiered() {
+		level = s.tiers[rule.Path].Level
+	} else if !rule.KeepsByCount() {
+		needsDiskScan = diskUsage >= float64(rule.MaxFreePercent)
+		isStackedActive = diskUsage >= float64(rule.StackThreshold)
+	}
+	// Outside tiers, truncate selects oversized files of any age
+	needsTruncateScan := rule.Truncate != nil && !rule.Tiered() && !rule.KeepsByCount() &&
+		(!rule.Truncate.Stack || isStackedActive)
 
 	// If no conditions are met, skip scanning this path entirely
-	if !needsAgeScan && !needsDiskScan && !isStackedActive {
+	if !needsAgeScan && !needsDiskScan && !isStackedActive && !needsTruncateScan && level == 0 && !rule.KeepsByCount() {
 		s.logger.Info("Skipping path - no cleanup conditions met",
 			"path", rule.Path,
 			"disk_usage", diskUsage,
@@ -314,19 +565,83 @@ func (s *Scanner) scanPath(rule *config.PathRule, diskUsage float64) ([]Candidat
 		"age_scan", needsAgeScan,
 		"disk_scan", needsDiskScan,
 		"stacked_active", isStackedActive,
+		"truncate_scan", needsTruncateScan,
+		"tier", level,
+		"gfs", rule.HasGFS(),
+		"keep_latest", rule.HasKeepLatest(),
 		"disk_usage", diskUsage,
 	)
 
 	excludePatterns := getExcludePatterns(rule)
 
-	err := filepath.Walk(rule.Path, func(path string, info os.FileInfo, err error) error {
+	walker := &treeWalker{idx: s.index, pace: s.limiter.Pace}
+	// Files below a nested root belong to that deeper rule alone
+	if nested := s.nested[rule.Path]; len(nested) > 0 {
+		walker.prune = make(map[string]bool, len(nested))
+		for _, root := range nested {
+			walker.prune[root] = true
+		}
+	}
+	fullRescan := false
+	if s.index != nil {
+		fullRescan = s.index.NeedsFullRescan(rule.Path, s.fullRescan, now)
+		if !fullRescan {
+			// Like the disk threshold, truncate needs every file whatever its age
+			walker.cutoff = indexCutoff(rule, level, needsDiskScan || needsTruncateScan, isStackedActive, now)
+		}
+	}
+
+	report := &ScanErrorReport{PathRule: rule.Path, Counts: make(map[string]int)}
+	hold := &RetentionHold{PathRule: rule.Path, MinRetentionDays: rule.MinRetentionDays}
+	consider := func(path string, info os.FileInfo, ageAt time.Time, source string, reason DeletionReason) {
+		recordAgeSource(&reason, source)
+		if reason.RetentionFloor != nil {
+			hold.add(info)
+			s.logger.Debug("File kept by retention floor",
+				"path", path,
+				"age_days", reason.RetentionFloor.ActualAgeDays,
+				"min_retention_days", reason.RetentionFloor.MinRetentionDays,
+			)
+		}
+
+		// Only add as candidate if at least one reason applies
+		if reason.HasReason() {
+			// IsEmptyDir is determined later for directories
+			candidate := CandidateFromInfo(path, info, reason)
+			candidate.AgeTime, candidate.AgeSource = ageAt, source
+
+			candidates = append(candidates, candidate)
+
+			s.logger.Debug("File selected for deletion",
+				"path", path,
+				"size", info.Size(),
+				"age", config.FormatDuration(now.Sub(ageAt).Truncate(time.Second)),
+				"reason", reason.ToLogString(),
+			)
+		}
+	}
+	// Truncating a file a process holds open frees space where deleting it would not
+	var open openSet
+	if rule.Truncate != nil {
+		open = openFiles()
+	}
+	var managed []gfsFile
+	var versions []versionFile
+	walker.walkFn = func(path string, info os.FileInfo, err error) error {
 		if err != nil {
-			// Log and continue on permission errors
-			if os.IsPermission(err) {
-				s.logger.Warn("Permission denied", "path", path)
+			// Entries removed during the walk are not invisible data
+			if os.IsNotExist(err) {
 				return nil
 			}
-			return err
+			// Record what could not be read and keep walking the rest of the tree
+			e := ScanError{PathRule: rule.Path, Path: path, Kind: scanErrorKind(err), Error: err.Error()}
+			if info != nil && info.IsDir() {
+				e.IsDir = true
+				e.EstimatedBytes = walker.unreadable(path)
+			}
+			report.add(e)
+			s.logger.Warn("Cannot read path", "path", path, "kind", e.Kind, "error", err)
+			return nil
 		}
 
 		// Skip the root directory itself
@@ -349,42 +664,72 @@ func (s *Scanner) scanPath(rule *config.PathRule, diskUsage float64) ([]Candidat
 			}
 		}
 
-		// Calculate file age (only if needed by any condition)
-		var ageInDays int
-		if needsAgeScan || isStackedActive {
-			ageInDays = int(time.Since(info.ModTime()).Hours() / 24)
+		// A directory holding a nested root cannot be removed without removing the other rule's files
+		if info.IsDir() && walker.holdsPruned(path) {
+			return nil
 		}
 
-		// Evaluate deletion reasons for this file/directory
-		reason := s.evaluateDeletionReason(rule, ageInDays, diskUsage, info)
-
-		// Only add as candidate if at least one reason applies
-		if reason.HasReason() {
-			candidate := Candidate{
-				Path:           path,
-				Size:           info.Size(),
-				ModTime:        info.ModTime(),
-				IsDir:          info.IsDir(),
-				IsEmptyDir:     false, // Will be determined later if it's a directory
-				DeletionReason: reason,
+		// GFS decides once it has seen every file it manages
+		if rule.HasGFS() {
+			if f, ok := gfsFileFor(rule.GFS, path, info); ok {
+				managed = append(managed, f)
 			}
+			return nil
+		}
+		if rule.HasKeepLatest() {
+			if f, ok := versionFileFor(rule.KeepLatest, path, info); ok {
+				versions = append(versions, f)
+			}
+			return nil
+		}
 
-			candidates = append(candidates, candidate)
-
-			s.logger.Debug("File selected for deletion",
-				"path", path,
-				"size", info.Size(),
-				"age_days", ageInDays,
-				"reason", reason.ToLogString(),
-			)
+		// Calculate file age (only if needed by any condition)
+		ageAt, source := fileAge(rule, path, info)
+		var age time.Duration
+		if needsAgeScan || isStackedActive || level > 0 {
+			age = time.Since(ageAt)
 		}
 
+		// Evaluate deletion reasons for this file/directory
+		consider(path, info, ageAt, source, s.evaluateDeletionReason(rule, level, age, diskUsage, info, open.holds(info)))
 		return nil
-	})
+	}
 
-	if err != nil {
+	if err := walker.walkTree(rule.Path); err != nil {
 		return nil, fmt.Errorf("failed to scan path %s: %w", rule.Path, err)
 	}
+	if rule.HasGFS() {
+		displaced := gfsSelect(rule.GFS, managed)
+		for _, f := range managed {
+			if r, ok := displaced[f.path]; ok {
+				reason := DeletionReason{PathRule: rule.Path, EvaluatedAt: now, GFS: r}
+				ageAt, source := fileAge(rule, f.path, f.info)
+				consider(f.path, f.info, ageAt, source, applyRetentionFloor(rule, reason, f.info))
+			}
+		}
+	}
+	if rule.HasKeepLatest() {
+		selected := keepLatestSelect(rule.KeepLatest, versions)
+		for _, f := range versions {
+			if r, ok := selected[f.path]; ok {
+				reason := DeletionReason{PathRule: rule.Path, EvaluatedAt: now, KeepLatest: r}
+				ageAt, source := fileAge(rule, f.path, f.info)
+				consider(f.path, f.info, ageAt, source, applyRetentionFloor(rule, reason, f.info))
+			}
+		}
+	}
+	if s.index != nil && fullRescan {
+		s.index.MarkFullRescan(rule.Path, now)
+	}
+	if s.fileCounts != nil {
+		s.fileCounts[rule.Path] = walker.filesSeen
+	}
+	if s.scanErrors != nil {
+		s.scanErrors[rule.Path] = report
+	}
+	if s.retention != nil && rule.MinRetentionDays > 0 {
+		s.retention[rule.Path] = hold
+	}
 
 	// Check for empty directories
 	candidates = s.markEmptyDirectories(candidates)
@@ -392,6 +737,11 @@ func (s *Scanner) scanPath(rule *config.PathRule, diskUsage float64) ([]Candidat
 	s.logger.Info("Path scan complete",
 		"path", rule.Path,
 		"candidates_found", len(candidates),
+		"dirs_skipped", walker.dirsSkipped,
+		"full_rescan", fullRescan,
+		"unreadable", report.Total(),
+		"retention_held", hold.Files,
+		"skipped_bytes_estimate", report.SkippedBytes,
 	)
 
 	return candidates, nil
@@ -0,0 +1,165 @@
+package scan
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"storage-sage/internal/config"
+)
+
+// Cleanup modes of rules without tiers; tiered rules report TIER-n
+const (
+	ModeAge   = "AGE"
+	ModeDisk  = "DISK"
+	ModeStack = "STACK"
+)
+
+// TierState is the escalation tier a rule is held at between cycles
+type TierState struct {
+	Level int       // 1-based position in the rule's tiers; 0 when no tier is engaged
+	Since time.Time // When the rule entered Level
+}
+
+// TierReason indicates file was selected by the rule's engaged escalation tier.
+type TierReason struct {
+	Level         int           // 1-based position of the tier in the rule's ladder
+	UsagePercent  float64       // usage_percent of the tier
+	MinAgeDays    int           // min age of the tier, in whole days
+	Strategy      string        // eviction strategy of the tier
+	ActualPercent float64       // actual disk usage at scan time
+	ActualAgeDays int           // actual file age at scan time, in whole days
+	MinAge        time.Duration // min age of the tier
+	Age           time.Duration // actual file age at scan time
+	Held          bool          // usage is below the tier's threshold; hysteresis or cooldown keeps it engaged
+}
+
+// TierMode returns the cleanup mode label of a tier level
+func TierMode(level int) string {
+	return fmt.Sprintf("TIER-%d", level)
+}
+
+// nextTier returns the tier rule is at with usage percent used at now, given
+// where it was held before. Escalation is immediate. Stepping down waits
+// until usage falls tier_hysteresis_percent below the held tier's threshold
+// and the rule has spent tier_cooldown_minutes at it.
+func nextTier(rule *config.PathRule, usage float64, prev TierState, now time.Time) TierState {
+	raw := 0
+	for i, t := range rule.Tiers {
+		if usage >= t.UsagePercent {
+			raw = i + 1
+		}
+	}
+	held := prev.Level
+	if held > len(rule.Tiers) {
+		held = len(rule.Tiers)
+	}
+	switch {
+	case raw >= held:
+		if raw == prev.Level {
+			return prev
+		}
+		return TierState{Level: raw, Since: now}
+	case now.Sub(prev.Since) < rule.TierCooldown():
+		return TierState{Level: held, Since: prev.Since}
+	}
+
+	// Step down to the highest tier still within its hysteresis band
+	level := raw
+	for i := held; i > raw; i-- {
+		if usage >= rule.Tiers[i-1].UsagePercent-rule.TierHysteresisPercent {
+			level = i
+			break
+		}
+	}
+	if level == held {
+		return TierState{Level: held, Since: prev.Since}
+	}
+	return TierState{Level: level, Since: now}
+}
+
+// tierReason returns the reason the highest delete tier engaged at level
+// selects an entry of the given age, or nil
+func tierReason(rule *config.PathRule, level int, age time.Duration, diskUsage float64) *TierReason {
+	level = rule.EngagedTier(level, config.ActionDelete)
+	if level <= 0 {
+		return nil
+	}
+	t := rule.Tiers[level-1]
+	if age < t.MinAgeLimit() {
+		return nil
+	}
+	strategy := t.Strategy
+	if strategy == "" {
+		strategy = config.StrategyOldest
+	}
+	return &TierReason{
+		Level:         level,
+		UsagePercent:  t.UsagePercent,
+		MinAgeDays:    wholeDays(t.MinAgeLimit()),
+		Strategy:      strategy,
+		ActualPercent: diskUsage,
+		ActualAgeDays: wholeDays(age),
+		MinAge:        t.MinAgeLimit(),
+		Age:           age,
+		Held:          diskUsage < t.UsagePercent,
+	}
+}
+
+// ruleMode returns the cleanup mode a rule runs in at diskUsage and tier
+// level, and how far up its own ladder that is (0-1) so that rules with
+// different numbers of tiers compare; rules without tiers have two steps
+func ruleMode(rule *config.PathRule, level int, diskUsage float64) (string, float64) {
+	if rule.KeepsByCount() {
+		return ModeAge, 0
+	}
+	if rule.Tiered() {
+		if level > 0 {
+			return TierMode(level), float64(level) / float64(len(rule.Tiers))
+		}
+		return ModeAge, 0
+	}
+	switch {
+	case diskUsage >= float64(rule.StackThreshold):
+		return ModeStack, 1
+	case diskUsage >= float64(rule.MaxFreePercent):
+		return ModeDisk, 0.5
+	}
+	return ModeAge, 0
+}
+
+// orderByStrategy reorders, in place, the candidates selected by tiers with
+// the largest strategy: each rule's such candidates keep the positions they
+// hold in the oldest-first order but are filled biggest first
+func orderByStrategy(candidates []Candidate) {
+	slots := make(map[string][]int)
+	for i, c := range candidates {
+		if t := c.DeletionReason.Tier; t != nil && t.Strategy == config.StrategyLargest {
+			slots[c.DeletionReason.PathRule] = append(slots[c.DeletionReason.PathRule], i)
+		}
+	}
+	for _, idx := range slots {
+		group := make([]Candidate, len(idx))
+		for k, i := range idx {
+			group[k] = candidates[i]
+		}
+		sort.SliceStable(group, func(a, b int) bool { return group[a].Size > group[b].Size })
+		for k, i := range idx {
+			candidates[i] = group[k]
+		}
+	}
+}
+
+// SetTierStates carries the tiers rules were held at by the previous cycle
+// into this scanner; Scan updates the map in place
+func (s *Scanner) SetTierStates(states map[string]TierState) {
+	s.tiers = states
+}
+
+// Mode returns the most critical cleanup mode across the rules of the last Scan
+func (s *Scanner) Mode() string {
+	if s.mode == "" {
+		return ModeAge
+	}
+	return s.mode
+}
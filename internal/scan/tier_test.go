@@ -0,0 +1,167 @@
+package scan
+
+import (
+	"testing"
+	"time"
+
+	"storage-sage/internal/config"
+)
+
+func tieredRule() *config.PathRule {
+	return &config.PathRule{
+		Path: "/data",
+		Tiers: []config.Tier{
+			{UsagePercent: 85, MinAgeDays: 30},
+			{UsagePercent: 92, MinAgeDays: 7},
+			{UsagePercent: 98, MinAgeDays: 1, Strategy: config.StrategyLargest},
+		},
+		TierHysteresisPercent: 2,
+		TierCooldownMinutes:   30,
+	}
+}
+
+// TestNextTier verifies tiers escalate at once and step down only past the
+// hysteresis band and after the cooldown
+func TestNextTier(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	rule := tieredRule()
+
+	steps := []struct {
+		after     time.Duration
+		usage     float64
+		wantLevel int
+	}{
+		{0, 80, 0},
+		{15 * time.Minute, 93, 2},   // escalate straight past tier 1
+		{30 * time.Minute, 99, 3},   // escalate again without waiting for the cooldown
+		{45 * time.Minute, 91, 3},   // below tier 3 but still cooling down
+		{70 * time.Minute, 96.5, 3}, // cooled down but within tier 3's hysteresis band
+		{75 * time.Minute, 91, 2},   // out of tier 3's band, within tier 2's
+		{90 * time.Minute, 80, 2},   // cooldown restarted when tier 2 was entered
+		{110 * time.Minute, 80, 0},  // below every band
+		{115 * time.Minute, 85, 1},
+		{150 * time.Minute, 84, 1}, // hysteresis keeps tier 1 engaged at its boundary
+	}
+
+	var state TierState
+	for _, step := range steps {
+		now := start.Add(step.after)
+		state = nextTier(rule, step.usage, state, now)
+		if state.Level != step.wantLevel {
+			t.Fatalf("at +%s with usage %.1f%%: level %d, want %d", step.after, step.usage, state.Level, step.wantLevel)
+		}
+	}
+}
+
+func TestEvaluateReasonWithTiers(t *testing.T) {
+	rule := tieredRule()
+	rule.AgeOffDays = 90
+
+	tests := []struct {
+		name      string
+		level     int
+		ageInDays int
+		usage     float64
+		wantTier  int // 0 expects no tier reason
+		wantAge   bool
+		wantHeld  bool
+	}{
+		{name: "no tier engaged", level: 0, ageInDays: 40, usage: 80},
+		{name: "too young for tier 1", level: 1, ageInDays: 20, usage: 86},
+		{name: "tier 1", level: 1, ageInDays: 40, usage: 86, wantTier: 1},
+		{name: "tier 2 held by hysteresis", level: 2, ageInDays: 8, usage: 91, wantTier: 2, wantHeld: true},
+		{name: "age rule alongside tier", level: 3, ageInDays: 100, usage: 99, wantTier: 3, wantAge: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := evaluateReason(rule, tt.level, time.Duration(tt.ageInDays)*config.Day, tt.usage)
+			if r.DiskThreshold != nil || r.StackedCleanup != nil {
+				t.Errorf("tiered rule produced legacy reasons: %s", r.ToLogString())
+			}
+			gotTier := 0
+			if r.Tier != nil {
+				gotTier = r.Tier.Level
+				if r.Tier.Held != tt.wantHeld {
+					t.Errorf("Held = %v, want %v", r.Tier.Held, tt.wantHeld)
+				}
+			}
+			if gotTier != tt.wantTier {
+				t.Errorf("tier = %d, want %d (%s)", gotTier, tt.wantTier, r.ToLogString())
+			}
+			if (r.AgeThreshold != nil) != tt.wantAge {
+				t.Errorf("age reason = %v, want %v", r.AgeThreshold != nil, tt.wantAge)
+			}
+		})
+	}
+}
+
+func TestRuleMode(t *testing.T) {
+	legacy := &config.PathRule{MaxFreePercent: 90, StackThreshold: 98}
+	tests := []struct {
+		rule     *config.PathRule
+		level    int
+		usage    float64
+		wantMode string
+	}{
+		{legacy, 0, 50, ModeAge},
+		{legacy, 0, 91, ModeDisk},
+		{legacy, 0, 99, ModeStack},
+		{tieredRule(), 0, 99, ModeAge}, // the level, not the usage, decides for tiered rules
+		{tieredRule(), 2, 93, "TIER-2"},
+	}
+	for _, tt := range tests {
+		if mode, _ := ruleMode(tt.rule, tt.level, tt.usage); mode != tt.wantMode {
+			t.Errorf("ruleMode(level %d, %.0f%%) = %s, want %s", tt.level, tt.usage, mode, tt.wantMode)
+		}
+	}
+}
+
+func TestRecheckLevel(t *testing.T) {
+	rule := tieredRule()
+	tests := []struct {
+		reason *TierReason
+		usage  float64
+		want   int
+	}{
+		{nil, 99, 0},
+		{&TierReason{Level: 3}, 97, 3},             // within tier 3's band
+		{&TierReason{Level: 3}, 93, 2},             // relieved to tier 2
+		{&TierReason{Level: 3}, 70, 0},             // relieved entirely
+		{&TierReason{Level: 2, Held: true}, 70, 2}, // the cycle held tier 2 on purpose
+	}
+	for _, tt := range tests {
+		if got := recheckLevel(rule, tt.reason, tt.usage); got != tt.want {
+			t.Errorf("recheckLevel(%+v, %.0f) = %d, want %d", tt.reason, tt.usage, got, tt.want)
+		}
+	}
+}
+
+func TestOrderByStrategy(t *testing.T) {
+	largest := func(rule string, size int64) Candidate {
+		return Candidate{Path: rule + "/f", Size: size, DeletionReason: DeletionReason{
+			PathRule: rule, Tier: &TierReason{Level: 3, Strategy: config.StrategyLargest}}}
+	}
+	oldest := func(rule string, size int64) Candidate {
+		return Candidate{Path: rule + "/f", Size: size, DeletionReason: DeletionReason{
+			PathRule: rule, Tier: &TierReason{Level: 1, Strategy: config.StrategyOldest}}}
+	}
+	// Oldest first as the scanner sorts them; /a's largest-strategy files
+	// are reordered within their own positions, everything else stays put
+	candidates := []Candidate{largest("/a", 1), oldest("/b", 5), largest("/a", 3), oldest("/b", 9), largest("/a", 2)}
+	orderByStrategy(candidates)
+
+	want := []int64{3, 5, 2, 9, 1}
+	for i, c := range candidates {
+		if c.Size != want[i] {
+			t.Fatalf("sizes after ordering = %v, want %v", sizes(candidates), want)
+		}
+	}
+}
+
+func sizes(candidates []Candidate) []int64 {
+	out := make([]int64, len(candidates))
+	for i, c := range candidates {
+		out[i] = c.Size
+	}
+	return out
+}
@@ -0,0 +1,128 @@
+package scan
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"storage-sage/internal/config"
+)
+
+// TruncateReason indicates an oversized file is truncated in place instead of
+// deleted. It is set alone: a file other reasons select is deleted instead,
+// unless a process holds it open and deleting it would free nothing.
+type TruncateReason struct {
+	Level         int           // 1-based position of the truncate tier; 0 outside tiers
+	UsagePercent  float64       // usage_percent of the tier, or stack_threshold for truncate.stack
+	MinAgeDays    int           // min age of the tier, in whole days
+	ActualPercent float64       // actual disk usage at scan time
+	ActualAgeDays int           // actual file age at scan time, in whole days
+	MinAge        time.Duration // min age of the tier
+	Age           time.Duration // actual file age at scan time
+	Size          int64         // file size at scan time
+	MaxSize       int64         // truncate.max_size_bytes from config
+	KeepTail      int64         // truncate.keep_tail_bytes from config
+	Stack         bool          // selected in STACK mode by truncate.stack
+	Open          bool          // a process held the file open at scan time
+	Held          bool          // usage is below the tier's threshold; hysteresis or cooldown keeps it engaged
+}
+
+// truncateReason returns the reason rule truncates an entry of the given age,
+// before its size is known, or nil: a rule with truncate tiers truncates while
+// one is engaged, truncate.stack in STACK mode, and any other at every usage
+func truncateReason(rule *config.PathRule, level int, age time.Duration, diskUsage float64) *TruncateReason {
+	t := rule.Truncate
+	if t == nil {
+		return nil
+	}
+	r := &TruncateReason{
+		ActualPercent: diskUsage,
+		ActualAgeDays: wholeDays(age),
+		Age:           age,
+		MaxSize:       t.MaxSizeBytes,
+		KeepTail:      t.KeepTailBytes,
+	}
+	switch {
+	case rule.TruncateTiered():
+		i := rule.EngagedTier(level, config.ActionTruncate)
+		if i == 0 {
+			return nil
+		}
+		tier := rule.Tiers[i-1]
+		if age < tier.MinAgeLimit() {
+			return nil
+		}
+		r.Level, r.UsagePercent, r.Held = i, tier.UsagePercent, diskUsage < tier.UsagePercent
+		r.MinAge, r.MinAgeDays = tier.MinAgeLimit(), wholeDays(tier.MinAgeLimit())
+	case t.Stack:
+		if rule.Tiered() || diskUsage < float64(rule.StackThreshold) {
+			return nil
+		}
+		r.Stack, r.UsagePercent = true, float64(rule.StackThreshold)
+	}
+	return r
+}
+
+// settleAction leaves the entry one action. A truncation applies only to a
+// regular file over the size limit: it replaces the deletes of a file held
+// open, which deleting would not free, and yields to them otherwise.
+// Compression applies only when nothing else does.
+func settleAction(rule *config.PathRule, reason *DeletionReason, name string, mode os.FileMode, size int64, open bool) {
+	if t := reason.Truncate; t != nil {
+		switch {
+		case !mode.IsRegular() || size <= t.MaxSize:
+			reason.Truncate = nil
+		case open:
+			t.Size, t.Open = size, true
+			*reason = DeletionReason{PathRule: reason.PathRule, EvaluatedAt: reason.EvaluatedAt, Truncate: t}
+		case reason.deletes():
+			reason.Truncate = nil
+		default:
+			t.Size = size
+		}
+	}
+	if reason.Compress != nil && (reason.deletes() || reason.Truncate != nil || !compressible(rule, name, mode)) {
+		reason.Compress = nil
+	}
+}
+
+// fileKey identifies a file by device and inode
+type fileKey struct {
+	dev, ino uint64
+}
+
+// openSet holds the files some process had open when it was read
+type openSet map[fileKey]struct{}
+
+// holds reports whether info is one of the open files
+func (s openSet) holds(info os.FileInfo) bool {
+	if len(s) == 0 {
+		return false
+	}
+	dev, ino, ok := fileID(info)
+	if !ok {
+		return false
+	}
+	_, found := s[fileKey{dev, ino}]
+	return found
+}
+
+// logString formats the reason for ToLogString.
+// Example: "truncate_3: disk_usage=98.6% (threshold=98.0%), age=2d (min=1d), size=5368709120 (max=1073741824), keep_tail=1048576, open"
+func (r *TruncateReason) logString() string {
+	var prefix string
+	switch {
+	case r.Level > 0:
+		age, minAge := ageStrings(r.Age, r.MinAge, r.ActualAgeDays, r.MinAgeDays)
+		prefix = fmt.Sprintf("truncate_%d: disk_usage=%.1f%% (threshold=%.1f%%), age=%s (min=%s),", r.Level, r.ActualPercent, r.UsagePercent, age, minAge)
+	case r.Stack:
+		prefix = fmt.Sprintf("truncate: disk_usage=%.1f%% (stack_threshold=%.1f%%),", r.ActualPercent, r.UsagePercent)
+	default:
+		prefix = "truncate:"
+	}
+	s := fmt.Sprintf("%s size=%d (max=%d), keep_tail=%d", prefix, r.Size, r.MaxSize, r.KeepTail)
+	if r.Open {
+		s += ", open"
+	}
+	return s
+}
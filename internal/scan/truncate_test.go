@@ -0,0 +1,76 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"storage-sage/internal/config"
+)
+
+// TestScanTruncate verifies truncate selects oversized regular files, yields
+// to a delete unless a process holds the file open, and survives Reevaluate
+func TestScanTruncate(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("open files are read from /proc")
+	}
+	root := t.TempDir()
+	big := strings.Repeat("x", 200)
+	files := map[string]string{
+		filepath.Join(root, "big.log"):          big,
+		filepath.Join(root, "small.log"):        "x",
+		filepath.Join(root, "old-big.log"):      big,
+		filepath.Join(root, "old-open-big.log"): big,
+	}
+	writeFiles(t, files)
+	old := time.Now().Add(-40 * config.Day)
+	for _, name := range []string{"old-big.log", "old-open-big.log"} {
+		if err := os.Chtimes(filepath.Join(root, name), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f, err := os.Open(filepath.Join(root, "old-open-big.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	rule := &config.PathRule{
+		Path:           root,
+		MaxAge:         config.Duration(30 * config.Day),
+		MaxFreePercent: 90,
+		StackThreshold: 98,
+		Truncate:       &config.Truncate{MaxSizeBytes: 100, KeepTailBytes: 10},
+	}
+	candidates, err := NewScanner(nil).scanPath(rule, 40, time.Now())
+	if err != nil {
+		t.Fatalf("scanPath: %v", err)
+	}
+	got := make(map[string]Candidate)
+	for _, c := range candidates {
+		got[filepath.Base(c.Path)] = c
+	}
+	if len(got) != 3 {
+		t.Fatalf("selected %d entries, want big.log, old-big.log and old-open-big.log", len(got))
+	}
+	if r := got["big.log"].DeletionReason; r.Truncate == nil || r.Truncate.Size != 200 || r.Truncate.Open || r.GetPrimaryReason() != "truncate" {
+		t.Errorf("big.log: reason %s, want truncate alone", r.ToLogString())
+	}
+	if r := got["old-big.log"].DeletionReason; r.Truncate != nil || r.AgeThreshold == nil {
+		t.Errorf("old-big.log: reason %s, want age_threshold: deleting frees it", r.ToLogString())
+	}
+	if r := got["old-open-big.log"].DeletionReason; r.Truncate == nil || !r.Truncate.Open || r.AgeThreshold != nil {
+		t.Errorf("old-open-big.log: reason %s, want truncate of an open file", r.ToLogString())
+	}
+
+	// A file chosen for truncation is rechecked as one, even once a delete applies
+	for _, name := range []string{"big.log", "old-open-big.log"} {
+		c := got[name]
+		if r := Reevaluate(c, rule, 95, time.Now()); r.Truncate == nil || r.DiskThreshold != nil {
+			t.Errorf("Reevaluate(%s) = %s, want truncate alone", name, r.ToLogString())
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package scan
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"storage-sage/internal/config"
+)
+
+// Changed describes how info, a fresh lstat of cand.Path, differs from what
+// the scan saw; it returns "" when the entry is the same one. Directory
+// mtimes are not compared because removing their children updates them.
+func Changed(cand Candidate, info os.FileInfo) string {
+	if t := info.Mode().Type(); t != cand.Type || info.IsDir() != cand.IsDir {
+		return fmt.Sprintf("type %v -> %v", cand.Type, t)
+	}
+	if cand.HasFileID {
+		if dev, ino, ok := fileID(info); ok && (dev != cand.Dev || ino != cand.Inode) {
+			return fmt.Sprintf("inode %d -> %d", cand.Inode, ino)
+		}
+	}
+	// A file selected for truncation is typically a live log still being written
+	if cand.DeletionReason.Truncate != nil {
+		return ""
+	}
+	if !cand.IsDir && !info.ModTime().Equal(cand.ModTime) {
+		return fmt.Sprintf("mtime %s -> %s", cand.ModTime.Format(time.RFC3339), info.ModTime().Format(time.RFC3339))
+	}
+	// An entry aged by atime or ctime is younger once it has been read or changed
+	if src := cand.AgeSource; !cand.IsDir && (src == config.AgeSourceAtime || src == config.AgeSourceCtime) {
+		if t, ok := statTime(info, src); ok && !t.Equal(cand.AgeTime) {
+			return fmt.Sprintf("%s %s -> %s", src, cand.AgeTime.Format(time.RFC3339), t.Format(time.RFC3339))
+		}
+	}
+	return ""
+}
+
+// Reevaluate recomputes cand's deletion reason under rule at diskUsage percent
+// used, as the scanner would have at now
+func Reevaluate(cand Candidate, rule *config.PathRule, diskUsage float64, now time.Time) DeletionReason {
+	// What GFS keeps does not depend on disk usage, and Changed catches a rewritten file
+	if cand.DeletionReason.GFS != nil && rule.HasGFS() {
+		reason := cand.DeletionReason
+		reason.EvaluatedAt = now
+		return reason
+	}
+	// Nor does which versions keep_latest keeps; a newer version arriving can only select more
+	if cand.DeletionReason.KeepLatest != nil && rule.HasKeepLatest() {
+		reason := cand.DeletionReason
+		reason.EvaluatedAt = now
+		return reason
+	}
+	ageAt, source := cand.AgeTime, cand.AgeSource
+	if ageAt.IsZero() {
+		ageAt, source = cand.ModTime, config.AgeSourceMtime
+	}
+	held := cand.DeletionReason.Tier
+	if c := cand.DeletionReason.Compress; c != nil {
+		held = &TierReason{Level: c.Level, Held: c.Held}
+	}
+	if t := cand.DeletionReason.Truncate; t != nil && t.Level > 0 {
+		held = &TierReason{Level: t.Level, Held: t.Held}
+	}
+	reason := evaluateReason(rule, recheckLevel(rule, held, diskUsage), now.Sub(ageAt), diskUsage)
+	open := false
+	if t := cand.DeletionReason.Truncate; t != nil {
+		// Changed let its writes through, so it may be truncated but nothing else;
+		// whether a process holds it open is not checked again
+		reason = DeletionReason{PathRule: reason.PathRule, EvaluatedAt: reason.EvaluatedAt, Truncate: reason.Truncate}
+		open = t.Open
+	}
+	settleAction(rule, &reason, filepath.Base(cand.Path), cand.Type, cand.Size, open)
+	recordAgeSource(&reason, source)
+	return reason
+}
+
+// recheckLevel returns the tier still engaged at diskUsage for a candidate
+// selected by r. A tier the cycle held below its threshold stays engaged; any
+// other steps down to the highest tier within its hysteresis band.
+func recheckLevel(rule *config.PathRule, r *TierReason, diskUsage float64) int {
+	if r == nil || r.Level > len(rule.Tiers) {
+		return 0
+	}
+	if r.Held {
+		return r.Level
+	}
+	for i := r.Level; i > 0; i-- {
+		if diskUsage >= rule.Tiers[i-1].UsagePercent-rule.TierHysteresisPercent {
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,224 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"storage-sage/internal/config"
+	"storage-sage/internal/index"
+)
+
+// treeWalker walks a rule root with filepath.Walk semantics (lexical order,
+// symlinks not followed, errors reported to the walk function) and, when an
+// index is attached, skips reading directories that cannot hold a candidate.
+type treeWalker struct {
+	idx    *index.Index // nil when incremental scanning is disabled
+	cutoff time.Time    // files modified after cutoff cannot qualify; zero disables skipping
+	walkFn filepath.WalkFunc
+	pace   func()          // called before each lstat so the walk honours the CPU budget; may be nil
+	prune  map[string]bool // roots of nested rules; their subtrees are neither visited nor dropped from the index
+
+	seen        map[string]bool // directories visited during this walk (for index pruning)
+	dirsSkipped int             // directories whose listing was served from the index
+	filesSeen   int64           // non-directory entries under the root, including those counted from the index
+}
+
+// walkTree walks root and calls walkFn for every entry, like filepath.Walk
+func (w *treeWalker) walkTree(root string) error {
+	if w.idx != nil {
+		w.seen = make(map[string]bool)
+	}
+
+	info, err := os.Lstat(root)
+	if err != nil {
+		err = w.walkFn(root, nil, err)
+	} else {
+		err = w.walk(root, info)
+	}
+	if err == filepath.SkipDir || err == filepath.SkipAll {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if w.idx != nil {
+		// Nested roots are walked by their own rules, which keep their index entries current
+		for nested := range w.prune {
+			dirs, _ := w.idx.Subtree(nested)
+			for _, d := range dirs {
+				w.seen[d] = true
+			}
+		}
+		w.idx.Retain(root, w.seen)
+	}
+	return nil
+}
+
+func (w *treeWalker) walk(path string, info os.FileInfo) error {
+	if w.prune[path] {
+		return nil
+	}
+	if !info.IsDir() {
+		w.filesSeen++
+		return w.walkFn(path, info, nil)
+	}
+
+	if w.seen != nil {
+		w.seen[path] = true
+	}
+
+	if entry, ok := w.unchanged(path, info); ok {
+		return w.walkFromIndex(path, info, entry)
+	}
+
+	names, err := readDirNames(path)
+	err1 := w.walkFn(path, info, err)
+	if err != nil || err1 != nil {
+		return err1
+	}
+
+	summary := &index.DirEntry{ModTime: info.ModTime().UnixNano()}
+	for _, name := range names {
+		if w.pace != nil {
+			w.pace()
+		}
+		filename := filepath.Join(path, name)
+		fileInfo, err := os.Lstat(filename)
+		if err != nil {
+			if err := w.walkFn(filename, fileInfo, err); err != nil && err != filepath.SkipDir {
+				return err
+			}
+			// The summary is incomplete, so it must not be used to skip this directory later
+			summary = nil
+			continue
+		}
+
+		if summary != nil {
+			if fileInfo.IsDir() {
+				summary.Subdirs = append(summary.Subdirs, name)
+			} else {
+				summary.Files++
+				summary.Bytes += fileInfo.Size()
+				mod := fileInfo.ModTime().UnixNano()
+				if summary.OldestMod == 0 || mod < summary.OldestMod {
+					summary.OldestMod = mod
+				}
+			}
+		}
+
+		err = w.walk(filename, fileInfo)
+		if err != nil {
+			if !fileInfo.IsDir() || err != filepath.SkipDir {
+				return err
+			}
+		}
+	}
+
+	if w.idx != nil && summary != nil {
+		w.idx.Put(path, summary)
+	}
+	return nil
+}
+
+// unchanged returns the index entry for dir when its listing is known to be
+// unchanged and none of its files can meet the current age cutoff.
+func (w *treeWalker) unchanged(dir string, info os.FileInfo) (*index.DirEntry, bool) {
+	if w.idx == nil || w.cutoff.IsZero() {
+		return nil, false
+	}
+	entry, ok := w.idx.Lookup(dir)
+	if !ok || entry.ModTime != info.ModTime().UnixNano() {
+		return nil, false
+	}
+	if !entry.CannotHoldOlderThan(w.cutoff) {
+		return nil, false
+	}
+	return entry, true
+}
+
+// walkFromIndex visits dir and descends into its indexed subdirectories
+// without reading the directory listing.
+func (w *treeWalker) walkFromIndex(dir string, info os.FileInfo, entry *index.DirEntry) error {
+	w.dirsSkipped++
+	w.filesSeen += entry.Files
+
+	if err := w.walkFn(dir, info, nil); err != nil {
+		return err
+	}
+
+	for _, name := range entry.Subdirs {
+		if w.pace != nil {
+			w.pace()
+		}
+		child := filepath.Join(dir, name)
+		childInfo, err := os.Lstat(child)
+		if err != nil || !childInfo.IsDir() {
+			// The listing changed underneath us; the next cycle will notice the new mtime
+			continue
+		}
+		if err := w.walk(child, childInfo); err != nil && err != filepath.SkipDir {
+			return err
+		}
+	}
+	return nil
+}
+
+// unreadable keeps the index entries at and below dir, which could not be
+// read, and returns the bytes they held at their last listing as an estimate
+// of what the walk missed. Subtrees of nested rules are left out, and without
+// an index there is nothing to estimate from.
+func (w *treeWalker) unreadable(dir string) int64 {
+	if w.idx == nil {
+		return 0
+	}
+	dirs, _ := w.idx.Subtree(dir)
+	var bytes int64
+	for _, d := range dirs {
+		if w.pruned(d) {
+			continue
+		}
+		w.seen[d] = true
+		if e, ok := w.idx.Lookup(d); ok {
+			bytes += e.Bytes
+		}
+	}
+	return bytes
+}
+
+// holdsPruned reports whether a nested rule's root lies below dir
+func (w *treeWalker) holdsPruned(dir string) bool {
+	for root := range w.prune {
+		if config.Within(root, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// pruned reports whether dir lies in the subtree of a nested rule
+func (w *treeWalker) pruned(dir string) bool {
+	for root := range w.prune {
+		if config.Within(dir, root) {
+			return true
+		}
+	}
+	return false
+}
+
+// readDirNames reads the directory and returns a sorted list of entry names
+func readDirNames(dirname string) ([]string, error) {
+	f, err := os.Open(dirname)
+	if err != nil {
+		return nil, err
+	}
+	names, err := f.Readdirnames(-1)
+	_ = f.Close()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	return names, nil
+}
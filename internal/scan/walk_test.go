@@ -0,0 +1,101 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"storage-sage/internal/config"
+	"storage-sage/internal/index"
+)
+
+// TestIncrementalScanSkipsUnchangedDirectories verifies that a second cycle
+// serves unchanged directories from the index and still notices new old files
+func TestIncrementalScanSkipsUnchangedDirectories(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "sub")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	for _, p := range []string{filepath.Join(root, "a.log"), filepath.Join(sub, "b.log")} {
+		if err := os.WriteFile(p, []byte("fresh"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", p, err)
+		}
+	}
+
+	idx, err := index.Open(filepath.Join(t.TempDir(), "idx.gob"))
+	if err != nil {
+		t.Fatalf("index.Open: %v", err)
+	}
+
+	rule := &config.PathRule{
+		Path:           root,
+		AgeOffDays:     7,
+		MaxFreePercent: 100,
+		StackThreshold: 100,
+		StackAgeDays:   14,
+	}
+	s := NewScanner(nil)
+	s.SetIndex(idx, 24*time.Hour)
+	now := time.Now()
+
+	// First cycle: full walk builds the index
+	candidates, err := s.scanPath(rule, 50, now)
+	if err != nil {
+		t.Fatalf("first scan: %v", err)
+	}
+	if len(candidates) != 0 {
+		t.Fatalf("expected no candidates on fresh tree, got %d", len(candidates))
+	}
+	if idx.Len() != 2 {
+		t.Fatalf("expected 2 indexed directories, got %d", idx.Len())
+	}
+
+	// Second cycle: nothing changed, both directories come from the index
+	walker := &treeWalker{idx: idx, cutoff: indexCutoff(rule, 0, false, false, now), walkFn: func(string, os.FileInfo, error) error { return nil }}
+	if err := walker.walkTree(root); err != nil {
+		t.Fatalf("indexed walk: %v", err)
+	}
+	if walker.dirsSkipped != 2 {
+		t.Errorf("expected 2 skipped directories, got %d", walker.dirsSkipped)
+	}
+
+	// Adding an old file changes the directory mtime, so it must be re-read
+	old := filepath.Join(sub, "old.log")
+	if err := os.WriteFile(old, []byte("old"), 0o644); err != nil {
+		t.Fatalf("write old: %v", err)
+	}
+	past := now.Add(-30 * 24 * time.Hour)
+	if err := os.Chtimes(old, past, past); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	// Make sure the directory mtime differs even on coarse-grained filesystems
+	if err := os.Chtimes(sub, now.Add(time.Second), now.Add(time.Second)); err != nil {
+		t.Fatalf("chtimes dir: %v", err)
+	}
+
+	candidates, err = s.scanPath(rule, 50, now)
+	if err != nil {
+		t.Fatalf("third scan: %v", err)
+	}
+	if len(candidates) != 1 || candidates[0].Path != old {
+		t.Fatalf("expected only %s as candidate, got %+v", old, candidates)
+	}
+}
+
+// TestIndexCutoffDisabledInDiskMode verifies every file is re-read when disk mode makes all files eligible
+func TestIndexCutoffDisabledInDiskMode(t *testing.T) {
+	rule := &config.PathRule{AgeOffDays: 7, StackAgeDays: 3}
+	now := time.Now()
+
+	if !indexCutoff(rule, 0, true, false, now).IsZero() {
+		t.Error("disk threshold mode must disable skipping")
+	}
+	if got := indexCutoff(rule, 0, false, true, now); !got.Equal(now.Add(-3 * 24 * time.Hour)) {
+		t.Errorf("stacked mode should use the smaller stack age, got %v", got)
+	}
+	if !indexCutoff(&config.PathRule{}, 0, false, false, now).IsZero() {
+		t.Error("a rule without age thresholds has no cutoff")
+	}
+}
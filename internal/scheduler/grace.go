@@ -0,0 +1,117 @@
+package scheduler
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"storage-sage/internal/database"
+	"storage-sage/internal/scan"
+)
+
+// applyReprieves drops candidates that users have reprieved. Reprieves are
+// read every cycle so the web UI and storage-sage-query take effect without a reload.
+func applyReprieves(db *database.DeletionDB, candidates []scan.Candidate, now time.Time, logger *log.Logger) ([]scan.Candidate, error) {
+	if db == nil {
+		return candidates, nil
+	}
+	reprieves, err := db.GetReprieves(now)
+	if err != nil {
+		return nil, err
+	}
+	if len(reprieves) == 0 {
+		return candidates, nil
+	}
+	paths := make([]string, 0, len(reprieves))
+	for _, r := range reprieves {
+		paths = append(paths, r.Path)
+	}
+	before := len(candidates)
+	candidates = dropReprieved(candidates, paths)
+	if n := before - len(candidates); n > 0 {
+		logger.Printf("skipping %d reprieved candidate(s)", n)
+	}
+	return candidates, nil
+}
+
+// dropReprieved removes candidates at or below a reprieved path, and
+// directories that contain one since removing them would remove the reprieved subtree
+func dropReprieved(candidates []scan.Candidate, reprieved []string) []scan.Candidate {
+	kept := candidates[:0]
+	for _, c := range candidates {
+		if deepestRoot(c.Path, reprieved) != "" {
+			continue
+		}
+		if c.IsDir && containsAny(c.Path, reprieved) {
+			continue
+		}
+		kept = append(kept, c)
+	}
+	return kept
+}
+
+// holdForGracePeriod records candidates as pending and returns the ones that
+// have been pending, unchanged in size and mtime, for the whole grace period.
+// Pending entries of rules that no longer select them are dropped. A dry run
+// reads the pending state without updating it.
+func holdForGracePeriod(db *database.DeletionDB, rules []string, candidates []scan.Candidate, now time.Time, grace time.Duration, dryRun bool, logger *log.Logger) ([]scan.Candidate, error) {
+	if db == nil {
+		logger.Printf("WARNING: grace period configured without a database, holding all %d candidate(s)", len(candidates))
+		return nil, nil
+	}
+	prev, err := db.GetPendingByRule(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, due := markPending(prev, candidates, now, grace)
+	if !dryRun {
+		if err := db.ReplacePending(rules, entries); err != nil {
+			return nil, err
+		}
+	}
+	logger.Printf("grace period %s: %d candidate(s) pending, %d due for removal", grace, len(entries)-len(due), len(due))
+	return due, nil
+}
+
+// markPending carries over the first-seen time of candidates that are still
+// pending unchanged and starts a new grace period for the rest
+func markPending(prev map[string]database.PendingDeletion, candidates []scan.Candidate, now time.Time, grace time.Duration) ([]database.PendingDeletion, []scan.Candidate) {
+	entries := make([]database.PendingDeletion, 0, len(candidates))
+	var due []scan.Candidate
+	seen := make(map[string]bool, len(candidates))
+	for _, c := range candidates {
+		if seen[c.Path] {
+			continue
+		}
+		seen[c.Path] = true
+
+		firstSeen := now
+		if p, ok := prev[c.Path]; ok && p.Size == c.Size && p.ModTime.Equal(c.ModTime) {
+			firstSeen = p.FirstSeen
+		}
+		entry := database.NewPendingDeletion(c, firstSeen, firstSeen.Add(grace))
+		entries = append(entries, entry)
+		if !entry.DueAt.After(now) {
+			due = append(due, c)
+		}
+	}
+	return entries, due
+}
+
+// clearRemoved drops the pending entries of due candidates that are gone.
+// Candidates that were skipped or failed stay pending and are retried next cycle.
+func clearRemoved(db *database.DeletionDB, due []scan.Candidate, logger *log.Logger) {
+	if db == nil {
+		return
+	}
+	var removed []string
+	for _, c := range due {
+		if _, err := os.Lstat(c.Path); os.IsNotExist(err) {
+			removed = append(removed, c.Path)
+		}
+	}
+	if err := db.ClearPending(removed); err != nil {
+		logger.Printf("failed to clear pending deletions: %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package scheduler
+
+import (
+	"io"
+	"log"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"storage-sage/internal/database"
+	"storage-sage/internal/scan"
+)
+
+func TestHoldForGracePeriod(t *testing.T) {
+	db, err := database.NewDeletionDB(filepath.Join(t.TempDir(), "deletions.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	logger := log.New(io.Discard, "", 0)
+
+	t0 := time.Now()
+	mod := t0.Add(-30 * 24 * time.Hour)
+	cand := func(path string, size int64) scan.Candidate {
+		return scan.Candidate{Path: path, Size: size, ModTime: mod, DeletionReason: scan.DeletionReason{PathRule: "/scratch"}}
+	}
+	paths := func(cs []scan.Candidate) []string {
+		var p []string
+		for _, c := range cs {
+			p = append(p, c.Path)
+		}
+		return p
+	}
+	rules := []string{"/scratch"}
+	grace := 24 * time.Hour
+
+	cycles := []struct {
+		name       string
+		at         time.Duration
+		candidates []scan.Candidate
+		dryRun     bool
+		wantDue    []string
+		wantQueued int
+	}{
+		{"first sighting", 0, []scan.Candidate{cand("/scratch/a", 1), cand("/scratch/b", 1)}, false, nil, 2},
+		{"b changed", 12 * time.Hour, []scan.Candidate{cand("/scratch/a", 1), cand("/scratch/b", 2)}, false, nil, 2},
+		{"dry run sees a due", 25 * time.Hour, []scan.Candidate{cand("/scratch/a", 1)}, true, []string{"/scratch/a"}, 2},
+		{"a due, b restarted", 25 * time.Hour, []scan.Candidate{cand("/scratch/a", 1), cand("/scratch/b", 2)}, false, []string{"/scratch/a"}, 2},
+		{"b no longer qualifies", 26 * time.Hour, []scan.Candidate{cand("/scratch/a", 1)}, false, []string{"/scratch/a"}, 1},
+		{"b qualifies again", 37 * time.Hour, []scan.Candidate{cand("/scratch/b", 2)}, false, nil, 1},
+	}
+	for _, c := range cycles {
+		due, err := holdForGracePeriod(db, rules, c.candidates, t0.Add(c.at), grace, c.dryRun, logger)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if got := paths(due); !reflect.DeepEqual(got, c.wantDue) {
+			t.Errorf("%s: due = %v, want %v", c.name, got, c.wantDue)
+		}
+		pending, err := db.GetPendingDeletions(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pending) != c.wantQueued {
+			t.Errorf("%s: %d pending, want %d", c.name, len(pending), c.wantQueued)
+		}
+	}
+}
+
+func TestDropReprieved(t *testing.T) {
+	candidates := []scan.Candidate{
+		{Path: "/scratch/alice/run1/out.csv"},
+		{Path: "/scratch/alice/run1", IsDir: true},
+		{Path: "/scratch/alice", IsDir: true},
+		{Path: "/scratch/alice2/out.csv"},
+		{Path: "/scratch/bob/out.csv"},
+	}
+	got := dropReprieved(candidates, []string{"/scratch/alice/run1", "/scratch/bob/out.csv"})
+
+	var paths []string
+	for _, c := range got {
+		paths = append(paths, c.Path)
+	}
+	want := []string{"/scratch/alice2/out.csv"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("dropReprieved kept %v, want %v", paths, want)
+	}
+}
@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"storage-sage/internal/cleanup"
+	"storage-sage/internal/database"
+	"storage-sage/internal/safety"
+)
+
+// holdRefresh returns a cleanup.HoldRefresh that reloads the holds of v from
+// db whenever one was placed or released after generation gen was read
+func holdRefresh(db *database.DeletionDB, v *safety.Validator, gen int64) cleanup.HoldRefresh {
+	return func() (bool, error) {
+		current, err := db.HoldsGeneration()
+		if err != nil {
+			return false, fmt.Errorf("reading legal holds: %w", err)
+		}
+		if current == gen {
+			return false, nil
+		}
+		holds, err := legalHolds(db, time.Now())
+		if err != nil {
+			return false, err
+		}
+		v.SetHolds(holds)
+		gen = current
+		return true, nil
+	}
+}
+
+// legalHolds reads the legal holds in force at now for the validator. Holds
+// are read every cycle, and again within it by holdRefresh when they change,
+// so new ones apply without a reload; a cycle that cannot read them does not
+// delete anything.
+func legalHolds(db *database.DeletionDB, now time.Time) ([]safety.LegalHold, error) {
+	if db == nil {
+		return nil, nil
+	}
+	holds, err := db.GetHolds(now, false)
+	if err != nil {
+		return nil, fmt.Errorf("reading legal holds: %w", err)
+	}
+	out := make([]safety.LegalHold, 0, len(holds))
+	for _, h := range holds {
+		out = append(out, safety.LegalHold{ID: h.ID, Pattern: h.Pattern, Owner: h.Owner, Reason: h.Reason})
+	}
+	return out, nil
+}
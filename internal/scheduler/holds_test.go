@@ -0,0 +1,74 @@
+package scheduler
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"storage-sage/internal/cleanup"
+	"storage-sage/internal/config"
+	"storage-sage/internal/database"
+	"storage-sage/internal/fsops"
+	"storage-sage/internal/safety"
+	"storage-sage/internal/scan"
+)
+
+// placingDeleter places a legal hold the first time it deletes something
+type placingDeleter struct {
+	fsops.FakeDeleter
+	place func()
+}
+
+func (d *placingDeleter) Remove(path string) error {
+	if d.place != nil {
+		d.place()
+		d.place = nil
+	}
+	return d.FakeDeleter.Remove(path)
+}
+
+// TestHoldPlacedDuringCycle verifies a hold placed while a cycle runs covers
+// the candidates that cycle has not reached yet
+func TestHoldPlacedDuringCycle(t *testing.T) {
+	root := t.TempDir()
+	db, err := database.NewDeletionDB(filepath.Join(t.TempDir(), "deletions.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	first, held := filepath.Join(root, "a.log"), filepath.Join(root, "case", "mail.eml")
+	for _, p := range []string{first, held} {
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deleter := &placingDeleter{place: func() {
+		if _, err := db.PlaceHold(database.LegalHold{Pattern: filepath.Join(root, "case"), Owner: "legal", Reason: "case 114"}); err != nil {
+			t.Error(err)
+		}
+	}}
+
+	gen, err := db.HoldsGeneration()
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator := safety.NewValidator([]string{root}, nil)
+	cleaner := cleanup.NewCleaner(log.Default(), nil, false, db)
+	cleaner.SetDeleter(deleter)
+	cleaner.SetValidator(validator)
+	cleaner.SetHoldRefresh(holdRefresh(db, validator, gen))
+
+	cfg := &config.Config{ScanPaths: []string{root}}
+	count, _, err := cleaner.CleanupWithConfig(cfg, []scan.Candidate{{Path: first, Size: 1}, {Path: held, Size: 1}})
+	if err != nil {
+		t.Fatalf("CleanupWithConfig: %v", err)
+	}
+	if count != 1 || len(deleter.Calls) != 1 || deleter.Calls[0] != "rm:"+first {
+		t.Errorf("deleted %d (%v), want only %s", count, deleter.Calls, first)
+	}
+}
@@ -0,0 +1,57 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"storage-sage/internal/config"
+	"storage-sage/internal/metrics"
+	"storage-sage/internal/safety"
+	"storage-sage/internal/scan"
+)
+
+// retentionFloors lists the retention floor of every root in cfg for the
+// validator, including roots without one so they lift an enclosing floor
+func retentionFloors(cfg *config.Config) []safety.RetentionFloor {
+	roots := configRoots(cfg)
+	floors := make([]safety.RetentionFloor, 0, len(roots))
+	for _, root := range roots {
+		rule := scan.RuleFor(cfg, root)
+		if rule == nil {
+			continue
+		}
+		floors = append(floors, safety.RetentionFloor{
+			Root:       root,
+			MinAge:     time.Duration(rule.MinRetentionDays) * 24 * time.Hour,
+			Compliance: rule.ComplianceMode,
+		})
+	}
+	return floors
+}
+
+// floorShortfalls publishes what each rule's retention floor kept and
+// explains each rule the floor kept from its target
+func floorShortfalls(targets map[string]int64, holds []*scan.RetentionHold, candidates []scan.Candidate) []string {
+	selectable := make(map[string]int64)
+	for _, c := range candidates {
+		if !c.IsDir {
+			selectable[c.DeletionReason.PathRule] += c.Size
+		}
+	}
+
+	var msgs []string
+	for _, h := range holds {
+		target := targets[h.PathRule]
+		missed := target > 0 && h.Files > 0 && selectable[h.PathRule] < target
+		metrics.SetRetentionFloor(h.PathRule, h.Bytes, missed)
+		if !missed {
+			continue
+		}
+		msgs = append(msgs, fmt.Sprintf(
+			"target not reached on %s: %d of %d bytes selectable; %d entries (%d bytes) are younger than the %d-day retention floor",
+			h.PathRule, selectable[h.PathRule], target, h.Files, h.Bytes, h.MinRetentionDays))
+	}
+	sort.Strings(msgs)
+	return msgs
+}
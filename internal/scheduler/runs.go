@@ -0,0 +1,57 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"storage-sage/internal/database"
+	"storage-sage/internal/metrics"
+	"storage-sage/internal/scan"
+)
+
+// publishScanErrors updates the per-rule scan error metrics and totals them for the run record
+func publishScanErrors(reports []*scan.ScanErrorReport, run *database.CycleRun) []scan.ScanError {
+	var entries []scan.ScanError
+	for _, r := range reports {
+		metrics.SetScanErrors(r.PathRule, scan.ScanErrorKinds, r.Counts, r.SkippedBytes)
+		run.ScanErrors += r.Total()
+		run.SkippedBytes += r.SkippedBytes
+		entries = append(entries, r.Errors...)
+	}
+	return entries
+}
+
+// scanShortfalls explains each rule that needs to free more than its
+// candidates hold while part of its tree could not be read
+func scanShortfalls(targets map[string]int64, reports []*scan.ScanErrorReport, candidates []scan.Candidate) []string {
+	selectable := make(map[string]int64)
+	for _, c := range candidates {
+		if !c.IsDir {
+			selectable[c.DeletionReason.PathRule] += c.Size
+		}
+	}
+
+	var msgs []string
+	for _, r := range reports {
+		target := targets[r.PathRule]
+		if target <= 0 || r.Total() == 0 || selectable[r.PathRule] >= target {
+			continue
+		}
+		msgs = append(msgs, fmt.Sprintf(
+			"target not reached on %s: %d of %d bytes selectable; %d unreadable entries (about %d bytes) were not scanned",
+			r.PathRule, selectable[r.PathRule], target, r.Total(), r.SkippedBytes))
+	}
+	sort.Strings(msgs)
+	return msgs
+}
+
+// recordRun stores the run record; failures are logged and do not fail the cycle
+func recordRun(db *database.DeletionDB, run database.CycleRun, scanErrors []scan.ScanError, logger *log.Logger) {
+	if db == nil {
+		return
+	}
+	if _, err := db.RecordRun(run, scanErrors); err != nil {
+		logger.Printf("failed to record cycle: %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+
+	"storage-sage/internal/scan"
+)
+
+func TestScanShortfalls(t *testing.T) {
+	cand := func(rule string, size int64) scan.Candidate {
+		return scan.Candidate{Path: rule + "/f", Size: size, DeletionReason: scan.DeletionReason{PathRule: rule}}
+	}
+	report := func(rule string, unreadable int) *scan.ScanErrorReport {
+		return &scan.ScanErrorReport{PathRule: rule, Counts: map[string]int{scan.ScanErrPermission: unreadable}, SkippedBytes: 500}
+	}
+
+	targets := map[string]int64{"/short": 1000, "/enough": 100, "/clean": 1000}
+	reports := []*scan.ScanErrorReport{report("/short", 2), report("/enough", 1), report("/clean", 0), report("/no-target", 4)}
+	candidates := []scan.Candidate{cand("/short", 300), cand("/enough", 200), cand("/clean", 10)}
+
+	msgs := scanShortfalls(targets, reports, candidates)
+	if len(msgs) != 1 || !strings.HasPrefix(msgs[0], "target not reached on /short: 300 of 1000 bytes") {
+		t.Errorf("shortfalls = %q, want one for /short", msgs)
+	}
+}
+
+func TestFloorShortfalls(t *testing.T) {
+	cand := func(rule string, size int64) scan.Candidate {
+		return scan.Candidate{Path: rule + "/f", Size: size, DeletionReason: scan.DeletionReason{PathRule: rule}}
+	}
+	hold := func(rule string, files int) *scan.RetentionHold {
+		return &scan.RetentionHold{PathRule: rule, MinRetentionDays: 7, Files: files, Bytes: int64(files) * 100}
+	}
+
+	targets := map[string]int64{"/short": 1000, "/enough": 100, "/nothing-held": 1000}
+	holds := []*scan.RetentionHold{hold("/short", 3), hold("/enough", 2), hold("/nothing-held", 0), hold("/no-target", 5)}
+	candidates := []scan.Candidate{cand("/short", 300), cand("/enough", 200), cand("/nothing-held", 10)}
+
+	msgs := floorShortfalls(targets, holds, candidates)
+	want := "target not reached on /short: 300 of 1000 bytes selectable; 3 entries (300 bytes) are younger than the 7-day retention floor"
+	if len(msgs) != 1 || msgs[0] != want {
+		t.Errorf("shortfalls = %q, want [%q]", msgs, want)
+	}
+}
@@ -4,23 +4,85 @@ import (
 	"context"
 	"errors"
 	"log"
+	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"storage-sage/internal/cleanup"
 	"storage-sage/internal/config"
 	"storage-sage/internal/database"
 	"storage-sage/internal/disk"
+	"storage-sage/internal/fsops"
+	"storage-sage/internal/index"
 	"storage-sage/internal/limiter"
 	"storage-sage/internal/metrics"
 	"storage-sage/internal/safety"
+	"storage-sage/internal/sandbox"
 	"storage-sage/internal/scan"
 )
 
+var (
+	// scanIndex is kept in memory between cycles so it is only decoded once per process
+	scanIndex   *index.Index
+	scanIndexMu sync.Mutex
+
+	// activeSandbox runs deletes on a Landlock-restricted thread when set
+	activeSandbox   *sandbox.Sandbox
+	activeSandboxMu sync.RWMutex
+
+	// tierStates holds the escalation tier of each tiered rule between cycles
+	tierStates   = make(map[string]scan.TierState)
+	tierStatesMu sync.Mutex
+)
+
+// SetSandbox routes all deletes through sb (nil disables the sandbox)
+func SetSandbox(sb *sandbox.Sandbox) {
+	activeSandboxMu.Lock()
+	defer activeSandboxMu.Unlock()
+	activeSandbox = sb
+}
+
+func currentSandbox() *sandbox.Sandbox {
+	activeSandboxMu.RLock()
+	defer activeSandboxMu.RUnlock()
+	return activeSandbox
+}
+
+// loadScanIndex returns the persistent scan index if incremental scanning is enabled
+func loadScanIndex(cfg *config.Config, logger *log.Logger) *index.Index {
+	if !cfg.ScanOptimizations.UseIndex {
+		return nil
+	}
+
+	scanIndexMu.Lock()
+	defer scanIndexMu.Unlock()
+
+	if scanIndex != nil && scanIndex.Path() == cfg.ScanOptimizations.IndexPath {
+		return scanIndex
+	}
+
+	idx, err := index.Open(cfg.ScanOptimizations.IndexPath)
+	if err != nil {
+		logger.Printf("scan index unavailable, falling back to full scan: %v", err)
+		return nil
+	}
+	logger.Printf("loaded scan index %s (%d directories)", idx.Path(), idx.Len())
+	scanIndex = idx
+	return scanIndex
+}
+
 func RunOnce(ctx context.Context, cfg *config.Config, dryRun bool, logger *log.Logger) error {
 	return RunOnceWithDB(ctx, cfg, dryRun, logger, nil)
 }
 
 func RunOnceWithDB(ctx context.Context, cfg *config.Config, dryRun bool, logger *log.Logger, db *database.DeletionDB) error {
+	return runOnce(ctx, cfg, dryRun, logger, db, nil)
+}
+
+// runOnce runs one cycle over the active rules, or over the rules selected by
+// req when the cycle was triggered manually
+func runOnce(ctx context.Context, cfg *config.Config, dryRun bool, logger *log.Logger, db *database.DeletionDB, req *TriggerRequest) (err error) {
 	if logger == nil {
 		logger = log.Default()
 	}
@@ -34,43 +96,104 @@ func RunOnceWithDB(ctx context.Context, cfg *config.Config, dryRun bool, logger
 	default:
 	}
 
-	// Initialize CPU limiter if configured
-	var cpuLimiter *limiter.CPULimiter
-	if cfg.ResourceLimits.MaxCPUPercent > 0 {
-		cpuLimiter = limiter.NewCPULimiter(cfg.ResourceLimits.MaxCPUPercent)
+	// Initialize CPU and I/O budgets shared by the scan and cleanup phases
+	budget := limiter.New(limiter.Options{
+		MaxCPUPercent:      cfg.ResourceLimits.MaxCPUPercent,
+		DeletionsPerSecond: cfg.ResourceLimits.MaxDeletionsPerSecond,
+		BytesPerSecond:     cfg.ResourceLimits.MaxBytesPerSecond,
+	})
+	if cfg.ResourceLimits.IOPriorityIdle {
+		// Re-applied every cycle so threads started since the last cycle are covered
+		if err := limiter.SetIdleIOPriority(); err != nil {
+			logger.Printf("failed to set idle I/O priority: %v", err)
+		}
 	}
 
 	start := time.Now()
 
+	// Update free space metrics for all monitored paths, including paused ones
+	updateFreeSpaceMetrics(ctx, cfg, logger)
+
+	// Leave out paused rules and, for a scoped trigger, rules that were not selected
+	allRoots := configRoots(cfg)
+	cfg, excluded := scopeConfig(cfg, req)
+	if req != nil && req.DryRun && !dryRun {
+		logger.Println("triggered cycle is a dry run: no files will be deleted")
+		dryRun = true
+	}
+	if len(excluded) > 0 {
+		logger.Printf("skipping %d paused or unselected rule(s): %v", len(excluded), excluded)
+	}
+	if len(cfg.ScanPaths) == 0 && len(cfg.Paths) == 0 {
+		logger.Println("no active rules, skipping cycle")
+		return nil
+	}
+
 	// Record cleanup run timestamp
 	metrics.RecordCleanupRun()
 
-	// Update free space metrics for all monitored paths
-	updateFreeSpaceMetrics(cfg, logger)
+	// Every cycle that scans leaves a run record, including failed ones
+	run := database.CycleRun{StartedAt: start, DryRun: dryRun}
+	var scanErrors []scan.ScanError
+	defer func() {
+		run.FinishedAt = time.Now()
+		if err != nil {
+			run.Error = err.Error()
+		}
+		recordRun(db, run, scanErrors, logger)
+	}()
+
+	idx := loadScanIndex(cfg, logger)
+	scanner := scan.NewScanner(logger)
+	if idx != nil {
+		scanner.SetIndex(idx, time.Duration(cfg.ScanOptimizations.FullRescanHours)*time.Hour)
+	}
+	scanner.SetLimiter(budget)
+	tierStatesMu.Lock()
+	scanner.SetTierStates(tierStates)
+	candidates, err := scanner.Scan(cfg, start)
+	tierStatesMu.Unlock()
+	if err != nil {
+		metrics.ErrorsTotal.Inc()
+		return err
+	}
 
-	// Determine cleanup mode based on disk usage (Section 4)
-	cleanupMode := determineCleanupMode(cfg, logger)
+	// The scan decides each rule's mode, holding tiers through hysteresis and cooldown
+	cleanupMode := scanner.Mode()
 	metrics.SetCleanupMode(cleanupMode)
 	logger.Printf("cleanup mode: %s", cleanupMode)
-
-	// Throttle CPU during scan
-	if cpuLimiter != nil {
-		cpuLimiter.Throttle()
+	scanErrors = publishScanErrors(scanner.ScanErrors(), &run)
+	if run.ScanErrors > 0 {
+		logger.Printf("scan could not read %d entries (about %d bytes below unreadable directories)", run.ScanErrors, run.SkippedBytes)
+	}
+	candidates = dropExcluded(candidates, allRoots, excluded)
+	if idx != nil {
+		if err := idx.Save(); err != nil {
+			logger.Printf("failed to save scan index: %v", err)
+		}
 	}
 
-	candidates, err := scan.Scan(cfg, start)
+	// Reprieved paths are never deleted. With a grace period, candidates are
+	// only removed after staying pending, unchanged, for the whole period.
+	candidates, err = applyReprieves(db, candidates, start, logger)
 	if err != nil {
 		metrics.ErrorsTotal.Inc()
 		return err
 	}
-
-	// Throttle CPU during cleanup
-	if cpuLimiter != nil {
-		cpuLimiter.Throttle()
+	grace := cfg.CleanupOptions.GracePeriod()
+	if grace > 0 {
+		candidates, err = holdForGracePeriod(db, configRoots(cfg), candidates, start, grace, dryRun, logger)
+		if err != nil {
+			metrics.ErrorsTotal.Inc()
+			return err
+		}
 	}
 
 	// Create cleaner with database
 	cleaner := cleanup.NewCleaner(logger, nil, dryRun, db)
+	cleaner.SetContext(ctx)
+	cleaner.SetLimiter(budget)
+	cleaner.SetPauseCheck(pauseCheck(allRoots))
 
 	// SAFETY CONTRACT: Create and set validator with allowed roots from config
 	allowedRoots := make([]string, 0, len(cfg.ScanPaths)+len(cfg.Paths))
@@ -78,10 +201,84 @@ func RunOnceWithDB(ctx context.Context, cfg *config.Config, dryRun bool, logger
 	for _, rule := range cfg.Paths {
 		allowedRoots = append(allowedRoots, rule.Path)
 	}
-	validator := safety.NewValidator(allowedRoots, nil)
+	validator := safety.NewValidator(allowedRoots, cfg.Protection.Paths)
+	validator.SetPolicy(&safety.Policy{
+		Globs:         cfg.Protection.Globs,
+		MarkerFile:    cfg.Protection.MarkerFile,
+		Xattr:         cfg.Protection.Xattr,
+		SkipImmutable: cfg.Protection.SkipImmutable,
+		AllowUIDs:     cfg.Protection.AllowUIDs,
+		DenyUIDs:      cfg.Protection.DenyUIDs,
+	})
+	validator.SetRetention(retentionFloors(cfg))
+	// The generation is read first, so a hold placed while the holds are read is picked up later
+	var holdsGen int64
+	if db != nil {
+		if holdsGen, err = db.HoldsGeneration(); err != nil {
+			metrics.ErrorsTotal.Inc()
+			return err
+		}
+	}
+	holds, err := legalHolds(db, start)
+	if err != nil {
+		metrics.ErrorsTotal.Inc()
+		return err
+	}
+	validator.SetHolds(holds)
 	cleaner.SetValidator(validator)
+	if db != nil {
+		cleaner.SetHoldRefresh(holdRefresh(db, validator, holdsGen))
+	}
+
+	// Delete relative to directory fds opened from the rule roots, so a parent
+	// swapped for a symlink after validation cannot redirect the delete. Only a
+	// platform without fd-relative deletion falls back to path-based deletes;
+	// a root that cannot be opened refuses the deletes below it instead. Either
+	// way the deletes run on the sandboxed thread, so the fallback fails closed too.
+	if !dryRun {
+		var deleter fsops.Deleter = fsops.OSDeleter{}
+		secureDeleter, err := fsops.NewSecureDeleter(allowedRoots)
+		switch {
+		case errors.Is(err, errors.ErrUnsupported):
+			logger.Printf("WARNING: fd-relative deletion unavailable, falling back to path-based deletes: %v", err)
+		case err != nil:
+			metrics.ErrorsTotal.Inc()
+			return err
+		default:
+			defer func() { _ = secureDeleter.Close() }()
+			for _, rootErr := range secureDeleter.Unavailable() {
+				logger.Printf("WARNING: skipping deletes below a rule root that cannot be opened: %v", rootErr)
+			}
+			deleter = secureDeleter
+		}
+		cleaner.SetDeleter(currentSandbox().Wrap(deleter))
+	}
+
+	// Blast-radius limits are measured against what the scan saw; an armed
+	// override only applies to, and is spent by, the next cycle that really deletes
+	cleaner.SetFileCounts(scanner.FileCounts())
+	override := takeOverride(dryRun)
+	cleaner.SetBreakerOverride(override)
+
+	// Say so when unreadable data or a retention floor keeps a rule from reaching its disk target
+	shortfalls := scanShortfalls(scanner.Targets(), scanner.ScanErrors(), candidates)
+	shortfalls = append(shortfalls, floorShortfalls(scanner.Targets(), scanner.RetentionHolds(), candidates)...)
+	for _, msg := range shortfalls {
+		logger.Printf("WARNING: %s", msg)
+	}
+	run.Message = strings.Join(shortfalls, "; ")
+	run.Candidates = len(candidates)
 
 	count, freed, err := cleaner.CleanupWithConfig(cfg, candidates)
+	run.Deleted, run.BytesFreed = count, freed
+	if cleaner.OverrideUsed() {
+		logger.Println("blast-radius override spent: this cycle ran past its limits")
+	} else if override {
+		logger.Println("blast-radius override spent: this cycle stayed within its limits")
+	}
+	if grace > 0 && !dryRun {
+		clearRemoved(db, candidates, logger)
+	}
 	if err != nil {
 		metrics.ErrorsTotal.Inc()
 		return err
@@ -99,6 +296,22 @@ func Run(ctx context.Context, cfg *config.Config, dryRun bool, logger *log.Logge
 }
 
 func RunWithDB(ctx context.Context, cfg *config.Config, dryRun bool, logger *log.Logger, db *database.DeletionDB) error {
+	return RunWithSignals(ctx, cfg, dryRun, logger, db, Signals{})
+}
+
+// Signals lets the daemon drive the scheduler between intervals
+type Signals struct {
+	Trigger  <-chan os.Signal               // Run a cycle immediately
+	Requests <-chan TriggerRequest          // Run a cycle immediately, limited to some rules or as a dry run
+	Reload   <-chan os.Signal               // Re-read the configuration
+	Load     func() (*config.Config, error) // Loads the configuration on reload
+	OnReload func(cfg *config.Config)       // Called after a successful reload, before the next cycle
+}
+
+// RunWithSignals runs cycles on the configured interval, on trigger, and
+// picks up a new configuration on reload. A reload that fails to load keeps
+// the current configuration.
+func RunWithSignals(ctx context.Context, cfg *config.Config, dryRun bool, logger *log.Logger, db *database.DeletionDB, sig Signals) error {
 	if logger == nil {
 		logger = log.Default()
 	}
@@ -106,12 +319,27 @@ func RunWithDB(ctx context.Context, cfg *config.Config, dryRun bool, logger *log
 		return errors.New("nil config")
 	}
 
-	if err := RunOnceWithDB(ctx, cfg, dryRun, logger, db); err != nil {
+	setRules(cfg)
+	runCycle := func(req *TriggerRequest) error {
+		start := time.Now()
+		beginCycle()
+		err := runOnce(ctx, cfg, dryRun, logger, db, req)
+		endCycle(start, err)
 		return err
 	}
 
+	if err := runCycle(nil); err != nil {
+		// A tripped breaker must not take the daemon down: the operator needs
+		// the control API to review and override it
+		if !errors.Is(err, cleanup.ErrCircuitBreaker) {
+			return err
+		}
+		logger.Printf("error running cycle: %v", err)
+	}
+
 	ticker := time.NewTicker(cfg.Interval())
 	defer ticker.Stop()
+	setNextRun(cfg.Interval(), time.Now().Add(cfg.Interval()))
 
 	for {
 		select {
@@ -119,20 +347,51 @@ func RunWithDB(ctx context.Context, cfg *config.Config, dryRun bool, logger *log
 			logger.Println("scheduler shutting down")
 			return ctx.Err()
 		case <-ticker.C:
-			if err := RunOnceWithDB(ctx, cfg, dryRun, logger, db); err != nil {
+			setNextRun(cfg.Interval(), time.Now().Add(cfg.Interval()))
+			if err := runCycle(nil); err != nil {
 				logger.Printf("error running cycle: %v", err)
 			}
+		case <-sig.Trigger:
+			logger.Println("cleanup cycle triggered")
+			if err := runCycle(nil); err != nil {
+				logger.Printf("error running cycle: %v", err)
+			}
+		case req := <-sig.Requests:
+			logger.Printf("cleanup cycle triggered: rules=%v dry_run=%t", req.Rules, req.DryRun)
+			if err := runCycle(&req); err != nil {
+				logger.Printf("error running cycle: %v", err)
+			}
+		case <-sig.Reload:
+			if sig.Load == nil {
+				continue
+			}
+			newCfg, err := sig.Load()
+			if err != nil {
+				logger.Printf("config reload failed, keeping current config: %v", err)
+				continue
+			}
+			cfg = newCfg
+			setRules(cfg)
+			ticker.Reset(cfg.Interval())
+			setNextRun(cfg.Interval(), time.Now().Add(cfg.Interval()))
+			if sig.OnReload != nil {
+				sig.OnReload(cfg)
+			}
+			logger.Printf("config reloaded: interval=%s", cfg.Interval())
 		}
 	}
 }
 
 // updateFreeSpaceMetrics updates free space percentage metrics for all paths
 // Uses optimized parallel scanning and caching based on config
-func updateFreeSpaceMetrics(cfg *config.Config, logger *log.Logger) {
+func updateFreeSpaceMetrics(ctx context.Context, cfg *config.Config, logger *log.Logger) {
 	// Apply scan optimizations from config
 	if cfg.ScanOptimizations.FastScanThreshold > 0 {
 		disk.SetFastScanThreshold(int64(cfg.ScanOptimizations.FastScanThreshold))
 	}
+	if cfg.NFSTimeout > 0 {
+		disk.SetStallTimeout(time.Duration(cfg.NFSTimeout) * time.Second)
+	}
 	if cfg.ScanOptimizations.CacheTTLMinutes > 0 {
 		disk.SetCacheTTL(time.Duration(cfg.ScanOptimizations.CacheTTLMinutes) * time.Minute)
 	}
@@ -146,7 +405,7 @@ func updateFreeSpaceMetrics(cfg *config.Config, logger *log.Logger) {
 
 	// Use parallel scanning if enabled (default: auto-enabled)
 	if cfg.ScanOptimizations.ParallelScans || len(allPaths) > 1 {
-		results, err := disk.ScanPathsParallel(allPaths)
+		results, err := disk.ScanPathsParallelContext(ctx, allPaths)
 		if err != nil {
 			logger.Printf("parallel scan encountered errors: %v", err)
 		}
@@ -154,65 +413,22 @@ func updateFreeSpaceMetrics(cfg *config.Config, logger *log.Logger) {
 		// Update metrics from results
 		for path, stats := range results {
 			metrics.UpdateAllDiskMetrics(path, stats)
+			if stats.WalkErrors > 0 {
+				logger.Printf("usage of %s is partial: %d entries could not be read", path, stats.WalkErrors)
+			}
 		}
 	} else {
 		// Sequential scan (fallback)
 		for _, path := range allPaths {
-			stats, err := disk.ScanPath(path)
+			stats, err := disk.ScanPathContext(ctx, path, true, true)
 			if err != nil {
 				logger.Printf("failed to scan path %s: %v", path, err)
 				continue
 			}
 			metrics.UpdateAllDiskMetrics(path, stats)
+			if stats.WalkErrors > 0 {
+				logger.Printf("usage of %s is partial: %d entries could not be read", path, stats.WalkErrors)
+			}
 		}
 	}
 }
-
-// determineCleanupMode determines the cleanup mode based on disk usage thresholds
-// Section 4: Cleanup mode decision logic
-// - AGE-BASED mode when free_space_percent >= max_free_percent
-// - DISK-USAGE mode when free_space_percent < max_free_percent but >= stack_threshold
-// - STACK mode when free_space_percent < stack_threshold
-func determineCleanupMode(cfg *config.Config, logger *log.Logger) string {
-	// Check all paths and determine the most critical mode
-	mode := "AGE" // Default mode
-
-	// Check scan_paths (use global thresholds if available)
-	for _, path := range cfg.ScanPaths {
-		usedPercent, _, _, err := disk.GetDiskUsage(path)
-		if err != nil {
-			logger.Printf("failed to get disk usage for %s: %v", path, err)
-			continue
-		}
-
-		// Assume default thresholds if not set globally
-		maxFreePercent := 90.0
-		stackThreshold := 98.0
-
-		if usedPercent >= stackThreshold {
-			return "STACK" // Most critical - return immediately
-		} else if usedPercent >= maxFreePercent {
-			mode = "DISK" // Upgrade to DISK mode
-		}
-	}
-
-	// Check path rules
-	for _, rule := range cfg.Paths {
-		usedPercent, _, _, err := disk.GetDiskUsage(rule.Path)
-		if err != nil {
-			logger.Printf("failed to get disk usage for %s: %v", rule.Path, err)
-			continue
-		}
-
-		maxFreePercent := float64(rule.MaxFreePercent)
-		stackThreshold := float64(rule.StackThreshold)
-
-		if usedPercent >= stackThreshold {
-			return "STACK" // Most critical - return immediately
-		} else if usedPercent >= maxFreePercent {
-			mode = "DISK" // Upgrade to DISK mode
-		}
-	}
-
-	return mode
-}
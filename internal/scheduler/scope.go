@@ -0,0 +1,117 @@
+package scheduler
+
+import (
+	"path/filepath"
+	"strings"
+
+	"storage-sage/internal/config"
+	"storage-sage/internal/database"
+	"storage-sage/internal/scan"
+)
+
+// scopeConfig returns a copy of cfg limited to the roots a cycle may clean,
+// and the roots it left out. Roots are left out when they are not selected
+// by req or are paused; a dry-run request also covers paused roots.
+func scopeConfig(cfg *config.Config, req *TriggerRequest) (*config.Config, []string) {
+	stateMu.RLock()
+	_, global := pauses[database.PauseScopeGlobal]
+	paused := make(map[string]bool, len(pauses))
+	for scope := range pauses {
+		paused[scope] = true
+	}
+	stateMu.RUnlock()
+
+	var selected map[string]bool
+	dryRun := false
+	if req != nil {
+		dryRun = req.DryRun
+		if len(req.Rules) > 0 {
+			selected = make(map[string]bool, len(req.Rules))
+			for _, r := range req.Rules {
+				selected[r] = true
+			}
+		}
+	}
+	include := func(root string) bool {
+		if selected != nil && !selected[root] {
+			return false
+		}
+		return dryRun || (!global && !paused[root])
+	}
+
+	scoped := *cfg
+	scoped.ScanPaths = nil
+	scoped.Paths = nil
+	var excluded []string
+	for _, p := range cfg.ScanPaths {
+		if include(p) {
+			scoped.ScanPaths = append(scoped.ScanPaths, p)
+		} else {
+			excluded = append(excluded, p)
+		}
+	}
+	for _, rule := range cfg.Paths {
+		if include(rule.Path) {
+			scoped.Paths = append(scoped.Paths, rule)
+		} else {
+			excluded = append(excluded, rule.Path)
+		}
+	}
+	return &scoped, excluded
+}
+
+// dropExcluded removes candidates that belong to an excluded root. A candidate
+// belongs to the deepest root containing it, so a paused rule nested inside an
+// active one is still protected; directories that contain an excluded root are
+// dropped as well since removing them would remove the paused subtree.
+func dropExcluded(candidates []scan.Candidate, roots, excluded []string) []scan.Candidate {
+	if len(excluded) == 0 {
+		return candidates
+	}
+	isExcluded := make(map[string]bool, len(excluded))
+	for _, r := range excluded {
+		isExcluded[r] = true
+	}
+
+	kept := candidates[:0]
+	for _, c := range candidates {
+		if owner := deepestRoot(c.Path, roots); owner != "" && isExcluded[owner] {
+			continue
+		}
+		if c.IsDir && containsAny(c.Path, excluded) {
+			continue
+		}
+		kept = append(kept, c)
+	}
+	return kept
+}
+
+// deepestRoot returns the longest root that is path or one of its ancestors
+func deepestRoot(path string, roots []string) string {
+	best := ""
+	for _, r := range roots {
+		if isWithin(path, r) && len(r) > len(best) {
+			best = r
+		}
+	}
+	return best
+}
+
+func containsAny(dir string, paths []string) bool {
+	for _, p := range paths {
+		if isWithin(p, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+func isWithin(path, root string) bool {
+	if path == root {
+		return true
+	}
+	if root == string(filepath.Separator) {
+		return strings.HasPrefix(path, root)
+	}
+	return strings.HasPrefix(path, root+string(filepath.Separator))
+}
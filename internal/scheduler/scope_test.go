@@ -0,0 +1,147 @@
+package scheduler
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"storage-sage/internal/config"
+	"storage-sage/internal/scan"
+)
+
+func TestScopeConfig(t *testing.T) {
+	cfg := &config.Config{
+		ScanPaths: []string{"/scratch"},
+		Paths:     []config.PathRule{{Path: "/data"}, {Path: "/data/volume1"}, {Path: "/logs"}},
+	}
+	if err := RestorePauses(cfg, nil); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = Resume(nil, "")
+		_ = Resume(nil, "/data/volume1")
+	})
+
+	if err := Pause(nil, "/nope", "", ""); !errors.Is(err, ErrUnknownRule) {
+		t.Fatalf("Pause unknown rule = %v, want ErrUnknownRule", err)
+	}
+	if err := Pause(nil, "/data/volume1", "incident", "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	roots := func(c *config.Config) []string {
+		var r []string
+		r = append(r, c.ScanPaths...)
+		for _, p := range c.Paths {
+			r = append(r, p.Path)
+		}
+		return r
+	}
+
+	tests := []struct {
+		name         string
+		globalPause  bool
+		req          *TriggerRequest
+		wantIncluded []string
+		wantExcluded []string
+	}{
+		{"scheduled skips paused rule", false, nil, []string{"/scratch", "/data", "/logs"}, []string{"/data/volume1"}},
+		{"scoped trigger", false, &TriggerRequest{Rules: []string{"/logs"}}, []string{"/logs"}, []string{"/scratch", "/data", "/data/volume1"}},
+		{"dry run covers paused rule", false, &TriggerRequest{Rules: []string{"/data/volume1"}, DryRun: true}, []string{"/data/volume1"}, []string{"/scratch", "/data", "/logs"}},
+		{"global pause", true, nil, nil, []string{"/scratch", "/data", "/data/volume1", "/logs"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.globalPause {
+				if err := Pause(nil, "", "freeze", "test"); err != nil {
+					t.Fatal(err)
+				}
+				defer func() { _ = Resume(nil, "") }()
+			}
+			scoped, excluded := scopeConfig(cfg, tt.req)
+			if got := roots(scoped); !reflect.DeepEqual(got, tt.wantIncluded) {
+				t.Errorf("included = %v, want %v", got, tt.wantIncluded)
+			}
+			if !reflect.DeepEqual(excluded, tt.wantExcluded) {
+				t.Errorf("excluded = %v, want %v", excluded, tt.wantExcluded)
+			}
+		})
+	}
+
+	if err := ValidateTrigger(TriggerRequest{Rules: []string{"/data/volume1"}}); !errors.Is(err, ErrPaused) {
+		t.Errorf("ValidateTrigger on paused rule = %v, want ErrPaused", err)
+	}
+	if err := ValidateTrigger(TriggerRequest{Rules: []string{"/data/volume1"}, DryRun: true}); err != nil {
+		t.Errorf("ValidateTrigger dry run on paused rule = %v, want nil", err)
+	}
+
+	st := CurrentState()
+	if len(st.Rules) != 4 || !st.Rules[1].Paused || st.Rules[1].Path != "/data/volume1" || st.Rules[1].Reason != "incident" {
+		t.Errorf("CurrentState rules = %+v", st.Rules)
+	}
+}
+
+func TestPauseCheck(t *testing.T) {
+	cfg := &config.Config{Paths: []config.PathRule{{Path: "/data"}, {Path: "/data/volume1"}, {Path: "/logs"}}}
+	if err := RestorePauses(cfg, nil); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = Resume(nil, "")
+		_ = Resume(nil, "/data/volume1")
+	})
+	check := pauseCheck(configRoots(cfg))
+
+	if _, paused := check("/data/volume1/a.log"); paused {
+		t.Fatal("paused before any pause was placed")
+	}
+	if err := Pause(nil, "/data/volume1", "incident", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path       string
+		wantPaused bool
+	}{
+		{"/data/volume1/a.log", true},
+		{"/data/volume1", true},
+		{"/data", true}, // removing it would remove the paused rule
+		{"/data/a.log", false},
+		{"/data/volume10/a.log", false},
+		{"/logs/a.log", false},
+	}
+	for _, tt := range tests {
+		if detail, paused := check(tt.path); paused != tt.wantPaused {
+			t.Errorf("check(%s) = %q, %v; want paused %v", tt.path, detail, paused, tt.wantPaused)
+		}
+	}
+	if detail, _ := check("/data/volume1/a.log"); detail != "rule /data/volume1 paused by alice: incident" {
+		t.Errorf("detail = %q", detail)
+	}
+
+	if err := Pause(nil, "", "freeze", "bob"); err != nil {
+		t.Fatal(err)
+	}
+	if _, paused := check("/logs/a.log"); !paused {
+		t.Error("global pause did not apply")
+	}
+}
+
+func TestDropExcluded(t *testing.T) {
+	roots := []string{"/data", "/data/volume1", "/logs"}
+	excluded := []string{"/data/volume1"}
+	candidates := []scan.Candidate{
+		{Path: "/data/a.log"},
+		{Path: "/data/volume1/b.log"},  // deepest root is paused
+		{Path: "/data/volume10/c.log"}, // sibling with a shared prefix
+		{Path: "/data", IsDir: true},   // removing it would remove the paused subtree
+		{Path: "/logs/d.log"},
+	}
+	var got []string
+	for _, c := range dropExcluded(candidates, roots, excluded) {
+		got = append(got, c.Path)
+	}
+	want := []string{"/data/a.log", "/data/volume10/c.log", "/logs/d.log"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dropExcluded = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,308 @@
+package scheduler
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"storage-sage/internal/cleanup"
+	"storage-sage/internal/config"
+	"storage-sage/internal/database"
+	"storage-sage/internal/metrics"
+)
+
+var (
+	// ErrPaused is returned when a trigger would delete in a paused scope
+	ErrPaused = errors.New("cleanup is paused")
+	// ErrUnknownRule is returned for a rule path that is not in the current config
+	ErrUnknownRule = errors.New("unknown rule")
+)
+
+// RuleState is the pause state of one configured root
+type RuleState struct {
+	Path     string    `json:"path"`
+	Paused   bool      `json:"paused"`
+	Reason   string    `json:"reason,omitempty"`
+	PausedBy string    `json:"paused_by,omitempty"`
+	PausedAt time.Time `json:"paused_at,omitempty"`
+}
+
+// State describes the scheduler loop as seen by the control API
+type State struct {
+	Paused      bool          `json:"paused"`
+	PauseReason string        `json:"pause_reason,omitempty"`
+	Running     bool          `json:"running"`
+	Interval    time.Duration `json:"interval"`
+	LastRun     time.Time     `json:"last_run"`
+	LastError   string        `json:"last_error,omitempty"`
+	NextRun     time.Time     `json:"next_run"`
+	Rules       []RuleState   `json:"rules"`
+
+	OverrideArmed bool   `json:"override_armed"`        // The next cycle may exceed its blast-radius limits
+	OverrideBy    string `json:"override_by,omitempty"` // Who armed the override
+}
+
+// TriggerRequest asks for an immediate cycle, optionally limited to some rules
+type TriggerRequest struct {
+	Rules  []string // Rule paths to run; empty runs every active rule
+	DryRun bool     // Log what would be deleted without deleting, for this cycle only
+}
+
+var (
+	state   State
+	pauses  = make(map[string]database.PauseEntry) // keyed by database.PauseScopeGlobal or rule path
+	rules   []string                               // roots of the current config
+	stateMu sync.RWMutex
+)
+
+// RestorePauses records the rules of cfg and loads persisted pauses so they survive restarts
+func RestorePauses(cfg *config.Config, db *database.DeletionDB) error {
+	setRules(cfg)
+	if db == nil {
+		return nil
+	}
+	entries, err := db.GetPaused()
+	if err != nil {
+		return err
+	}
+
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	pauses = make(map[string]database.PauseEntry, len(entries))
+	for _, e := range entries {
+		pauses[e.Scope] = e
+	}
+	publishPauseStateLocked()
+	return nil
+}
+
+// Pause stops cleanup for one rule, or for every rule when rule is empty.
+// A cycle already in progress skips the paused entries it has not reached yet.
+func Pause(db *database.DeletionDB, rule, reason, pausedBy string) error {
+	scope := database.PauseScopeGlobal
+	if rule != "" {
+		scope = rule
+	}
+
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	if scope != database.PauseScopeGlobal && !knownRuleLocked(rule) {
+		return ErrUnknownRule
+	}
+	if db != nil {
+		if err := db.SetPaused(scope, reason, pausedBy); err != nil {
+			return err
+		}
+	}
+	entry := database.PauseEntry{Scope: scope, Reason: reason, PausedBy: pausedBy, PausedAt: time.Now()}
+	if prev, ok := pauses[scope]; ok {
+		entry.PausedAt = prev.PausedAt
+	}
+	pauses[scope] = entry
+	publishPauseStateLocked()
+	return nil
+}
+
+// Resume lifts the pause for one rule, or the global pause when rule is empty.
+// Rule pauses are independent of the global pause and stay in place.
+func Resume(db *database.DeletionDB, rule string) error {
+	scope := database.PauseScopeGlobal
+	if rule != "" {
+		scope = rule
+	}
+
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	// Resuming a rule that was removed from the config is allowed so stale pauses can be cleared
+	if db != nil {
+		if err := db.ClearPaused(scope); err != nil {
+			return err
+		}
+	}
+	delete(pauses, scope)
+	publishPauseStateLocked()
+	return nil
+}
+
+// Paused reports whether the whole daemon is paused
+func Paused() bool {
+	stateMu.RLock()
+	defer stateMu.RUnlock()
+	_, ok := pauses[database.PauseScopeGlobal]
+	return ok
+}
+
+// pauseCheck returns the live pause state for a cycle over roots: changes
+// at a path are paused by the global pause, by a pause of the deepest root
+// containing it, or, for a directory, by a pause of a root below it
+func pauseCheck(roots []string) cleanup.PauseCheck {
+	return func(path string) (string, bool) {
+		stateMu.RLock()
+		defer stateMu.RUnlock()
+		if g, ok := pauses[database.PauseScopeGlobal]; ok {
+			return pauseDetail("cleanup", g), true
+		}
+		owner := deepestRoot(path, roots)
+		for scope, p := range pauses {
+			if scope == owner || isWithin(scope, path) {
+				return pauseDetail("rule "+scope, p), true
+			}
+		}
+		return "", false
+	}
+}
+
+// pauseDetail describes a pause for SKIP records
+func pauseDetail(what string, p database.PauseEntry) string {
+	detail := what + " paused"
+	if p.PausedBy != "" {
+		detail += " by " + p.PausedBy
+	}
+	if p.Reason != "" {
+		detail += ": " + p.Reason
+	}
+	return detail
+}
+
+// ValidateTrigger checks a trigger request against the current config and pause state.
+// Dry-run triggers are allowed on paused scopes since they delete nothing.
+func ValidateTrigger(req TriggerRequest) error {
+	stateMu.RLock()
+	defer stateMu.RUnlock()
+	for _, rule := range req.Rules {
+		if !knownRuleLocked(rule) {
+			return ErrUnknownRule
+		}
+	}
+	if req.DryRun {
+		return nil
+	}
+	if _, ok := pauses[database.PauseScopeGlobal]; ok {
+		return ErrPaused
+	}
+	for _, rule := range req.Rules {
+		if _, ok := pauses[rule]; ok {
+			return ErrPaused
+		}
+	}
+	return nil
+}
+
+// ArmOverride lets the next real (non dry-run) cycle exceed its blast-radius
+// limits. That cycle spends the override whether or not it needed it, so a
+// later misconfiguration still trips the breaker; it does not survive a restart.
+func ArmOverride(reason, by string) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	state.OverrideArmed = true
+	state.OverrideBy = by
+	if reason != "" {
+		state.OverrideBy = by + ": " + reason
+	}
+}
+
+// takeOverride spends an armed override on a real cycle and reports whether
+// one was armed; dry runs leave it armed
+func takeOverride(dryRun bool) bool {
+	if dryRun {
+		return false
+	}
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	armed := state.OverrideArmed
+	state.OverrideArmed = false
+	state.OverrideBy = ""
+	return armed
+}
+
+// CurrentState returns a snapshot of the scheduler loop
+func CurrentState() State {
+	stateMu.RLock()
+	defer stateMu.RUnlock()
+
+	st := state
+	if g, ok := pauses[database.PauseScopeGlobal]; ok {
+		st.Paused = true
+		st.PauseReason = g.Reason
+	}
+	st.Rules = make([]RuleState, 0, len(rules))
+	for _, path := range rules {
+		rs := RuleState{Path: path}
+		if p, ok := pauses[path]; ok {
+			rs.Paused, rs.Reason, rs.PausedBy, rs.PausedAt = true, p.Reason, p.PausedBy, p.PausedAt
+		}
+		st.Rules = append(st.Rules, rs)
+	}
+	return st
+}
+
+// setRules records the roots of the active config for validation, status and metrics
+func setRules(cfg *config.Config) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	rules = configRoots(cfg)
+	publishPauseStateLocked()
+}
+
+// configRoots lists every root a config cleans: scan_paths and path rules, deduplicated and sorted
+func configRoots(cfg *config.Config) []string {
+	seen := make(map[string]bool, len(cfg.ScanPaths)+len(cfg.Paths))
+	roots := make([]string, 0, len(cfg.ScanPaths)+len(cfg.Paths))
+	add := func(p string) {
+		if !seen[p] {
+			seen[p] = true
+			roots = append(roots, p)
+		}
+	}
+	for _, p := range cfg.ScanPaths {
+		add(p)
+	}
+	for _, r := range cfg.Paths {
+		add(r.Path)
+	}
+	sort.Strings(roots)
+	return roots
+}
+
+func knownRuleLocked(path string) bool {
+	for _, r := range rules {
+		if r == path {
+			return true
+		}
+	}
+	return false
+}
+
+func publishPauseStateLocked() {
+	byRule := make(map[string]bool, len(rules))
+	for _, r := range rules {
+		_, byRule[r] = pauses[r]
+	}
+	_, global := pauses[database.PauseScopeGlobal]
+	metrics.SetPauseState(global, byRule)
+}
+
+func setNextRun(interval time.Duration, next time.Time) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	state.Interval = interval
+	state.NextRun = next
+}
+
+func beginCycle() {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	state.Running = true
+}
+
+func endCycle(start time.Time, err error) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	state.Running = false
+	state.LastRun = start
+	state.LastError = ""
+	if err != nil {
+		state.LastError = err.Error()
+	}
+}
@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"storage-sage/internal/config"
+	"storage-sage/internal/metrics"
+)
+
+// TestOverrideSpentByNextRealCycle verifies an override is spent by the next
+// real cycle even when that cycle stays within its limits, and that dry runs
+// leave it armed
+func TestOverrideSpentByNextRealCycle(t *testing.T) {
+	ArmOverride("planned purge", "alice")
+	defer takeOverride(false)
+
+	if takeOverride(true) {
+		t.Fatal("a dry run took the override")
+	}
+	if st := CurrentState(); !st.OverrideArmed {
+		t.Fatal("override disarmed by a dry run")
+	}
+
+	// The next real cycle takes it whether or not its limits are exceeded
+	if !takeOverride(false) {
+		t.Fatal("the next real cycle did not get the override")
+	}
+	if st := CurrentState(); st.OverrideArmed || st.OverrideBy != "" {
+		t.Errorf("override still armed after a real cycle: %+v", st)
+	}
+	if takeOverride(false) {
+		t.Error("a later cycle got the override again")
+	}
+}
+
+// TestOverrideSpentWithinLimits runs a real cycle that deletes nothing and
+// checks it spends the override it did not need
+func TestOverrideSpentWithinLimits(t *testing.T) {
+	metrics.Init()
+	root := t.TempDir()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("scan_paths: ["+root+"]\nage_off_days: 30\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	ArmOverride("planned purge", "alice")
+	defer takeOverride(false)
+	if err := RunOnce(context.Background(), cfg, false, log.New(io.Discard, "", 0)); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if st := CurrentState(); st.OverrideArmed {
+		t.Errorf("override still armed after a cycle within its limits (by %q)", st.OverrideBy)
+	}
+}
@@ -6,6 +6,7 @@ set -e
 
 TEST_DIR="/tmp/storage-sage-test-workspace/var/log"
 DAEMON_URL="http://localhost:9090"
+CTL="${CTL:-storage-sage ctl}"  # Control client; triggers go to the control socket, not the metrics port
 
 # Colors for output
 GREEN='\033[0;32m'
@@ -52,7 +53,7 @@ echo ""
 
 # Trigger cleanup and show results
 echo -e "${GREEN}3. Triggering cleanup...${NC}"
-curl -s -X POST "$DAEMON_URL/trigger" > /dev/null
+$CTL trigger > /dev/null
 echo "Cleanup triggered, waiting 3 seconds..."
 sleep 3
 
@@ -74,7 +75,7 @@ echo ""
 
 # Trigger cleanup and show results
 echo -e "${GREEN}6. Triggering cleanup...${NC}"
-curl -s -X POST "$DAEMON_URL/trigger" > /dev/null
+$CTL trigger > /dev/null
 echo "Cleanup triggered, waiting 3 seconds..."
 sleep 3
 
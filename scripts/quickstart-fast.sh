@@ -34,7 +34,11 @@ if [ ! -f secrets/jwt_secret.txt ]; then
     openssl rand -base64 32 > secrets/jwt_secret.txt
     chmod 600 secrets/jwt_secret.txt
 fi
-log_success "JWT secret ready"
+if [ ! -f secrets/control_token.txt ]; then
+    openssl rand -base64 32 > secrets/control_token.txt
+    chmod 600 secrets/control_token.txt
+fi
+log_success "JWT secret and control token ready"
 
 # Step 2: Create .env if missing (fast)
 log_info "Checking .env..."
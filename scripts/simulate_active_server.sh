@@ -10,6 +10,7 @@ set -e
 
 TEST_DIR="/tmp/storage-sage-test-workspace/var/log"
 DAEMON_URL="http://localhost:9090"
+CTL="${CTL:-storage-sage ctl}"  # Control client; triggers go to the control socket, not the metrics port
 BACKEND_URL="https://localhost:8443"
 
 # Colors
@@ -80,9 +81,8 @@ get_disk_usage() {
 # Function to trigger cleanup
 trigger_cleanup() {
     echo -e "${BLUE}🔄 Triggering cleanup...${NC}"
-    RESPONSE=$(curl -s -X POST "$DAEMON_URL/trigger" 2>&1)
-    if [ $? -ne 0 ]; then
-        echo -e "${YELLOW}  Warning: Trigger endpoint may not be available${NC}"
+    if ! RESPONSE=$($CTL trigger 2>&1); then
+        echo -e "${YELLOW}  Warning: Control socket may not be available${NC}"
         echo "  Response: $RESPONSE"
     fi
     # Increase wait time for cleanup to complete
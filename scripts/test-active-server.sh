@@ -16,6 +16,7 @@ TEST_BACKUP_DIR="$TEST_WORKSPACE/backups"
 TEST_TEMP_DIR="$TEST_WORKSPACE/tmp"
 
 DAEMON_URL="http://localhost:9090"
+CTL="${CTL:-storage-sage ctl}"  # Control client; triggers go to the control socket, not the metrics port
 BACKEND_URL="https://localhost:8443"
 DB_PATH="/var/lib/storage-sage/deletions.db"
 
@@ -121,8 +122,8 @@ trigger_cleanup() {
         # Send SIGUSR1 to trigger cleanup in Docker
         docker compose exec -T "$DAEMON_CONTAINER" pkill -SIGUSR1 storage-sage 2>/dev/null || true
     else
-        # Try HTTP endpoint
-        curl -s -X POST "$DAEMON_URL/trigger" > /dev/null 2>&1 || true
+        # Ask the daemon over its control socket
+        $CTL trigger > /dev/null 2>&1 || true
     fi
     
     echo "  Waiting 5 seconds for cleanup to complete..."
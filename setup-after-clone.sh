@@ -71,6 +71,18 @@ if [ ! -f secrets/jwt_secret.txt ]; then
 else
     echo "✓ secrets/jwt_secret.txt already exists"
 fi
+if [ ! -f secrets/control_token.txt ]; then
+    if command -v openssl >/dev/null 2>&1; then
+        openssl rand -base64 32 > secrets/control_token.txt
+        chmod 600 secrets/control_token.txt
+        echo "✓ Created secrets/control_token.txt"
+    else
+        echo -e "${YELLOW}⚠ OpenSSL not found. Please create secrets/control_token.txt manually${NC}"
+        echo "   Run: openssl rand -base64 32 > secrets/control_token.txt"
+    fi
+else
+    echo "✓ secrets/control_token.txt already exists"
+fi
 echo ""
 
 # Step 4: Run make setup (creates certs and config)
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"storage-sage/internal/control"
+	"storage-sage/web/backend/auth"
+	"storage-sage/web/backend/middleware"
+)
+
+// daemonControlClient connects to the daemon's authenticated control API.
+// DAEMON_CONTROL_ADDR is a socket path or http(s) URL; TCP listeners also need
+// the shared bearer token from DAEMON_CONTROL_TOKEN_FILE.
+func daemonControlClient() (*control.Client, error) {
+	addr := os.Getenv("DAEMON_CONTROL_ADDR")
+	if addr == "" {
+		addr = "http://storage-sage-daemon:9092"
+	}
+
+	tokenFile := os.Getenv("DAEMON_CONTROL_TOKEN_FILE")
+	if tokenFile == "" {
+		tokenFile = "/run/secrets/control_token"
+	}
+	token := ""
+	if _, err := os.Stat(tokenFile); err == nil {
+		if token, err = control.ReadToken(tokenFile); err != nil {
+			return nil, err
+		}
+	}
+
+	return control.NewClient(addr, token)
+}
+
+// PauseCleanupHandler pauses cleanup for the rules in the body, or for the whole daemon
+func PauseCleanupHandler(w http.ResponseWriter, r *http.Request) {
+	setPause(w, r, true)
+}
+
+// ResumeCleanupHandler resumes cleanup for the rules in the body, or lifts the global pause
+func ResumeCleanupHandler(w http.ResponseWriter, r *http.Request) {
+	setPause(w, r, false)
+}
+
+func setPause(w http.ResponseWriter, r *http.Request, pause bool) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok || !auth.HasPermission(claims.Roles, auth.PermissionTriggerCleanup) {
+		respondError(w, "unauthorized", http.StatusForbidden)
+		return
+	}
+
+	// Optional body: {"rules": ["/data/volume1"], "reason": "incident 42"}
+	var req control.PauseRequest
+	if err := decodeOptionalJSON(r, &req); err != nil {
+		respondError(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	req.By = claims.Username
+
+	client, err := daemonControlClient()
+	if err != nil {
+		respondError(w, fmt.Sprintf("failed to reach daemon: %v", err), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	action := "resumed"
+	if pause {
+		action = "paused"
+		err = client.Pause(ctx, req)
+	} else {
+		err = client.Resume(ctx, req)
+	}
+	if err != nil {
+		log.Printf("[PauseCleanupHandler] ERROR: Failed to set pause=%t for %v: %v", pause, req.Rules, err)
+		if errors.Is(err, control.ErrUnknownRule) {
+			respondError(w, "unknown rule", http.StatusNotFound)
+			return
+		}
+		respondError(w, fmt.Sprintf("failed to update pause state: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	log.Printf("[PauseCleanupHandler] %s cleanup %s rules=%v reason=%q", claims.Username, action, req.Rules, req.Reason)
+	respondJSON(w, map[string]interface{}{
+		"message": "cleanup " + action,
+		"rules":   req.Rules,
+	}, http.StatusOK)
+}
+
+// OverrideCleanupHandler lets the daemon's next cycle exceed its blast-radius
+// limits once. Only operators and admins hold the permission.
+func OverrideCleanupHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok || !auth.HasPermission(claims.Roles, auth.PermissionOverrideCleanup) {
+		respondError(w, "unauthorized", http.StatusForbidden)
+		return
+	}
+
+	// Optional body: {"reason": "planned purge of /data/old"}
+	var req control.OverrideRequest
+	if err := decodeOptionalJSON(r, &req); err != nil {
+		respondError(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	req.By = claims.Username
+
+	client, err := daemonControlClient()
+	if err != nil {
+		respondError(w, fmt.Sprintf("failed to reach daemon: %v", err), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Override(ctx, req); err != nil {
+		log.Printf("[OverrideCleanupHandler] ERROR: Failed to arm override: %v", err)
+		respondError(w, fmt.Sprintf("failed to arm override: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	log.Printf("[OverrideCleanupHandler] %s armed blast-radius override reason=%q", claims.Username, req.Reason)
+	respondJSON(w, map[string]interface{}{
+		"message": "blast-radius override armed for the next cycle",
+	}, http.StatusOK)
+}
+
+// decodeOptionalJSON decodes a JSON request body into v; an empty body leaves v unchanged
+func decodeOptionalJSON(r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 	"strings"
 	"time"
 
+	"storage-sage/internal/accounts"
 	"storage-sage/internal/config"
 	"storage-sage/internal/database"
 	"storage-sage/web/backend/auth"
@@ -22,16 +23,20 @@ import (
 // DeletionLogEntry represents a single deletion log entry
 type DeletionLogEntry struct {
 	Timestamp      time.Time `json:"timestamp"`
-	Action         string    `json:"action"` // DELETE, SKIP, ERROR, DRY_RUN
+	Action         string    `json:"action"` // DELETE, COMPRESS, TRUNCATE, SKIP, ERROR, DRY_RUN
 	Path           string    `json:"path"`
 	FileName       string    `json:"file_name"`
 	ObjectType     string    `json:"object_type"` // file, directory, empty_directory
 	Size           int64     `json:"size"`
 	DeletionReason string    `json:"deletion_reason"`
 	HumanReason    string    `json:"human_reason"`
-	PrimaryReason  string    `json:"primary_reason"` // age_threshold, disk_threshold, combined, stacked_cleanup
+	PrimaryReason  string    `json:"primary_reason"` // age_threshold, disk_threshold, combined, stacked_cleanup, tier, gfs, keep_latest, compress, truncate
 	PathRule       string    `json:"path_rule"`
 	ErrorMessage   string    `json:"error_message,omitempty"`
+	Errno          string    `json:"errno,omitempty"`      // Errno of a failed delete, e.g. EACCES
+	Owner          string    `json:"owner,omitempty"`      // Owning user, resolved via /etc/passwd
+	Group          string    `json:"group,omitempty"`      // Owning group, resolved via /etc/group
+	SizeAfter      *int64    `json:"size_after,omitempty"` // Size of the compressed file or kept tail (COMPRESS and TRUNCATE only)
 }
 
 // DeletionsLogResponse is the API response for deletion log
@@ -56,6 +61,15 @@ func convertDBRecord(record database.DeletionRecord) DeletionLogEntry {
 		PrimaryReason:  record.PrimaryReason,
 		PathRule:       record.PathRule,
 		ErrorMessage:   record.ErrorMessage,
+		Errno:          record.Errno,
+		SizeAfter:      record.SizeAfter,
+	}
+
+	if record.UID != nil {
+		entry.Owner = accounts.Default().UserName(uint32(*record.UID))
+	}
+	if record.GID != nil {
+		entry.Group = accounts.Default().GroupName(uint32(*record.GID))
 	}
 
 	// Generate human-readable reason
@@ -74,6 +88,16 @@ func toHumanReason(reason, primaryReason string) string {
 			return "Disk usage exceeded threshold"
 		case "stacked_cleanup":
 			return "Critical disk usage condition"
+		case "tier":
+			return "Disk usage escalation tier reached"
+		case "gfs":
+			return "Displaced by backup retention (GFS)"
+		case "keep_latest":
+			return "Older than the newest versions kept"
+		case "compress":
+			return "Compressed by a compress tier"
+		case "truncate":
+			return "Oversized file truncated in place"
 		case "combined":
 			return "Multiple conditions met"
 		default:
@@ -91,6 +115,21 @@ func toHumanReason(reason, primaryReason string) string {
 		// Try to extract disk usage and age from reason string
 		return fmt.Sprintf("Critical disk usage condition: %s", reason)
 	}
+	if primaryReason == "tier" {
+		return fmt.Sprintf("Disk usage escalation tier reached: %s", reason)
+	}
+	if primaryReason == "gfs" {
+		return fmt.Sprintf("Displaced by backup retention (GFS): %s", reason)
+	}
+	if primaryReason == "keep_latest" {
+		return fmt.Sprintf("Older than the newest versions kept: %s", reason)
+	}
+	if primaryReason == "compress" {
+		return fmt.Sprintf("Compressed by a compress tier: %s", reason)
+	}
+	if primaryReason == "truncate" {
+		return fmt.Sprintf("Oversized file truncated in place: %s", reason)
+	}
 
 	parts := []string{}
 
@@ -375,17 +414,87 @@ func (lp *LogParser) parseLine(line string) (DeletionLogEntry, error) {
 	return entry, nil
 }
 
+// tierReasonPattern matches the tier part of a reason string: level, disk usage and age
+var tierReasonPattern = regexp.MustCompile(`tier_(\d+): disk_usage=([\d.]+)%.*?age=(\w+)`)
+
+// humanAge rewrites an age from a reason string: "20d" reads "20 days", sub-day ages such as "7h12m" stay as they are
+func humanAge(s string) string {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if _, err := strconv.Atoi(days); err == nil {
+			return days + " days"
+		}
+	}
+	return s
+}
+
+// gfsReasonPattern matches a GFS reason whose bucket keeps a newer file: period and bucket
+var gfsReasonPattern = regexp.MustCompile(`gfs:(?: series=\S+)? (\w+) bucket (\S+) kept`)
+
+// keepLatestReasonPattern matches a keep_latest reason: name, rank, total, order and count kept
+var keepLatestReasonPattern = regexp.MustCompile(`keep_latest:(?: name=(\S+))? rank (\d+) of (\d+) by (\w+).*?\(keep (\d+),`)
+
+// compressReasonPattern matches a compress tier reason: level, age and format
+var compressReasonPattern = regexp.MustCompile(`compress_(\d+): disk_usage=[\d.]+%.*?age=(\w+).*?format=(\w+)`)
+
+// truncateReasonPattern matches a truncate reason: size, max size, kept tail and whether the file was open
+var truncateReasonPattern = regexp.MustCompile(`truncate(?:_\d+)?:.*?size=(\d+) \(max=(\d+)\), keep_tail=(\d+)(, open)?`)
+
 // toHumanReason converts technical reason to human-readable format
 func (lp *LogParser) toHumanReason(reason string) string {
+	// Oversized files are truncated instead of deleted
+	// Example: "truncate: size=5368709120 (max=1073741824), keep_tail=1048576, open"
+	if matches := truncateReasonPattern.FindStringSubmatch(reason); matches != nil {
+		human := fmt.Sprintf("Truncated to its last %s bytes (%s bytes, limit %s)", matches[3], matches[1], matches[2])
+		if matches[4] != "" {
+			human += ", held open by a process"
+		}
+		return human
+	}
+
+	// Compress tiers select what nothing deletes yet
+	// Example: "compress_1: disk_usage=40.0% (threshold=0.0%), age=3d (min=1d), format=gzip"
+	if matches := compressReasonPattern.FindStringSubmatch(reason); matches != nil {
+		return fmt.Sprintf("Compressed with %s by tier %s, file %s old", matches[3], matches[1], humanAge(matches[2]))
+	}
+
+	// GFS rules select by backup retention alone
+	// Example: "gfs: daily bucket 2026-10-15 kept /backups/db-20261015.tar (keep daily=7 weekly=4 monthly=12 yearly=0)"
+	if matches := gfsReasonPattern.FindStringSubmatch(reason); matches != nil {
+		return fmt.Sprintf("Newer file kept for %s backup %s", matches[1], matches[2])
+	}
+	if strings.HasPrefix(reason, "gfs:") {
+		return "Older than every backup period kept"
+	}
+
+	// keep_latest rules select by version count alone
+	// Example: "keep_latest: name=service rank 4 of 6 by semver, version 1.2.1 (keep 3, newest kept /ci/service-1.2.4.tar.gz)"
+	if matches := keepLatestReasonPattern.FindStringSubmatch(reason); matches != nil {
+		what := "files"
+		if matches[4] == "semver" {
+			what = "versions"
+		}
+		if matches[1] != "" {
+			what += " of " + matches[1]
+		} else {
+			what += " in the directory"
+		}
+		return fmt.Sprintf("Not among the newest %s %s (ranked %s of %s by %s)", matches[5], what, matches[2], matches[3], matches[4])
+	}
+
+	// Escalation tiers replace the other disk reasons for rules that use them
+	// Example: "tier_2: disk_usage=93.0% (threshold=92.0%), age=10d (min=7d), strategy=oldest"
+	if matches := tierReasonPattern.FindStringSubmatch(reason); matches != nil {
+		return fmt.Sprintf("Disk usage tier %s (%s%%), file %s old", matches[1], matches[2], humanAge(matches[3]))
+	}
+
 	// Check for stacked cleanup first (highest priority)
 	if strings.Contains(reason, "stacked_cleanup:") {
 		// Example: "stacked_cleanup: disk_usage=99.0% (threshold=98.0%), age=20d (min=14d)"
-		re := regexp.MustCompile(`disk_usage=([\d.]+)%.*age=(\d+)d`)
+		re := regexp.MustCompile(`disk_usage=([\d.]+)%.*age=(\w+)`)
 		matches := re.FindStringSubmatch(reason)
 		if len(matches) >= 3 {
 			diskUsage, _ := strconv.ParseFloat(matches[1], 64)
-			ageDays, _ := strconv.ParseFloat(matches[2], 64)
-			return fmt.Sprintf("Critical disk usage (%.1f%%), file %.0f days old", diskUsage, ageDays)
+			return fmt.Sprintf("Critical disk usage (%.1f%%), file %s old", diskUsage, humanAge(matches[2]))
 		}
 		return "Critical disk usage condition"
 	}
@@ -404,11 +513,16 @@ func (lp *LogParser) toHumanReason(reason string) string {
 
 	// Age threshold
 	if strings.Contains(reason, "age_threshold:") {
-		re := regexp.MustCompile(`age_threshold:.*\(max=(\d+)d\)`)
+		// Example: "age_threshold: 40d (max=30d) from filename" when the rule ages by another timestamp
+		// A sub-day limit reads "age_threshold: 7h12m (max=6h)"
+		re := regexp.MustCompile(`age_threshold:.*\(max=(\w+)\)(?: from (\w+))?`)
 		matches := re.FindStringSubmatch(reason)
 		if len(matches) >= 2 {
-			days := matches[1]
-			parts = append(parts, fmt.Sprintf("File older than %s days", days))
+			part := fmt.Sprintf("File older than %s", humanAge(matches[1]))
+			if len(matches) >= 3 && matches[2] != "" {
+				part += " by " + matches[2]
+			}
+			parts = append(parts, part)
 		}
 	}
 
@@ -421,6 +535,21 @@ func (lp *LogParser) toHumanReason(reason string) string {
 
 // extractPrimaryReason determines the primary category
 func (lp *LogParser) extractPrimaryReason(reason string) string {
+	if strings.HasPrefix(reason, "gfs:") {
+		return "gfs"
+	}
+	if strings.HasPrefix(reason, "keep_latest:") {
+		return "keep_latest"
+	}
+	if compressReasonPattern.MatchString(reason) {
+		return "compress"
+	}
+	if truncateReasonPattern.MatchString(reason) {
+		return "truncate"
+	}
+	if tierReasonPattern.MatchString(reason) {
+		return "tier"
+	}
 	if strings.Contains(reason, "stacked_cleanup:") {
 		return "stacked_cleanup"
 	}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"storage-sage/internal/database"
+	"storage-sage/web/backend/auth"
+	"storage-sage/web/backend/middleware"
+)
+
+// PlaceHoldRequest is the body of POST /api/v1/holds
+type PlaceHoldRequest struct {
+	Pattern   string     `json:"pattern"` // Absolute path or glob
+	Owner     string     `json:"owner"`
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at"` // RFC 3339; omit to hold until released
+}
+
+// GetHoldsHandler handles GET /api/v1/holds
+// Query parameters: all=true includes released and expired holds
+func GetHoldsHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok || !auth.HasPermission(claims.Roles, auth.PermissionViewLogs) {
+		respondError(w, "unauthorized", http.StatusForbidden)
+		return
+	}
+
+	db, ok := openDeletionDB(w, "GetHoldsHandler")
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	holds, err := db.GetHolds(time.Now(), r.URL.Query().Get("all") == "true")
+	if err != nil {
+		log.Printf("[GetHoldsHandler] Database query error: %v", err)
+		respondError(w, fmt.Sprintf("failed to query database: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if holds == nil {
+		holds = []database.LegalHold{}
+	}
+	respondJSON(w, map[string]interface{}{"holds": holds}, http.StatusOK)
+}
+
+// PlaceHoldHandler handles POST /api/v1/holds.
+// The daemon checks for new holds before each delete, so a hold applies
+// within a running cycle and no reload is needed.
+func PlaceHoldHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok || !auth.HasPermission(claims.Roles, auth.PermissionManageHolds) {
+		respondError(w, "unauthorized", http.StatusForbidden)
+		return
+	}
+
+	var req PlaceHoldRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		respondError(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	db, ok := openDeletionDB(w, "PlaceHoldHandler")
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	id, err := db.PlaceHold(database.LegalHold{
+		Pattern:   req.Pattern,
+		Owner:     req.Owner,
+		Reason:    req.Reason,
+		CreatedBy: claims.Username,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if errors.Is(err, database.ErrInvalidHold) {
+		respondError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("[PlaceHoldHandler] ERROR: Failed to place hold on %s: %v", req.Pattern, err)
+		respondError(w, fmt.Sprintf("failed to place hold: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[PlaceHoldHandler] %s placed legal hold #%d on %s owner=%q reason=%q", claims.Username, id, req.Pattern, req.Owner, req.Reason)
+	respondJSON(w, map[string]interface{}{"message": "hold placed", "id": id}, http.StatusCreated)
+}
+
+// ReleaseHoldHandler handles DELETE /api/v1/holds?id=N&note=...
+func ReleaseHoldHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok || !auth.HasPermission(claims.Roles, auth.PermissionManageHolds) {
+		respondError(w, "unauthorized", http.StatusForbidden)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil || id <= 0 {
+		respondError(w, "id must be a hold ID", http.StatusBadRequest)
+		return
+	}
+	note := r.URL.Query().Get("note")
+
+	db, ok := openDeletionDB(w, "ReleaseHoldHandler")
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	released, err := db.ReleaseHold(id, claims.Username, note, time.Now())
+	if err != nil {
+		log.Printf("[ReleaseHoldHandler] ERROR: Failed to release hold #%d: %v", id, err)
+		respondError(w, fmt.Sprintf("failed to release hold: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if !released {
+		respondError(w, "no active hold with that id", http.StatusNotFound)
+		return
+	}
+
+	log.Printf("[ReleaseHoldHandler] %s released legal hold #%d note=%q", claims.Username, id, note)
+	respondJSON(w, map[string]interface{}{"message": "hold released", "id": id}, http.StatusOK)
+}
+
+// GetHoldAuditHandler handles GET /api/v1/holds/audit
+// Query parameters: id=N limits the trail to one hold
+func GetHoldAuditHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok || !auth.HasPermission(claims.Roles, auth.PermissionViewLogs) {
+		respondError(w, "unauthorized", http.StatusForbidden)
+		return
+	}
+
+	var id int64
+	if idStr := r.URL.Query().Get("id"); idStr != "" {
+		var err error
+		if id, err = strconv.ParseInt(idStr, 10, 64); err != nil || id <= 0 {
+			respondError(w, "id must be a hold ID", http.StatusBadRequest)
+			return
+		}
+	}
+
+	db, ok := openDeletionDB(w, "GetHoldAuditHandler")
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	events, err := db.GetHoldEvents(id)
+	if err != nil {
+		log.Printf("[GetHoldAuditHandler] Database query error: %v", err)
+		respondError(w, fmt.Sprintf("failed to query database: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if events == nil {
+		events = []database.HoldEvent{}
+	}
+	respondJSON(w, map[string]interface{}{"events": events}, http.StatusOK)
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"storage-sage/internal/accounts"
+	"storage-sage/internal/database"
+	"storage-sage/web/backend/auth"
+	"storage-sage/web/backend/middleware"
+)
+
+// OwnerUsageResponse is the API response for freed space per owner
+type OwnerUsageResponse struct {
+	GroupBy string                `json:"group_by"` // user or group
+	Days    int                   `json:"days"`
+	Start   time.Time             `json:"start"`
+	End     time.Time             `json:"end"`
+	Owners  []database.OwnerUsage `json:"owners"`
+}
+
+// GetBytesFreedByOwnerHandler handles GET /api/v1/deletions/by-owner
+// Query parameters: group_by=user|group (default user), days=N (default 30)
+func GetBytesFreedByOwnerHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok || !auth.HasPermission(claims.Roles, auth.PermissionViewLogs) {
+		respondError(w, "unauthorized", http.StatusForbidden)
+		return
+	}
+
+	groupBy := r.URL.Query().Get("group_by")
+	if groupBy == "" {
+		groupBy = "user"
+	}
+	if groupBy != "user" && groupBy != "group" {
+		respondError(w, "group_by must be 'user' or 'group'", http.StatusBadRequest)
+		return
+	}
+
+	days := 30
+	if dStr := r.URL.Query().Get("days"); dStr != "" {
+		if d, err := strconv.Atoi(dStr); err == nil && d > 0 && d <= 3650 {
+			days = d
+		}
+	}
+
+	dbPath := getDatabasePath()
+	if dbPath == "" {
+		respondError(w, "deletion database not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	db, err := database.NewDeletionDB(dbPath)
+	if err != nil {
+		log.Printf("[GetBytesFreedByOwnerHandler] Failed to open database: %v", err)
+		respondError(w, "deletion database not available", http.StatusServiceUnavailable)
+		return
+	}
+	defer db.Close()
+
+	end := time.Now()
+	start := end.AddDate(0, 0, -days)
+
+	resolver := accounts.Default()
+	var usage []database.OwnerUsage
+	if groupBy == "group" {
+		usage, err = db.GetBytesFreedByGroup(start, end)
+		for i := range usage {
+			usage[i].Name = resolver.GroupName(uint32(usage[i].ID))
+		}
+	} else {
+		usage, err = db.GetBytesFreedByOwner(start, end)
+		for i := range usage {
+			usage[i].Name = resolver.UserName(uint32(usage[i].ID))
+		}
+	}
+	if err != nil {
+		log.Printf("[GetBytesFreedByOwnerHandler] Database query error: %v", err)
+		respondError(w, fmt.Sprintf("failed to query database: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if usage == nil {
+		usage = []database.OwnerUsage{}
+	}
+
+	response := OwnerUsageResponse{
+		GroupBy: groupBy,
+		Days:    days,
+		Start:   start,
+		End:     end,
+		Owners:  usage,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,182 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"storage-sage/internal/database"
+	"storage-sage/web/backend/auth"
+	"storage-sage/web/backend/middleware"
+)
+
+// ReprieveRequest is the body of POST /api/v1/deletions/reprieves
+type ReprieveRequest struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+	Hours  int    `json:"hours"` // Expire after this many hours (0 = until lifted)
+}
+
+// GetPendingDeletionsHandler handles GET /api/v1/deletions/pending
+// Query parameters: limit=N (default 500)
+func GetPendingDeletionsHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok || !auth.HasPermission(claims.Roles, auth.PermissionViewLogs) {
+		respondError(w, "unauthorized", http.StatusForbidden)
+		return
+	}
+
+	limit := 500
+	if lStr := r.URL.Query().Get("limit"); lStr != "" {
+		if l, err := strconv.Atoi(lStr); err == nil && l > 0 && l <= 10000 {
+			limit = l
+		}
+	}
+
+	db, ok := openDeletionDB(w, "GetPendingDeletionsHandler")
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	pending, err := db.GetPendingDeletions(limit)
+	if err != nil {
+		log.Printf("[GetPendingDeletionsHandler] Database query error: %v", err)
+		respondError(w, fmt.Sprintf("failed to query database: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if pending == nil {
+		pending = []database.PendingDeletion{}
+	}
+	respondJSON(w, map[string]interface{}{"pending": pending}, http.StatusOK)
+}
+
+// GetReprievesHandler handles GET /api/v1/deletions/reprieves
+func GetReprievesHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok || !auth.HasPermission(claims.Roles, auth.PermissionViewLogs) {
+		respondError(w, "unauthorized", http.StatusForbidden)
+		return
+	}
+
+	db, ok := openDeletionDB(w, "GetReprievesHandler")
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	reprieves, err := db.GetReprieves(time.Now())
+	if err != nil {
+		log.Printf("[GetReprievesHandler] Database query error: %v", err)
+		respondError(w, fmt.Sprintf("failed to query database: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if reprieves == nil {
+		reprieves = []database.Reprieve{}
+	}
+	respondJSON(w, map[string]interface{}{"reprieves": reprieves}, http.StatusOK)
+}
+
+// AddReprieveHandler handles POST /api/v1/deletions/reprieves.
+// The daemon reads reprieves every cycle, so no reload is needed.
+func AddReprieveHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok || !auth.HasPermission(claims.Roles, auth.PermissionReprieve) {
+		respondError(w, "unauthorized", http.StatusForbidden)
+		return
+	}
+
+	var req ReprieveRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		respondError(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if !filepath.IsAbs(req.Path) {
+		respondError(w, "path must be absolute", http.StatusBadRequest)
+		return
+	}
+	if req.Hours < 0 {
+		respondError(w, "hours cannot be negative", http.StatusBadRequest)
+		return
+	}
+
+	db, ok := openDeletionDB(w, "AddReprieveHandler")
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	reprieve := database.Reprieve{Path: req.Path, Reason: req.Reason, ReprievedBy: claims.Username}
+	if req.Hours > 0 {
+		expires := time.Now().Add(time.Duration(req.Hours) * time.Hour)
+		reprieve.ExpiresAt = &expires
+	}
+	if err := db.AddReprieve(reprieve); err != nil {
+		log.Printf("[AddReprieveHandler] ERROR: Failed to reprieve %s: %v", req.Path, err)
+		respondError(w, fmt.Sprintf("failed to reprieve: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[AddReprieveHandler] %s reprieved %s hours=%d reason=%q", claims.Username, req.Path, req.Hours, req.Reason)
+	respondJSON(w, map[string]interface{}{
+		"message": "reprieved",
+		"path":    filepath.Clean(req.Path),
+	}, http.StatusOK)
+}
+
+// RemoveReprieveHandler handles DELETE /api/v1/deletions/reprieves?path=...
+func RemoveReprieveHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r)
+	if !ok || !auth.HasPermission(claims.Roles, auth.PermissionReprieve) {
+		respondError(w, "unauthorized", http.StatusForbidden)
+		return
+	}
+
+	path := r.URL.Query().Get("path")
+	if !filepath.IsAbs(path) {
+		respondError(w, "path must be absolute", http.StatusBadRequest)
+		return
+	}
+
+	db, ok := openDeletionDB(w, "RemoveReprieveHandler")
+	if !ok {
+		return
+	}
+	defer db.Close()
+
+	removed, err := db.RemoveReprieve(path)
+	if err != nil {
+		log.Printf("[RemoveReprieveHandler] ERROR: Failed to lift reprieve on %s: %v", path, err)
+		respondError(w, fmt.Sprintf("failed to lift reprieve: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if !removed {
+		respondError(w, "no reprieve on path", http.StatusNotFound)
+		return
+	}
+
+	log.Printf("[RemoveReprieveHandler] %s lifted reprieve on %s", claims.Username, path)
+	respondJSON(w, map[string]interface{}{"message": "reprieve lifted", "path": filepath.Clean(path)}, http.StatusOK)
+}
+
+// openDeletionDB opens the daemon's deletion database, writing a 503 when it is unavailable
+func openDeletionDB(w http.ResponseWriter, handler string) (*database.DeletionDB, bool) {
+	dbPath := getDatabasePath()
+	if dbPath == "" {
+		respondError(w, "deletion database not available", http.StatusServiceUnavailable)
+		return nil, false
+	}
+	db, err := database.NewDeletionDB(dbPath)
+	if err != nil {
+		log.Printf("[%s] Failed to open database: %v", handler, err)
+		respondError(w, "deletion database not available", http.StatusServiceUnavailable)
+		return nil, false
+	}
+	return db, true
+}
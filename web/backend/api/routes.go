@@ -1,7 +1,9 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -10,6 +12,7 @@ import (
 	"time"
 
 	"storage-sage/internal/config"
+	"storage-sage/internal/control"
 	"storage-sage/web/backend/auth"
 	"storage-sage/web/backend/middleware"
 
@@ -200,20 +203,14 @@ func UpdateConfigHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Trigger config reload on daemon via HTTP endpoint
-	daemonURL := os.Getenv("DAEMON_METRICS_URL")
-	if daemonURL == "" {
-		daemonURL = "http://storage-sage-daemon:9090"
-	}
-
-	reloadURL := daemonURL + "/reload"
-	log.Printf("[UpdateConfigHandler] Triggering config reload on daemon: %s", reloadURL)
-
-	client := &http.Client{
-		Timeout: 5 * time.Second,
+	// Trigger config reload through the daemon's authenticated control API
+	client, err := daemonControlClient()
+	if err == nil {
+		log.Printf("[UpdateConfigHandler] Triggering config reload on daemon")
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		err = client.Reload(ctx)
+		cancel()
 	}
-
-	resp, err := client.Post(reloadURL, "application/json", nil)
 	if err != nil {
 		log.Printf("[UpdateConfigHandler] WARNING: Failed to trigger reload: %v (config saved but daemon may need manual restart)", err)
 		// Don't fail the request - config is saved, daemon will pick it up on next restart
@@ -223,16 +220,6 @@ func UpdateConfigHandler(w http.ResponseWriter, r *http.Request) {
 		}, http.StatusOK)
 		return
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("[UpdateConfigHandler] WARNING: Daemon reload returned non-OK status: %d", resp.StatusCode)
-		respondJSON(w, map[string]string{
-			"message": "config updated successfully (daemon reload may have failed)",
-			"warning": fmt.Sprintf("daemon reload returned status %d", resp.StatusCode),
-		}, http.StatusOK)
-		return
-	}
 
 	log.Printf("[UpdateConfigHandler] Successfully saved config and reloaded daemon")
 	respondJSON(w, map[string]string{"message": "config updated and daemon reloaded successfully"}, http.StatusOK)
@@ -371,34 +358,39 @@ func TriggerCleanupHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: 10 * time.Second, // Increased from 5 to 10 seconds
-	}
-
-	// Trigger cleanup via HTTP endpoint on daemon
-	daemonURL := os.Getenv("DAEMON_METRICS_URL")
-	if daemonURL == "" {
-		daemonURL = "http://storage-sage-daemon:9090"
+	// Optional body scopes the cycle: {"rules": ["/data/volume1"], "dry_run": true}
+	var req control.TriggerRequest
+	if err := decodeOptionalJSON(r, &req); err != nil {
+		respondError(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		return
 	}
 
-	triggerURL := daemonURL + "/trigger"
-	log.Printf("[TriggerCleanupHandler] Triggering cleanup on daemon: %s", triggerURL)
-	
-	resp, err := client.Post(triggerURL, "application/json", nil)
+	// Trigger cleanup through the daemon's authenticated control API
+	client, err := daemonControlClient()
 	if err != nil {
-		log.Printf("[TriggerCleanupHandler] ERROR: Failed to trigger cleanup: %v", err)
+		log.Printf("[TriggerCleanupHandler] ERROR: control client: %v", err)
 		respondError(w, fmt.Sprintf("failed to trigger cleanup: %v", err), http.StatusInternalServerError)
 		return
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("[TriggerCleanupHandler] ERROR: Daemon returned non-OK status: %d", resp.StatusCode)
-		respondError(w, fmt.Sprintf("daemon returned non-OK status: %d", resp.StatusCode), http.StatusBadGateway)
+	log.Printf("[TriggerCleanupHandler] Triggering cleanup on daemon: rules=%v dry_run=%t", req.Rules, req.DryRun)
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	if err := client.Trigger(ctx, req); err != nil {
+		log.Printf("[TriggerCleanupHandler] ERROR: Failed to trigger cleanup: %v", err)
+		switch {
+		case errors.Is(err, control.ErrPaused):
+			respondError(w, "cleanup is paused on the daemon (a dry run is still allowed)", http.StatusConflict)
+		case errors.Is(err, control.ErrUnknownRule):
+			respondError(w, "unknown rule", http.StatusNotFound)
+		case errors.Is(err, control.ErrBusy):
+			respondError(w, "a cleanup cycle is already queued", http.StatusServiceUnavailable)
+		default:
+			respondError(w, fmt.Sprintf("failed to trigger cleanup: %v", err), http.StatusBadGateway)
+		}
 		return
 	}
-	
+
 	log.Printf("[TriggerCleanupHandler] Successfully triggered cleanup on daemon")
 
 	respondJSON(w, map[string]string{
@@ -407,7 +399,7 @@ func TriggerCleanupHandler(w http.ResponseWriter, r *http.Request) {
 	}, http.StatusOK)
 }
 
-// GetCleanupStatusHandler returns cleanup status
+// GetCleanupStatusHandler returns the daemon's cleanup status, including paused rules
 func GetCleanupStatusHandler(w http.ResponseWriter, r *http.Request) {
 	claims, ok := middleware.GetClaims(r)
 	if !ok || !auth.HasPermission(claims.Roles, auth.PermissionViewMetrics) {
@@ -415,12 +407,32 @@ func GetCleanupStatusHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	client, err := daemonControlClient()
+	if err != nil {
+		respondError(w, fmt.Sprintf("failed to get cleanup status: %v", err), http.StatusInternalServerError)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	st, err := client.Status(ctx)
+	if err != nil {
+		log.Printf("[GetCleanupStatusHandler] ERROR: Failed to get daemon status: %v", err)
+		respondError(w, fmt.Sprintf("failed to get cleanup status: %v", err), http.StatusBadGateway)
+		return
+	}
+
 	status := map[string]interface{}{
-		"running":      false,
-		"lastRun":      time.Now().Add(-15 * time.Minute),
-		"nextRun":      time.Now().Add(15 * time.Minute),
-		"filesDeleted": 0,
-		"bytesFreed":   0,
+		"running":     st.Running,
+		"paused":      st.Paused,
+		"pauseReason": st.PauseReason,
+		"dryRun":      st.DryRun,
+		"lastRun":     st.LastRun,
+		"nextRun":     st.NextRun,
+		"lastError":   st.LastError,
+		"rules":       st.Rules,
+
+		"overrideArmed": st.OverrideArmed,
+		"overrideBy":    st.OverrideBy,
 	}
 
 	respondJSON(w, status, http.StatusOK)
@@ -17,11 +17,14 @@ const (
 
 // Permission definitions
 const (
-	PermissionViewConfig     = "config:read"
-	PermissionEditConfig     = "config:write"
-	PermissionViewMetrics    = "metrics:read"
-	PermissionTriggerCleanup = "cleanup:trigger"
-	PermissionViewLogs       = "logs:read"
+	PermissionViewConfig      = "config:read"
+	PermissionEditConfig      = "config:write"
+	PermissionViewMetrics     = "metrics:read"
+	PermissionTriggerCleanup  = "cleanup:trigger"
+	PermissionOverrideCleanup = "cleanup:override" // Let one cycle exceed the blast-radius limits
+	PermissionViewLogs        = "logs:read"
+	PermissionReprieve        = "deletions:reprieve" // Exempt files from pending deletion
+	PermissionManageHolds     = "holds:manage"       // Place and release legal holds
 )
 
 // RolePermissions maps roles to their allowed permissions
@@ -31,13 +34,18 @@ var RolePermissions = map[string][]string{
 		PermissionEditConfig,
 		PermissionViewMetrics,
 		PermissionTriggerCleanup,
+		PermissionOverrideCleanup,
 		PermissionViewLogs,
+		PermissionReprieve,
+		PermissionManageHolds,
 	},
 	RoleOperator: {
 		PermissionViewConfig,
 		PermissionViewMetrics,
 		PermissionTriggerCleanup,
+		PermissionOverrideCleanup,
 		PermissionViewLogs,
+		PermissionReprieve,
 	},
 	RoleViewer: {
 		PermissionViewConfig,
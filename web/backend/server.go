@@ -117,9 +117,23 @@ func main() {
 	// Cleanup control
 	protected.HandleFunc("/cleanup/trigger", api.TriggerCleanupHandler).Methods("POST")
 	protected.HandleFunc("/cleanup/status", api.GetCleanupStatusHandler).Methods("GET")
+	protected.HandleFunc("/cleanup/pause", api.PauseCleanupHandler).Methods("POST")
+	protected.HandleFunc("/cleanup/override", api.OverrideCleanupHandler).Methods("POST")
+	protected.HandleFunc("/cleanup/resume", api.ResumeCleanupHandler).Methods("POST")
 
 	// Logs endpoints
 	protected.HandleFunc("/deletions/log", api.GetDeletionsLogHandler).Methods("GET")
+	protected.HandleFunc("/deletions/by-owner", api.GetBytesFreedByOwnerHandler).Methods("GET")
+	protected.HandleFunc("/deletions/pending", api.GetPendingDeletionsHandler).Methods("GET")
+	protected.HandleFunc("/deletions/reprieves", api.GetReprievesHandler).Methods("GET")
+	protected.HandleFunc("/deletions/reprieves", api.AddReprieveHandler).Methods("POST")
+	protected.HandleFunc("/deletions/reprieves", api.RemoveReprieveHandler).Methods("DELETE")
+
+	// Legal hold endpoints
+	protected.HandleFunc("/holds", api.GetHoldsHandler).Methods("GET")
+	protected.HandleFunc("/holds", api.PlaceHoldHandler).Methods("POST")
+	protected.HandleFunc("/holds", api.ReleaseHoldHandler).Methods("DELETE")
+	protected.HandleFunc("/holds/audit", api.GetHoldAuditHandler).Methods("GET")
 
 	// WebSocket endpoint for live metrics
 	protected.HandleFunc("/ws/metrics", websocket.HandleMetricsWebSocket(hub)).Methods("GET")